@@ -29,15 +29,24 @@ func main() {
 	root.AddCommand(cli.NewBuildCmd())
 	root.AddCommand(cli.NewDeployCmd())
 	root.AddCommand(cli.NewStatusCmd())
+	root.AddCommand(cli.NewDescribeCmd())
+	root.AddCommand(cli.NewHealthCmd())
 
 	// Operations commands
 	root.AddCommand(cli.NewRunCmd())
+	root.AddCommand(cli.NewRunsCmd())
+	root.AddCommand(cli.NewHistoryCmd())
+	root.AddCommand(cli.NewRollbackCmd())
+	root.AddCommand(cli.NewPromoteCmd())
 	root.AddCommand(cli.NewLogsCmd())
 	root.AddCommand(cli.NewListCmd())
 	root.AddCommand(cli.NewUndeployCmd())
+	root.AddCommand(cli.NewSuspendCmd())
+	root.AddCommand(cli.NewResumeCmd())
 
 	// Cluster commands
 	root.AddCommand(cli.NewClusterCmd())
+	root.AddCommand(cli.NewMCPCmd())
 
 	// Configuration commands
 	root.AddCommand(cli.NewConfigureCmd())
@@ -172,6 +172,9 @@ async function main() {
     } else {
       try {
         const { startNATSTriggers } = await import("./triggers/nats.ts");
+        // Set by the builder only when the KEDA ScaledObject is deployed:
+        // consume through the durable consumers it scales on
+        const stream = Deno.env.get("TENTACULAR_JETSTREAM_STREAM");
         natsHandle = await startNATSTriggers({
           url: natsUrl,
           token: natsToken,
@@ -182,6 +185,7 @@ async function main() {
           timeoutMs,
           maxRetries: spec.config?.retries ?? 0,
           sink,
+          jetstream: stream ? { stream, workflow: spec.name } : undefined,
         });
       } catch (err) {
         console.error("Failed to start NATS triggers:", err);
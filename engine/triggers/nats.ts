@@ -2,6 +2,13 @@
  * NATS trigger manager for queue-type triggers.
  * Dynamically imports @nats-io/transport-deno so the library is only loaded
  * when queue triggers are actually configured.
+ *
+ * Without autoscaling each subject is a core NATS subscription. When the
+ * workflow is deployed with a KEDA ScaledObject (TENTACULAR_JETSTREAM_STREAM
+ * is set), each subject is consumed through the durable JetStream pull
+ * consumer <workflow>-<subject> that the KEDA scaler reads. Each replica
+ * pulls one message at a time, so unfetched messages stay pending on the
+ * consumer and count as lag.
  */
 
 import type { CompiledDAG, Context, Trigger } from "../types.ts";
@@ -29,8 +36,27 @@ export interface NATSTriggerOptions {
   maxRetries?: number;
   /** Telemetry sink for runtime observability (default: NoopSink) */
   sink?: TelemetrySink;
+  /** Consume through durable JetStream consumers (set when a ScaledObject is deployed) */
+  jetstream?: JetStreamOptions;
 }
 
+export interface JetStreamOptions {
+  /** Stream capturing the trigger subjects */
+  stream: string;
+  /** Workflow name, the prefix of each consumer name */
+  workflow: string;
+}
+
+/** Grace added to the execution timeout before JetStream redelivers a message */
+const ACK_WAIT_GRACE_MS = 30_000;
+const DEFAULT_TIMEOUT_MS = 30_000;
+/** How long a pull waits on the server for a message before it is renewed */
+const PULL_EXPIRES_MS = 30_000;
+/** Deliveries of a failing message before it is dropped */
+export const MAX_DELIVER = 5;
+/** Delay before JetStream redelivers a message whose run failed */
+const NAK_DELAY_MS = 10_000;
+
 export interface NATSTriggerHandle {
   /** Drain all subscriptions and close the NATS connection */
   close(): Promise<void>;
@@ -53,9 +79,65 @@ export function validateOptions(opts: Partial<NATSTriggerOptions>): string | nul
   if (missing.length > 0) {
     return "All queue triggers must have a subject";
   }
+  if (opts.jetstream && !opts.jetstream.stream) {
+    return "deployment.autoscaling.jetstream.stream is required";
+  }
   return null;
 }
 
+/**
+ * Name of the durable consumer for a queue trigger subject:
+ * <workflow>-<subject>, with "." replaced by "-" and the wildcards "*" and
+ * ">" by "any" and "all". Must match JetStreamConsumerName in pkg/k8s/keda.go,
+ * which points the KEDA scaler at the same consumer.
+ */
+export function consumerName(workflow: string, subject: string): string {
+  const token = subject.replaceAll(".", "-").replaceAll("*", "any").replaceAll(">", "all")
+    .replaceAll(" ", "-");
+  return `${workflow}-${token.toLowerCase()}`;
+}
+
+/**
+ * Request for $JS.API.CONSUMER.DURABLE.CREATE.<stream>.<durable>: a durable
+ * pull consumer filtered to the subject, with explicit acks. Messages not
+ * acked within the execution timeout plus a grace period are redelivered, up
+ * to MAX_DELIVER deliveries.
+ */
+export function consumerConfig(
+  stream: string,
+  durable: string,
+  subject: string,
+  timeoutMs: number,
+): Record<string, unknown> {
+  return {
+    stream_name: stream,
+    config: {
+      durable_name: durable,
+      filter_subject: subject,
+      ack_policy: "explicit",
+      ack_wait: (timeoutMs + ACK_WAIT_GRACE_MS) * 1_000_000, // nanoseconds
+      max_deliver: MAX_DELIVER,
+    },
+  };
+}
+
+/** Request for $JS.API.CONSUMER.MSG.NEXT.<stream>.<durable>: one message */
+export function pullRequest(): Record<string, unknown> {
+  return { batch: 1, expires: PULL_EXPIRES_MS * 1_000_000 };
+}
+
+/**
+ * Delivery count of a JetStream message, read from its ack subject:
+ * $JS.ACK.<stream>.<consumer>.<delivered>.<sseq>.<cseq>.<ts>.<pending>, or
+ * with a domain and account hash after $JS.ACK. Returns 1 when unknown.
+ */
+export function deliveryCount(reply: string | undefined): number {
+  const tokens = (reply ?? "").split(".");
+  const index = tokens.length === 9 ? 4 : tokens.length >= 11 ? 6 : -1;
+  const n = index >= 0 ? Number(tokens[index]) : NaN;
+  return Number.isInteger(n) && n > 0 ? n : 1;
+}
+
 /**
  * Start NATS trigger subscriptions for all queue triggers.
  * Dynamically imports the NATS library to avoid loading it when not needed.
@@ -85,10 +167,113 @@ export async function startNATSTriggers(opts: NATSTriggerOptions): Promise<NATST
     sink,
   });
 
-  // Subscribe to each queue trigger's subject
+  // Parse a message payload as JSON input, tagged with its trigger
+  const toInput = (trigger: Trigger, payload: Uint8Array): unknown => {
+    let input: unknown = {};
+    if (payload && payload.length > 0) {
+      const text = new TextDecoder().decode(payload);
+      try {
+        input = JSON.parse(text);
+      } catch {
+        // Non-JSON payload — wrap as { data: "<raw>" }
+        input = { data: text };
+      }
+    }
+    if (typeof input === "object" && input !== null) {
+      (input as Record<string, unknown>).trigger = trigger.name ?? trigger.subject;
+    }
+    return input;
+  };
+
+  const execute = async (trigger: Trigger, payload: Uint8Array) => {
+    console.log(`NATS message on ${trigger.subject} — executing workflow`);
+    sink.record({
+      type: "nats-message",
+      timestamp: Date.now(),
+      metadata: { subject: trigger.subject },
+    });
+    const input = toInput(trigger, payload);
+    const result = await executor.execute(opts.graph, opts.runner, opts.ctx, input);
+    if (!result.success) {
+      console.error(`NATS-triggered execution failed on ${trigger.subject}:`, result.errors);
+    }
+    return result;
+  };
+
+  let closing = false;
   for (const trigger of opts.triggers) {
     if (!trigger.subject) continue;
 
+    if (opts.jetstream) {
+      // Durable consumer shared by all replicas; its lag drives KEDA scaling
+      const durable = consumerName(opts.jetstream.workflow, trigger.subject);
+      const timeoutMs = opts.timeoutMs ?? DEFAULT_TIMEOUT_MS;
+      const config = consumerConfig(opts.jetstream.stream, durable, trigger.subject, timeoutMs);
+      let createError: string | undefined;
+      try {
+        const reply = await nc.request(
+          `$JS.API.CONSUMER.DURABLE.CREATE.${opts.jetstream.stream}.${durable}`,
+          new TextEncoder().encode(JSON.stringify(config)),
+          { timeout: 5000 },
+        );
+        const created = JSON.parse(new TextDecoder().decode(reply.data)) as {
+          error?: { description?: string };
+        };
+        createError = created.error ? created.error.description ?? "unknown error" : undefined;
+      } catch (err) {
+        createError = String(err);
+      }
+      if (createError !== undefined) {
+        await nc.close();
+        const stream = opts.jetstream.stream;
+        throw new Error(`creating JetStream consumer ${durable} on ${stream}: ${createError}`);
+      }
+
+      console.log(`  NATS consuming ${trigger.subject} via JetStream consumer ${durable}`);
+      const next = `$JS.API.CONSUMER.MSG.NEXT.${opts.jetstream.stream}.${durable}`;
+      const nextRequest = new TextEncoder().encode(JSON.stringify(pullRequest()));
+
+      // Pull one message at a time: the rest stay pending on the consumer,
+      // where KEDA reads them as lag and other replicas can take them.
+      (async () => {
+        while (!closing) {
+          let msg;
+          try {
+            msg = await nc.request(next, nextRequest, { timeout: PULL_EXPIRES_MS + 5000 });
+          } catch (err) {
+            if (closing) break;
+            console.error(`NATS pull on ${durable} failed:`, err);
+            await new Promise((r) => setTimeout(r, 1000));
+            continue;
+          }
+          // A status (404 no messages, 408 pull expired) delivers nothing
+          if (msg.headers?.code) continue;
+
+          let ok = false;
+          try {
+            ok = (await execute(trigger, msg.data)).success;
+          } catch (err) {
+            console.error(`Error processing NATS message on ${trigger.subject}:`, err);
+          }
+          if (ok) {
+            msg.respond(new TextEncoder().encode("+ACK"));
+            continue;
+          }
+          const delivered = deliveryCount(msg.reply);
+          if (delivered >= MAX_DELIVER) {
+            console.error(
+              `Dropping NATS message on ${trigger.subject} after ${delivered} failed deliveries`,
+            );
+            msg.respond(new TextEncoder().encode("+TERM"));
+          } else {
+            const nak = `-NAK ${JSON.stringify({ delay: NAK_DELAY_MS * 1_000_000 })}`;
+            msg.respond(new TextEncoder().encode(nak));
+          }
+        }
+      })();
+      continue;
+    }
+
     const sub = nc.subscribe(trigger.subject);
     console.log(`  NATS subscribed to: ${trigger.subject}`);
 
@@ -96,41 +281,13 @@ export async function startNATSTriggers(opts: NATSTriggerOptions): Promise<NATST
     (async () => {
       for await (const msg of sub) {
         try {
-          // Parse message payload as JSON input
-          let input: unknown = {};
-          const payload = msg.data;
-          if (payload && payload.length > 0) {
-            try {
-              const text = new TextDecoder().decode(payload);
-              input = JSON.parse(text);
-            } catch {
-              // Non-JSON payload — wrap as { data: "<raw>" }
-              input = { data: new TextDecoder().decode(payload) };
-            }
-          }
-
-          // Add trigger metadata
-          if (typeof input === "object" && input !== null) {
-            (input as Record<string, unknown>).trigger = trigger.name ?? trigger.subject;
-          }
-
-          console.log(`NATS message on ${trigger.subject} — executing workflow`);
-          sink.record({
-            type: "nats-message",
-            timestamp: Date.now(),
-            metadata: { subject: trigger.subject },
-          });
-          const result = await executor.execute(opts.graph, opts.runner, opts.ctx, input);
+          const result = await execute(trigger, msg.data);
 
           // Request-reply: send result back if reply subject is set
           if (msg.reply) {
             const resultBytes = new TextEncoder().encode(JSON.stringify(result));
             msg.respond(resultBytes);
           }
-
-          if (!result.success) {
-            console.error(`NATS-triggered execution failed on ${trigger.subject}:`, result.errors);
-          }
         } catch (err) {
           console.error(`Error processing NATS message on ${trigger.subject}:`, err);
         }
@@ -140,6 +297,7 @@ export async function startNATSTriggers(opts: NATSTriggerOptions): Promise<NATST
 
   return {
     async close() {
+      closing = true;
       console.log("NATS draining subscriptions...");
       await nc.drain();
       console.log("NATS connection closed");
@@ -1,5 +1,11 @@
 import { assertEquals } from "std/assert";
-import { validateOptions } from "./nats.ts";
+import {
+  consumerConfig,
+  consumerName,
+  deliveryCount,
+  pullRequest,
+  validateOptions,
+} from "./nats.ts";
 import type { NATSTriggerOptions } from "./nats.ts";
 
 Deno.test("validateOptions: missing URL returns error", () => {
@@ -59,6 +65,51 @@ Deno.test("validateOptions: multiple triggers all valid returns null", () => {
   assertEquals(result, null);
 });
 
+Deno.test("validateOptions: jetstream without a stream returns error", () => {
+  const result = validateOptions({
+    url: "nats://localhost:4222",
+    token: "abc",
+    triggers: [{ type: "queue", subject: "events.test" }],
+    jetstream: { stream: "", workflow: "wf" },
+  } as Partial<NATSTriggerOptions>);
+  assertEquals(result, "deployment.autoscaling.jetstream.stream is required");
+});
+
+Deno.test("consumerName: matches the consumer the KEDA scaler reads", () => {
+  // Same cases as TestJetStreamConsumerName in pkg/k8s/keda_test.go
+  assertEquals(consumerName("orders", "orders.created"), "orders-orders-created");
+  assertEquals(consumerName("orders", "events.*.push"), "orders-events-any-push");
+  assertEquals(consumerName("orders", "Events.>"), "orders-events-all");
+});
+
+Deno.test("consumerConfig: durable pull consumer with bounded redelivery", () => {
+  const req = consumerConfig("EVENTS", "wf-events-push", "events.push", 60_000);
+  assertEquals(req, {
+    stream_name: "EVENTS",
+    config: {
+      durable_name: "wf-events-push",
+      filter_subject: "events.push",
+      ack_policy: "explicit",
+      ack_wait: 90_000_000_000,
+      max_deliver: 5,
+    },
+  });
+});
+
+Deno.test("pullRequest: fetches one message at a time", () => {
+  assertEquals(pullRequest(), { batch: 1, expires: 30_000_000_000 });
+});
+
+Deno.test("deliveryCount: reads the delivered count from the ack subject", () => {
+  assertEquals(deliveryCount("$JS.ACK.EVENTS.wf-events-push.3.10.10.1700000000.0"), 3);
+  assertEquals(
+    deliveryCount("$JS.ACK.hub.ACCHASH.EVENTS.wf-events-push.4.10.10.1700000000.0.rand"),
+    4,
+  );
+  assertEquals(deliveryCount(undefined), 1);
+  assertEquals(deliveryCount("_INBOX.abc"), 1);
+});
+
 // Integration tests — only run when NATS_TEST=true
 const natsTestEnabled = Deno.env.get("NATS_TEST") === "true";
 
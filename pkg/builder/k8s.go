@@ -39,6 +39,19 @@ type DeployOptions struct {
 	ImagePullPolicy  string          // If empty, defaults to "Always"
 	ModuleProxyURL   string          // If set, used to scope Deno net flags for jsr/npm proxy host
 	WorkflowDir      string          // If set, scan nodes/ for extra .ts files not declared as DAG nodes (shared modules)
+	Autoscaled       bool            // If set, omit replicas so a KEDA ScaledObject owns the replica count
+}
+
+// JetStreamStreamEnv names the engine environment variable holding the
+// JetStream stream of an autoscaled workflow. The engine consumes queue
+// triggers through durable JetStream consumers only when it is set.
+const JetStreamStreamEnv = "TENTACULAR_JETSTREAM_STREAM"
+
+// EngineResources are the resource requests and limits applied to the engine
+// container of every workflow Deployment.
+var EngineResources = spec.ResourceSpec{
+	Requests: spec.ResourceValues{CPU: "100m", Memory: "64Mi"},
+	Limits:   spec.ResourceValues{CPU: "500m", Memory: "256Mi"},
 }
 
 // GenerateCodeConfigMap produces a ConfigMap containing workflow code (workflow.yaml + nodes/*.ts).
@@ -481,6 +494,19 @@ func GenerateK8sManifests(wf *spec.Workflow, imageTag, namespace string, opts De
 		metaAnnotations = opts.Metadata.Annotations
 	}
 
+	// Replica count (omitted when a ScaledObject scales the Deployment)
+	replicasLine := "  replicas: 1\n"
+	if opts.Autoscaled {
+		replicasLine = ""
+	}
+
+	// With a ScaledObject, the engine consumes queue triggers through the
+	// durable JetStream consumers it scales on.
+	jetStreamEnv := ""
+	if a := wf.Deployment.Autoscaling; opts.Autoscaled && a != nil {
+		jetStreamEnv = fmt.Sprintf("            - name: %s\n              value: %q\n", JetStreamStreamEnv, a.JetStream.Stream)
+	}
+
 	// Deployment with security hardening
 	deployment := fmt.Sprintf(`apiVersion: apps/v1
 kind: Deployment
@@ -490,8 +516,7 @@ metadata:
   labels:
     %s
 %sspec:
-  replicas: 1
-  strategy:
+%s  strategy:
     type: Recreate
   selector:
     matchLabels:
@@ -524,7 +549,7 @@ metadata:
               value: %s
             - name: OTEL_RESOURCE_ATTRIBUTES
               value: k8s.namespace.name=%s,tentacular.enclave=%s
-          ports:
+%s          ports:
             - containerPort: 8080
               protocol: TCP
           securityContext:
@@ -556,11 +581,11 @@ metadata:
               mountPath: /tmp
 %s%s          resources:
             requests:
-              memory: "64Mi"
-              cpu: "100m"
+              memory: "%s"
+              cpu: "%s"
             limits:
-              memory: "256Mi"
-              cpu: "500m"
+              memory: "%s"
+              cpu: "%s"
 %s      volumes:
         - name: code
           configMap:
@@ -574,7 +599,7 @@ metadata:
         - name: tmp
           emptyDir:
             sizeLimit: 512Mi
-%s%s`, wf.Name, namespace, labels, buildDeployAnnotations(wf.Metadata, wf.Triggers, wf.Description, metaAnnotations), wf.Name, labels, runtimeClassLine, imageTag, imagePullPolicy, commandArgsBlock, wf.Name, namespace, namespace, engineSharedMount, importMapVolumeMount, sidecarContainersBlock, wf.Name, strings.Join(configMapItems, "\n"), wf.Name, sidecarVolumesBlock, importMapVolume)
+%s%s`, wf.Name, namespace, labels, buildDeployAnnotations(wf.Metadata, wf.Triggers, wf.Description, metaAnnotations), replicasLine, wf.Name, labels, runtimeClassLine, imageTag, imagePullPolicy, commandArgsBlock, wf.Name, namespace, namespace, jetStreamEnv, engineSharedMount, importMapVolumeMount, EngineResources.Requests.Memory, EngineResources.Requests.CPU, EngineResources.Limits.Memory, EngineResources.Limits.CPU, sidecarContainersBlock, wf.Name, strings.Join(configMapItems, "\n"), wf.Name, sidecarVolumesBlock, importMapVolume)
 
 	manifests = append(manifests, Manifest{
 		Kind: "Deployment", Name: wf.Name, Content: deployment,
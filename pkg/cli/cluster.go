@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
 func NewClusterCmd() *cobra.Command {
@@ -25,6 +29,13 @@ func NewClusterCmd() *cobra.Command {
 	return cluster
 }
 
+// clusterCheckJSON is the -o json output of cluster check: the preflight
+// results plus the server compatibility report.
+type clusterCheckJSON struct {
+	*mcp.ClusterPreflightResult
+	Server *mcp.Compatibility `json:"server,omitempty"`
+}
+
 func runClusterCheck(cmd *cobra.Command, args []string) error {
 	namespace := resolveNamespace(cmd, "")
 	output, _ := cmd.Flags().GetString("output")
@@ -34,6 +45,28 @@ func runClusterCheck(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Capability negotiation: fail early with a clear message when the server
+	// predates tools this CLI depends on.
+	compat, err := serverCompatibility(cmd, mcpClient)
+	if err != nil {
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("connecting to MCP server: %w\n  hint: %s", err, hint)
+		}
+		return fmt.Errorf("connecting to MCP server: %w", err)
+	}
+	if !compat.Compatible {
+		if output == "json" {
+			data, jsonErr := json.Marshal(clusterCheckJSON{Server: compat})
+			if jsonErr != nil {
+				return fmt.Errorf("marshaling JSON: %w", jsonErr)
+			}
+			fmt.Println(string(data))
+		} else {
+			printServerCompat(os.Stdout, compat)
+		}
+		return fmt.Errorf("MCP server is incompatible with this tntc: %s", strings.Join(compat.Problems, "; "))
+	}
+
 	preflightResult, err := mcpClient.ClusterPreflight(cmd.Context(), namespace)
 	if err != nil {
 		if hint := mcpErrorHint(err); hint != "" {
@@ -44,7 +77,7 @@ func runClusterCheck(cmd *cobra.Command, args []string) error {
 
 	// JSON output
 	if output == "json" {
-		data, jsonErr := json.Marshal(preflightResult)
+		data, jsonErr := json.Marshal(clusterCheckJSON{ClusterPreflightResult: preflightResult, Server: compat})
 		if jsonErr != nil {
 			return fmt.Errorf("marshaling JSON: %w", jsonErr)
 		}
@@ -56,6 +89,7 @@ func runClusterCheck(cmd *cobra.Command, args []string) error {
 	}
 
 	// Text output
+	printServerCompat(os.Stdout, compat)
 	for _, r := range preflightResult.Results {
 		icon := "\u2713"
 		if !r.Passed {
@@ -3,10 +3,13 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
 // NewWhoamiCmd creates the "whoami" cobra command.
@@ -21,15 +24,17 @@ func NewWhoamiCmd() *cobra.Command {
 
 // whoamiResult is the structured output for JSON mode.
 type whoamiResult struct {
-	Email     string   `json:"email"`
-	Name      string   `json:"name,omitempty"`
-	Subject   string   `json:"subject"`
-	Issuer    string   `json:"issuer,omitempty"`
-	Provider  string   `json:"provider,omitempty"`
-	Cluster   string   `json:"cluster"`
-	ExpiresAt string   `json:"expires_at,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
-	Expired   bool     `json:"expired"`
+	Server    *mcp.Compatibility `json:"server,omitempty"`
+	Email     string             `json:"email"`
+	Name      string             `json:"name,omitempty"`
+	Subject   string             `json:"subject"`
+	Issuer    string             `json:"issuer,omitempty"`
+	Provider  string             `json:"provider,omitempty"`
+	Cluster   string             `json:"cluster"`
+	ExpiresAt string             `json:"expires_at,omitempty"`
+	ServerErr string             `json:"server_error,omitempty"`
+	Groups    []string           `json:"groups,omitempty"`
+	Expired   bool               `json:"expired"`
 }
 
 func runWhoami(cmd *cobra.Command, args []string) error {
@@ -55,15 +60,19 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated for cluster %q; run 'tntc login -c %s'", clusterName, clusterName)
 	}
 
+	server, serverErr := whoamiServer(cmd)
+
 	// Decode claims from access token for fresh info
 	claims, err := DecodeJWTClaims(store.AccessToken)
 	if err != nil {
 		// Fall back to stored email
 		if outputFormat == "json" {
 			result := whoamiResult{
-				Email:   store.Email,
-				Cluster: clusterName,
-				Expired: store.IsExpired(),
+				Email:     store.Email,
+				Cluster:   clusterName,
+				Expired:   store.IsExpired(),
+				Server:    server,
+				ServerErr: serverErr,
 			}
 			if !store.IsExpired() {
 				result.ExpiresAt = store.ExpiresAt.Format(time.RFC3339)
@@ -77,6 +86,7 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Fprintf(cmd.OutOrStdout(), "Expires:     %s\n", store.ExpiresAt.Format(time.RFC3339))
 		}
+		printWhoamiServer(cmd.OutOrStdout(), server, serverErr)
 		return nil
 	}
 
@@ -109,6 +119,8 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 			Expired:   expired,
 			ExpiresAt: expiresAt,
 			Groups:    claims.Groups,
+			Server:    server,
+			ServerErr: serverErr,
 		}
 		return emitWhoamiJSON(cmd, result)
 	}
@@ -142,10 +154,38 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Fprintf(out, "Expires:     %s\n", expiresAt)
 	}
+	printWhoamiServer(out, server, serverErr)
 
 	return nil
 }
 
+// whoamiServer probes the configured MCP server for compatibility. It returns
+// (nil, "") when no server is configured; failures are reported, not fatal.
+func whoamiServer(cmd *cobra.Command) (*mcp.Compatibility, string) {
+	client, err := resolveMCPClient(cmd)
+	if err != nil {
+		return nil, err.Error()
+	}
+	if client == nil {
+		return nil, ""
+	}
+	defer func() { _ = client.Close() }()
+	compat, err := serverCompatibility(cmd, client)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return compat, ""
+}
+
+func printWhoamiServer(w io.Writer, server *mcp.Compatibility, serverErr string) {
+	switch {
+	case server != nil:
+		printServerCompat(w, server)
+	case serverErr != "":
+		fmt.Fprintf(w, "MCP Server:  unavailable (%s)\n", serverErr)
+	}
+}
+
 // emitWhoamiJSON marshals and writes the whoami result as JSON.
 func emitWhoamiJSON(cmd *cobra.Command, result whoamiResult) error {
 	data, err := json.Marshal(result)
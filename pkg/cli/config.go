@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -21,7 +22,17 @@ type ModuleProxyConfig struct {
 
 // MCPConfig holds MCP server connection settings.
 type MCPConfig struct {
-	Endpoint string `yaml:"endpoint,omitempty"` // e.g. http://tentacular-mcp.tentacular-system.svc.cluster.local:8080
+	Endpoint string         `yaml:"endpoint,omitempty"` // e.g. http://tentacular-mcp.tentacular-system.svc.cluster.local:8080
+	Retry    MCPRetryConfig `yaml:"retry,omitempty"`
+}
+
+// MCPRetryConfig tunes retries of MCP tool calls. Unset fields use the client
+// defaults (3 attempts, 200ms base delay, 2s max delay, budget of 2x timeout).
+type MCPRetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts,omitempty"` // 1 disables retries
+	BaseDelay   time.Duration `yaml:"base_delay,omitempty"`   // e.g. 200ms
+	MaxDelay    time.Duration `yaml:"max_delay,omitempty"`    // e.g. 2s
+	Budget      time.Duration `yaml:"budget,omitempty"`       // deadline for a call across all attempts
 }
 
 // GitStateConfig configures the git-backed state repository for tentacle source and secrets.
@@ -30,6 +41,14 @@ type GitStateConfig struct {
 	Enabled  bool   `yaml:"enabled,omitempty"`   // when true, enclave is required for scaffold init and deploy gates are enforced
 }
 
+// HealthConfig sets the thresholds `tntc health` uses to classify a workflow
+// as green, amber or red. Unset fields use the defaults in defaultHealthThresholds.
+type HealthConfig struct {
+	AmberErrorRate float64 `yaml:"amber_error_rate,omitempty"` // failed/recent executions, 0..1
+	RedErrorRate   float64 `yaml:"red_error_rate,omitempty"`   // failed/recent executions, 0..1
+	MaxInFlight    int     `yaml:"max_in_flight,omitempty"`    // more executions in flight is amber
+}
+
 // TentacularConfig holds default configuration values.
 type TentacularConfig struct {
 	Clusters       map[string]EnvironmentConfig `yaml:"clusters,omitempty"`
@@ -37,6 +56,7 @@ type TentacularConfig struct {
 	Catalog        catalog.CatalogConfig        `yaml:"catalog,omitempty"`
 	Scaffold       scaffold.ClientConfig        `yaml:"scaffold,omitempty"`
 	GitState       GitStateConfig               `yaml:"git_state,omitempty"`
+	Health         HealthConfig                 `yaml:"health,omitempty"`
 	Workspace      string                       `yaml:"workspace,omitempty"`
 	Registry       string                       `yaml:"registry,omitempty"`
 	Namespace      string                       `yaml:"namespace,omitempty"`
@@ -25,8 +25,34 @@ func NewDeployCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "deploy [dir]",
 		Short: "Deploy to Kubernetes",
-		Args:  cobra.MaximumNArgs(1),
-		RunE:  runDeploy,
+		Long: `Deploy the workflow in dir (default: the current directory).
+
+With --all, dir is an enclave directory: every workflow.yaml below it is
+validated first, then the tentacles are deployed with bounded concurrency,
+dependencies before their dependents. A failed tentacle skips only the
+tentacles that depend on it.
+
+Unless --force is set, the workflow is first deployed to and run in the
+target cluster's live-test environment (live_test in its config; by default
+the dev environment), once per configured fixture, with the output checked
+against the configured JSONPath expectations, and then removed.
+
+Each deploy holds a lock on its tentacle in the target namespace (and, with
+git-state, in the git-state repo) from the pre-deploy live test until it
+finishes, so concurrent deploys of the same tentacle fail fast and name the
+holder. Locks expire after --lock-ttl; --steal breaks a live one.
+
+A suspended tentacle (tntc suspend) is not deployed over, since the deploy
+would scale it back up; --resume resumes it first.
+
+Once applied, a deploy can be gated: --wait (readiness within --wait-timeout),
+--verify (a successful run, with --verify-input as its input) and --soak (no
+pod restarts for the window). The cluster's post_deploy config enables the
+same gates by default. When a gate fails the previously deployed revision is
+re-applied unless --no-rollback is set; the JSON result reports each gate and
+the rollback.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runDeploy,
 	}
 	cmd.Flags().String("image", "", "Base engine image (default: read from .tentacular/base-image.txt or use tentacular-engine:latest)")
 	cmd.Flags().String("cluster-registry", "", "DEPRECATED: Use --image instead")
@@ -34,9 +60,22 @@ func NewDeployCmd() *cobra.Command {
 	cmd.Flags().Bool("force", false, "Skip pre-deploy live test")
 	cmd.Flags().Bool("skip-live-test", false, "Skip pre-deploy live test (alias for --force)")
 	cmd.Flags().Bool("verify", false, "Run workflow once after deploy to verify")
-	cmd.Flags().Bool("warn", false, "Audit mode: contract violations produce warnings instead of failures")
+	cmd.Flags().String("verify-input", "", "JSON input fixture for the --verify run (implies --verify)")
+	cmd.Flags().Duration("soak", 0, "After the rollout, fail if any pod restarts within this window")
+	cmd.Flags().Bool("no-rollback", false, "Leave a deploy that fails a post-deploy gate live instead of re-applying the previous revision")
+	cmd.Flags().Bool("wait", false, "Wait for the rollout to become ready, failing on crash loops or image pull errors")
+	cmd.Flags().Duration("wait-timeout", defaultWatchTimeout, "Maximum time to --wait for the rollout")
+	cmd.Flags().Bool("diff", false, "Show a field-level diff against the live objects before applying")
+	cmd.Flags().Bool("dry-run", false, "Render and diff against the live objects without applying (implies --diff)")
+	cmd.Flags().Bool("all", false, "Deploy every tentacle found under the directory (an enclave directory)")
+	cmd.Flags().Int("concurrency", defaultDeployConcurrency, "Maximum tentacles deployed at once with --all")
+	addLockFlags(cmd)
+	addResumeFlag(cmd)
+	addInputFlag(cmd)
+	cmd.Flags().Bool("warn", false, "Audit mode: contract and cluster profile violations produce warnings instead of failures")
 	cmd.Flags().String("enclave", "", "Target enclave name (resolves to enclave namespace)")
 	cmd.Flags().Bool("no-push", false, "Skip git push step (emergency bypass; cluster state will diverge from git)")
+	addDirectFlags(cmd)
 	return cmd
 }
 
@@ -47,15 +86,21 @@ type InternalDeployOptions struct {
 	Image           string
 	RuntimeClass    string
 	ImagePullPolicy string
-	Context         string  // kubeconfig context override (bootstrap-only)
+	Context         string  // kubeconfig context for --direct deploys (reported in progress output)
 	GitMeta         GitMeta // optional git provenance; non-empty fields are injected as annotations on the Deployment
+	// Deployer, when set, is stamped on the Deployment as deployed-by annotations.
+	Deployer *deployerIdentity
+	// KEDA reports KEDA in the target cluster's profile; deployment.autoscaling
+	// only generates a ScaledObject when set.
+	KEDA bool
 }
 
 // DeployResult holds the result of a deployment.
 type DeployResult struct {
-	MCPClient    *mcp.Client
+	Client       mcp.WorkflowClient
 	WorkflowName string
 	Namespace    string
+	Manifests    []map[string]any // as applied, including any Secret
 }
 
 func runDeploy(cmd *cobra.Command, args []string) error {
@@ -71,13 +116,11 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("resolving path: %w", err)
 	}
 
-	clusterName := flagString(cmd, "cluster")
-	imageFlagValue, _ := cmd.Flags().GetString("image")
 	clusterRegistry, _ := cmd.Flags().GetString("cluster-registry")
-	runtimeClass, _ := cmd.Flags().GetString("runtime-class")
 	force, _ := cmd.Flags().GetBool("force")
 	skipLiveTest, _ := cmd.Flags().GetBool("skip-live-test")
-	verify, _ := cmd.Flags().GetBool("verify")
+	showDiff, _ := cmd.Flags().GetBool("diff")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	enclaveName, _ := cmd.Flags().GetString("enclave")
 
 	// --skip-live-test is an alias for --force
@@ -93,21 +136,13 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	// Apply config defaults: workflow.yaml > env config > config file
 	cfg := LoadConfig()
 
-	// Resolve --cluster: cluster config provides namespace, runtime-class defaults.
-	if clusterName != "" {
-		env, envErr := cfg.LoadEnvironment(clusterName)
-		if envErr != nil {
-			return fmt.Errorf("loading environment %q: %w", clusterName, envErr)
-		}
-		if !cmd.Flags().Changed("runtime-class") && env.RuntimeClass != "" {
-			runtimeClass = env.RuntimeClass
-		}
-		if !cmd.Flags().Changed("runtime-class") && env.RuntimeClass == "" {
-			runtimeClass = ""
-		}
-		if !cmd.Flags().Changed("image") && env.Image != "" {
-			imageFlagValue = env.Image
-		}
+	runtimeClass, imageFlagValue, err := resolveDeployDefaults(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return runDeployAll(cmd, absDir, cfg, runtimeClass, imageFlagValue, startedAt)
 	}
 
 	specPath := filepath.Join(absDir, "workflow.yaml")
@@ -120,6 +155,24 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("workflow spec has %d validation error(s)", len(errs))
 	}
 
+	// --input payload for the pre-deploy live test and --verify runs
+	runInput, err := loadRunInput(cmd, absDir, "")
+	if err != nil {
+		return err
+	}
+	gates, err := resolvePostDeployGates(cmd, cfg.Clusters[activeClusterName(cmd, cfg)].PostDeploy, absDir, runInput)
+	if err != nil {
+		return err
+	}
+	var liveTest *liveTestGate
+	liveTestSkip := "--force"
+	if !force && !dryRun {
+		liveTest, liveTestSkip, err = resolveLiveTestGate(cfg, activeClusterName(cmd, cfg), absDir, runInput, cmd.Flags().Changed("input"))
+		if err != nil {
+			return err
+		}
+	}
+
 	// Contract preflight gate: validate contract before deploy
 	warnMode, _ := cmd.Flags().GetBool("warn")
 	if wf.Contract != nil {
@@ -140,16 +193,47 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Cluster profile preflight: check the workflow against the saved profile for
+	// the target environment before anything touches the git-state remote or
+	// the cluster.
+	profileCluster := activeClusterName(cmd, cfg)
+	secretsSource := ""
+	if env, ok := cfg.Clusters[profileCluster]; ok {
+		secretsSource = env.SecretsSource
+	}
+	findings, compatErr := checkProfileCompatibility(profileCluster, wf, runtimeClass, secretsSource)
+	if compatErr != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: skipping cluster profile check: %s\n", compatErr)
+	}
+	if len(findings) > 0 {
+		fmt.Fprintln(os.Stderr, "Cluster profile compatibility:")
+		printCompatFindings(os.Stderr, findings)
+		if k8s.HasCompatErrors(findings) {
+			if !warnMode {
+				return emitDeployResult(cmd, "fail", "deploy aborted: workflow is incompatible with the saved cluster profile (use --warn for audit mode)", findings, startedAt)
+			}
+			fmt.Fprintf(os.Stderr, "Proceeding with deploy in audit mode (use strict mode in production)\n\n")
+		}
+	}
+
 	noPush, _ := cmd.Flags().GetBool("no-push")
 
 	// Git-state deploy gate: if git-state is enabled, verify the repo is clean
 	// for this enclave/tentacle before proceeding, then push HEAD to remote and
 	// capture provenance metadata for Deployment annotations.
+	locker := newDeployLocker(cmd, profileCluster)
 	var gitMeta GitMeta
 	if cfg.GitState.Enabled && cfg.GitState.RepoPath != "" {
 		if enclaveName == "" {
 			return emitDeployResult(cmd, "fail", "--enclave is required (pass it explicitly)", nil, startedAt)
 		}
+		if !dryRun {
+			release, lockErr := locker.lockGitState(cfg.GitState.RepoPath, enclaveName, wf.Name)
+			defer release()
+			if lockErr != nil {
+				return emitDeployResult(cmd, "fail", lockErr.Error(), nil, startedAt)
+			}
+		}
 		if gitErr := checkGitStateClean(cfg.GitState.RepoPath, enclaveName, wf.Name); gitErr != nil {
 			return emitDeployResult(cmd, "fail", gitErr.Error(), nil, startedAt)
 		}
@@ -159,9 +243,12 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 			return emitDeployResult(cmd, "fail", "reading git branch: "+branchErr.Error(), nil, startedAt)
 		}
 
-		if noPush {
+		switch {
+		case dryRun:
+			// Nothing is deployed, so there is nothing to sync.
+		case noPush:
 			_, _ = fmt.Fprintln(StatusWriter(cmd), "WARNING: --no-push bypasses remote sync; cluster state will diverge from git")
-		} else {
+		default:
 			if pushErr := pushGitState(cfg.GitState.RepoPath, branch); pushErr != nil {
 				return emitDeployResult(cmd, "fail", "git push failed — deploy aborted: "+pushErr.Error(), nil, startedAt)
 			}
@@ -177,27 +264,29 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	// Namespace cascade (pre-MCP): workflow.yaml > env config > global config > "default"
 	// --enclave override is applied after MCP client is available.
 	namespace := resolveNamespace(cmd, absDir)
-	if !cmd.Flags().Changed("runtime-class") && clusterName == "" && cfg.RuntimeClass != "" {
-		runtimeClass = cfg.RuntimeClass
-	}
 
-	// Image resolution cascade: --image flag > env.Image > <workflow>/.tentacular/base-image.txt > registry/tentacular-engine:version
-	imageTag := imageFlagValue
-	if imageTag == "" {
-		tagFilePath := filepath.Join(absDir, ".tentacular", "base-image.txt")
-		if tagData, readErr := os.ReadFile(tagFilePath); readErr == nil { //nolint:gosec // tagFilePath is derived from workflow directory
-			imageTag = strings.TrimSpace(string(tagData))
-		}
-	}
-	if imageTag == "" {
-		imageTag = resolveDefaultEngineImage(cfg)
-	}
+	imageTag := resolveDeployImage(absDir, imageFlagValue, cfg)
 
 	// Determine status output writer (stderr when -o json)
 	w := StatusWriter(cmd)
 
-	// Resolve MCP client once; it's used for apply, pre-deploy test, and verify.
-	mcpClient, err := requireMCPClient(cmd)
+	// Resolve the transport once; it's used for apply, pre-deploy test, and verify.
+	// With --direct, manifests are applied with client-go and the MCP-only
+	// steps (enclave lookup, workflow runs) are unavailable.
+	direct := isDirect(cmd)
+	var client mcp.WorkflowClient
+	var mcpClient *mcp.Client
+	kubeContext := ""
+	if direct {
+		if gates.verify {
+			return emitDeployResult(cmd, "fail", "--verify runs the workflow via the MCP server and cannot be combined with --direct", nil, startedAt)
+		}
+		kubeContext = resolveKubeContext(cmd)
+		client, err = newDirectClient(kubeContext)
+	} else {
+		mcpClient, err = requireMCPClient(cmd)
+		client = mcpClient
+	}
 	if err != nil {
 		return emitDeployResult(cmd, "fail", err.Error(), nil, startedAt)
 	}
@@ -205,84 +294,160 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 	// --enclave override: resolves to the enclave's namespace via MCP.
 	// Takes highest priority for namespace resolution.
 	if enclaveName != "" {
-		enclaveNS, nsErr := resolveEnclaveNamespace(cmd, mcpClient, enclaveName)
-		if nsErr != nil {
-			return emitDeployResult(cmd, "fail", nsErr.Error(), nil, startedAt)
-		}
-		namespace = enclaveNS
-	}
-
-	// Pre-deploy live test gate: if dev environment is configured and --force is not set,
-	// run a live test first to catch issues before deploying.
-	if !force {
-		devEnv, envErr := cfg.LoadEnvironment("dev")
-		if envErr == nil && devEnv.Namespace != "" {
-			_, _ = fmt.Fprintln(w, "Running pre-deploy live test in dev environment...")
-			liveOpts := InternalDeployOptions{
-				Namespace:    devEnv.Namespace,
-				Image:        imageTag,
-				RuntimeClass: devEnv.RuntimeClass,
-				StatusOut:    w,
+		if direct {
+			if enclaveName == "auto" {
+				return emitDeployResult(cmd, "fail", "--enclave auto requires the MCP server; name the enclave explicitly with --direct", nil, startedAt)
 			}
-			liveResult, liveErr := deployWorkflow(absDir, liveOpts, mcpClient)
-			if liveErr != nil {
-				return fmt.Errorf("pre-deploy live test failed (use --force to skip): %w", liveErr)
-			}
-
-			runResult, runErr := liveResult.MCPClient.WfRun(cmd.Context(), liveResult.Namespace, liveResult.WorkflowName, nil, 120)
-			// Clean up the dev deployment regardless of run outcome
-			_, _ = liveResult.MCPClient.WfRemove(cmd.Context(), liveResult.Namespace, liveResult.WorkflowName)
-
-			if runErr != nil {
-				return fmt.Errorf("pre-deploy live test: workflow run failed (use --force to skip): %w", runErr)
-			}
-
-			var execResult map[string]any
-			if json.Unmarshal(runResult.Output, &execResult) == nil {
-				if success, ok := execResult["success"].(bool); ok && !success {
-					return errors.New("pre-deploy live test: workflow returned success=false (use --force to skip)")
-				}
+			// The namespace name is always the enclave name.
+			namespace = enclaveName
+		} else {
+			enclaveNS, nsErr := resolveEnclaveNamespace(cmd, mcpClient, enclaveName)
+			if nsErr != nil {
+				return emitDeployResult(cmd, "fail", nsErr.Error(), nil, startedAt)
 			}
-			_, _ = fmt.Fprintln(w, "  Pre-deploy live test passed")
+			namespace = enclaveNS
 		}
 	}
 
-	// Deploy
+	deployer := resolveDeployerIdentity(profileCluster)
 	deployOpts := InternalDeployOptions{
 		Namespace:    namespace,
 		Image:        imageTag,
 		RuntimeClass: runtimeClass,
+		Context:      kubeContext,
 		StatusOut:    w,
 		GitMeta:      gitMeta,
+		Deployer:     &deployer,
+		KEDA:         clusterHasKEDA(profileCluster),
+	}
+
+	// Diff the rendered manifests against the live objects; --dry-run stops here.
+	if showDiff || dryRun {
+		diffOpts := deployOpts
+		diffOpts.Deployer = nil // the stamp changes on every deploy
+		if !dryRun {
+			diffOpts.StatusOut = io.Discard // the deploy below reports rendering
+		}
+		_, local, renderErr := renderWorkflowManifests(absDir, diffOpts)
+		if renderErr != nil {
+			return emitDeployResult(cmd, "fail", "rendering manifests: "+renderErr.Error(), nil, startedAt)
+		}
+		live, liveErr := liveManifests(cmd.Context(), client, namespace, wf.Name)
+		if liveErr != nil {
+			return emitDeployResult(cmd, "fail", "reading live manifests: "+liveErr.Error(), nil, startedAt)
+		}
+		diff := diffManifests(wf.Name, namespace, local, live)
+		writeDeployDiff(w, &diff)
+		if dryRun {
+			return emitDeployResult(cmd, "pass", "dry run: "+diff.summaryLine(), diff, startedAt)
+		}
 	}
 
-	deployResult, err := deployWorkflow(absDir, deployOpts, mcpClient)
+	// Hold the deploy lock from the live test through apply and verification.
+	release, lockErr := locker.lockCluster(cmd.Context(), client, namespace, wf.Name)
+	defer release()
+	if lockErr != nil {
+		return emitDeployResult(cmd, "fail", lockErr.Error(), nil, startedAt)
+	}
+	resume, _ := cmd.Flags().GetBool("resume")
+	if err := checkNotSuspended(cmd.Context(), client, namespace, wf.Name, resume, w); err != nil {
+		return emitDeployResult(cmd, "fail", err.Error(), nil, startedAt)
+	}
+
+	// Pre-deploy live test gate: unless --force, deploy to the target's
+	// live-test environment and run it before touching the target.
+	var phases []deployPhase
+	switch {
+	case liveTest == nil:
+		phases = append(phases, deployPhase{Phase: phaseLiveTest, Status: "skipped", Detail: liveTestSkip})
+	case direct:
+		_, _ = fmt.Fprintln(w, "Skipping pre-deploy live test: it runs the workflow via the MCP server (unavailable with --direct)")
+		phases = append(phases, deployPhase{Phase: phaseLiveTest, Status: "skipped", Detail: "unavailable with --direct"})
+	default:
+		phase := runLiveTestGate(cmd, liveTest, absDir, imageTag, mcpClient, w)
+		phases = append(phases, phase)
+		if phase.Status == "fail" {
+			return emitDeployResultPhases(cmd, "fail", "pre-deploy live test failed: "+phase.Detail, phases, nil, startedAt)
+		}
+	}
+
+	// The revision live before this deploy is what a failed gate rolls back to.
+	var previous *deployRevision
+	if gates.enabled() && gates.rollback {
+		if revs, listErr := resolveRevisionStore(cmd.Context(), cfg, client).list(profileCluster, namespace, wf.Name); listErr == nil && len(revs) > 0 {
+			previous = &revs[0]
+		}
+	}
+
+	// Deploy
+	deployResult, err := deployWorkflow(absDir, deployOpts, client)
 	if err != nil {
-		return emitDeployResult(cmd, "fail", "deploy failed: "+err.Error(), nil, startedAt)
+		return emitDeployResultPhases(cmd, "fail", "deploy failed: "+err.Error(), phases, nil, startedAt)
+	}
+
+	recordRevision(cmd, client, newDeployRevision(deployResult, profileCluster, absDir, gitMeta))
+	summary := fmt.Sprintf("deployed %s to %s", deployResult.WorkflowName, deployResult.Namespace)
+	if !gates.enabled() {
+		return emitDeployResultPhases(cmd, "pass", summary, phases, nil, startedAt)
 	}
 
-	// Post-deploy verification
-	if verify {
-		_, _ = fmt.Fprintln(w, "Verifying deployment...")
-		runResult, runErr := deployResult.MCPClient.WfRun(cmd.Context(), deployResult.Namespace, deployResult.WorkflowName, nil, 120)
-		if runErr != nil {
-			return emitDeployResult(cmd, "fail", "verification: workflow run failed: "+runErr.Error(), nil, startedAt)
+	// Post-deploy gates; a failure re-applies the previous revision.
+	report := runPostDeployGates(cmd, client, mcpClient, deployResult.Namespace, deployResult.WorkflowName, gates, w)
+	if failed := report.failed(); failed != nil {
+		summary = fmt.Sprintf("post-deploy gate %s failed: %s", failed.Gate, failed.Detail)
+		if gates.rollback {
+			report.Rollback = rollBackFailedDeploy(cmd, client, locker, previous, profileCluster, w)
+			summary += "; " + report.Rollback.summary()
 		}
+		return emitDeployResultPhases(cmd, "fail", summary, phases, report, startedAt)
+	}
+	return emitDeployResultPhases(cmd, "pass", summary, phases, report, startedAt)
+}
 
-		var execResult map[string]any
-		if json.Unmarshal(runResult.Output, &execResult) == nil {
-			if success, ok := execResult["success"].(bool); ok && !success {
-				return emitDeployResult(cmd, "fail", "verification: workflow returned success=false", execResult, startedAt)
-			}
+// resolveDeployDefaults applies the active environment's defaults to the
+// --runtime-class and --image flags. The runtime class follows
+// resolveRuntimeClassForEnv, as the offline preflights do; an unknown
+// --cluster is an error.
+func resolveDeployDefaults(cmd *cobra.Command, cfg TentacularConfig) (runtimeClass, image string, err error) {
+	if name := flagString(cmd, "cluster"); name != "" {
+		if _, envErr := cfg.LoadEnvironment(name); envErr != nil {
+			return "", "", fmt.Errorf("loading environment %q: %w", name, envErr)
 		}
-		_, _ = fmt.Fprintln(w, "  Verification passed")
 	}
+	clusterName := activeClusterName(cmd, cfg)
+	runtimeClass = resolveRuntimeClassForEnv(cfg, clusterName)
+	if cmd.Flags().Changed("runtime-class") {
+		runtimeClass, _ = cmd.Flags().GetString("runtime-class")
+	}
+	image, _ = cmd.Flags().GetString("image")
+	if env, ok := cfg.Clusters[clusterName]; ok && !cmd.Flags().Changed("image") && env.Image != "" {
+		image = env.Image
+	}
+	return runtimeClass, image, nil
+}
 
-	return emitDeployResult(cmd, "pass", fmt.Sprintf("deployed %s to %s", deployResult.WorkflowName, deployResult.Namespace), nil, startedAt)
+// resolveDeployImage applies the image resolution cascade: --image flag >
+// env.Image > <workflow>/.tentacular/base-image.txt > registry/tentacular-engine:version.
+func resolveDeployImage(workflowDir, image string, cfg TentacularConfig) string {
+	if image != "" {
+		return image
+	}
+	tagFilePath := filepath.Join(workflowDir, ".tentacular", "base-image.txt")
+	if tagData, readErr := os.ReadFile(tagFilePath); readErr == nil { //nolint:gosec // tagFilePath is derived from workflow directory
+		if tag := strings.TrimSpace(string(tagData)); tag != "" {
+			return tag
+		}
+	}
+	return resolveDefaultEngineImage(cfg)
 }
 
 // emitDeployResult outputs the deploy result in the appropriate format.
 func emitDeployResult(cmd *cobra.Command, status, summary string, execution any, startedAt time.Time) error {
+	return emitDeployResultPhases(cmd, status, summary, nil, execution, startedAt)
+}
+
+// emitDeployResultPhases is emitDeployResult with the deploy's phases.
+func emitDeployResultPhases(cmd *cobra.Command, status, summary string, phases []deployPhase, execution any, startedAt time.Time) error {
 	result := CommandResult{
 		Version: "1",
 		Command: "deploy",
@@ -295,6 +460,9 @@ func emitDeployResult(cmd *cobra.Command, status, summary string, execution any,
 		},
 		Execution: execution,
 	}
+	if len(phases) > 0 {
+		result.Phases = phases
+	}
 
 	if status == "fail" {
 		result.Hints = append(result.Hints, "use --force to skip pre-deploy live test")
@@ -374,6 +542,17 @@ func buildManifests(workflowDir string, wf *spec.Workflow, opts InternalDeployOp
 		proxyURL = fmt.Sprintf("http://esm-sh.%s.svc.cluster.local:8080", cfg.ModuleProxy.Namespace)
 	}
 
+	// Autoscale queue triggers with a KEDA ScaledObject when the cluster has KEDA
+	var scaledObject *builder.Manifest
+	if a := wf.Deployment.Autoscaling; a != nil {
+		if opts.KEDA {
+			scaledObject = k8s.GenerateScaledObject(wf, namespace)
+			_, _ = fmt.Fprintf(w, "  Autoscaling: KEDA ScaledObject, %d-%d replicas on queue consumer lag\n", a.Min(), a.MaxReplicas)
+		} else {
+			_, _ = fmt.Fprintln(w, "  Autoscaling: KEDA not detected in the cluster profile -- running a single replica")
+		}
+	}
+
 	// Generate K8s manifests
 	buildOpts := builder.DeployOptions{
 		Metadata:         metaBundle,
@@ -381,9 +560,13 @@ func buildManifests(workflowDir string, wf *spec.Workflow, opts InternalDeployOp
 		ImagePullPolicy:  imagePullPolicy,
 		ModuleProxyURL:   proxyURL,
 		WorkflowDir:      workflowDir,
+		Autoscaled:       scaledObject != nil,
 	}
 	manifests := builder.GenerateK8sManifests(wf, imageTag, namespace, buildOpts)
 	manifests = append([]builder.Manifest{configMap}, manifests...)
+	if scaledObject != nil {
+		manifests = append(manifests, *scaledObject)
+	}
 
 	// Add NetworkPolicy if contract present
 	proxyNamespace := cfg.ModuleProxy.Namespace
@@ -416,47 +599,95 @@ func buildManifests(workflowDir string, wf *spec.Workflow, opts InternalDeployOp
 	return manifests, nil
 }
 
-// deployWorkflow builds manifests locally and applies them via MCP.
+// deployWorkflow builds manifests locally and applies them via the given
+// transport (MCP, or client-go with --direct).
 // Used by both `tntc deploy` and `tntc test --live`.
-func deployWorkflow(workflowDir string, opts InternalDeployOptions, mcpClient *mcp.Client) (*DeployResult, error) {
+func deployWorkflow(workflowDir string, opts InternalDeployOptions, client mcp.WorkflowClient) (*DeployResult, error) {
 	w := opts.StatusOut
 	if w == nil {
 		w = os.Stdout
 	}
 
+	// Phases 1 and 2: build manifests locally and convert them for the transport
+	wf, mcpManifests, err := renderWorkflowManifests(workflowDir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	via := "MCP"
+	if _, direct := client.(*k8s.DirectClient); direct {
+		via = "kubeconfig"
+		if opts.Context != "" {
+			via = "kubeconfig context " + opts.Context
+		}
+		_, _ = fmt.Fprintf(w, "Deploying %s to namespace %s (direct, %s)...\n", wf.Name, opts.Namespace, via)
+	} else {
+		_, _ = fmt.Fprintf(w, "Deploying %s to namespace %s...\n", wf.Name, opts.Namespace)
+	}
+
+	// Phase 3: Apply via the transport
+	applyResult, err := client.WfApply(context.Background(), opts.Namespace, wf.Name, mcpManifests)
+	if err != nil {
+		if isAuthzError(err) {
+			return nil, fmt.Errorf("permission denied: %w\n  hint: check your enclave membership with 'tntc enclave info <name>'", err)
+		}
+		if hint := mcpErrorHint(err); hint != "" {
+			return nil, fmt.Errorf("applying via %s: %w\n  hint: %s", via, err, hint)
+		}
+		return nil, fmt.Errorf("applying via %s: %w", via, err)
+	}
+
+	for _, applied := range applyResult.Applied {
+		_, _ = fmt.Fprintf(w, "  applied %s\n", applied)
+	}
+
+	if applyResult.Updated > 0 {
+		_, _ = fmt.Fprintln(w, "  Triggered rollout restart")
+	}
+
+	_, _ = fmt.Fprintf(w, "Deployed %s to %s\n", wf.Name, opts.Namespace)
+	return &DeployResult{
+		WorkflowName: wf.Name,
+		Namespace:    opts.Namespace,
+		Client:       client,
+		Manifests:    mcpManifests,
+	}, nil
+}
+
+// renderWorkflowManifests parses and contract-checks the workflow in
+// workflowDir, builds its manifests and converts them to the maps sent to
+// the transport, with git provenance annotations injected. Used by
+// deployWorkflow and deploy --diff.
+func renderWorkflowManifests(workflowDir string, opts InternalDeployOptions) (*spec.Workflow, []map[string]any, error) {
 	specPath := filepath.Join(workflowDir, "workflow.yaml")
 	data, err := os.ReadFile(specPath) //nolint:gosec // specPath is derived from workflow directory
 	if err != nil {
-		return nil, fmt.Errorf("reading workflow spec: %w", err)
+		return nil, nil, fmt.Errorf("reading workflow spec: %w", err)
 	}
 
 	wf, errs := spec.Parse(data)
 	if len(errs) > 0 {
-		return nil, fmt.Errorf("workflow spec has %d validation error(s)", len(errs))
+		return nil, nil, fmt.Errorf("workflow spec has %d validation error(s)", len(errs))
 	}
 
 	// Contract preflight gate (strict mode for internal calls)
 	if wf.Contract != nil {
 		contractErrs := spec.ValidateContract(wf.Contract)
 		if len(contractErrs) > 0 {
-			return nil, fmt.Errorf("deploy aborted: contract validation failed with %d error(s)", len(contractErrs))
+			return nil, nil, fmt.Errorf("deploy aborted: contract validation failed with %d error(s)", len(contractErrs))
 		}
 	}
 
-	// Phase 1: Build manifests locally
 	manifests, err := buildManifests(workflowDir, wf, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	_, _ = fmt.Fprintf(w, "Deploying %s to namespace %s...\n", wf.Name, opts.Namespace)
-
-	// Phase 2: Convert manifests to map[string]any for MCP transport
 	mcpManifests := make([]map[string]any, 0, len(manifests))
 	for _, m := range manifests {
 		var obj map[string]any
 		if unmarshalErr := yaml.Unmarshal([]byte(m.Content), &obj); unmarshalErr != nil {
-			return nil, fmt.Errorf("serializing manifest %s/%s: %w", m.Kind, m.Name, unmarshalErr)
+			return nil, nil, fmt.Errorf("serializing manifest %s/%s: %w", m.Kind, m.Name, unmarshalErr)
 		}
 		mcpManifests = append(mcpManifests, obj)
 	}
@@ -465,33 +696,10 @@ func deployWorkflow(workflowDir string, opts InternalDeployOptions, mcpClient *m
 	if opts.GitMeta.SHA != "" {
 		injectGitAnnotations(mcpManifests, opts.GitMeta)
 	}
-
-	// Phase 3: Apply via MCP
-	applyResult, err := mcpClient.WfApply(context.Background(), opts.Namespace, wf.Name, mcpManifests)
-	if err != nil {
-		if isAuthzError(err) {
-			return nil, fmt.Errorf("permission denied: %w\n  hint: check your enclave membership with 'tntc enclave info <name>'", err)
-		}
-		if hint := mcpErrorHint(err); hint != "" {
-			return nil, fmt.Errorf("applying via MCP: %w\n  hint: %s", err, hint)
-		}
-		return nil, fmt.Errorf("applying via MCP: %w", err)
-	}
-
-	for _, applied := range applyResult.Applied {
-		_, _ = fmt.Fprintf(w, "  applied %s\n", applied)
-	}
-
-	if applyResult.Updated > 0 {
-		_, _ = fmt.Fprintln(w, "  Triggered rollout restart")
+	if opts.Deployer != nil {
+		injectDeployerAnnotations(mcpManifests, *opts.Deployer, time.Now())
 	}
-
-	_, _ = fmt.Fprintf(w, "Deployed %s to %s\n", wf.Name, opts.Namespace)
-	return &DeployResult{
-		WorkflowName: wf.Name,
-		Namespace:    opts.Namespace,
-		MCPClient:    mcpClient,
-	}, nil
+	return wf, mcpManifests, nil
 }
 
 // buildSecretManifest creates a K8s Secret manifest from a .secrets.yaml file.
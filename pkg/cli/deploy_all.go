@@ -0,0 +1,511 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// defaultDeployConcurrency is the number of tentacles deploy --all applies at once.
+const defaultDeployConcurrency = 4
+
+// Batch deploy outcomes for one tentacle.
+const (
+	batchDeployed = "deployed"
+	batchFailed   = "failed"
+	batchSkipped  = "skipped" // a dependency failed or was skipped
+	batchInvalid  = "invalid" // failed validation; nothing is deployed
+)
+
+// batchTentacle is one workflow found under the enclave directory.
+type batchTentacle struct {
+	wf        *spec.Workflow
+	entry     *batchDeployEntry
+	dir       string
+	namespace string
+	dependsOn []string
+}
+
+// batchDeployEntry is the per-tentacle result reported by deploy --all.
+type batchDeployEntry struct {
+	Name       string   `json:"name"`
+	Dir        string   `json:"dir"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Status     string   `json:"status"`
+	Error      string   `json:"error,omitempty"`
+	DependsOn  []string `json:"dependsOn,omitempty"`
+	DurationMs int64    `json:"durationMs"`
+}
+
+// runDeployAll deploys every tentacle under root: all are validated first,
+// then deployed dependencies-first with bounded concurrency. A failure skips
+// only the tentacles that (transitively) depend on the failed one.
+func runDeployAll(cmd *cobra.Command, root string, cfg TentacularConfig, runtimeClass, image string, startedAt time.Time) error {
+	verify, _ := cmd.Flags().GetBool("verify")
+	showDiff, _ := cmd.Flags().GetBool("diff")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if verify || showDiff || dryRun || cmd.Flags().Changed("verify-input") || cmd.Flags().Changed("soak") {
+		return errors.New("--verify, --verify-input, --soak, --diff and --dry-run are not supported with --all")
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	skipLiveTest, _ := cmd.Flags().GetBool("skip-live-test")
+	warnMode, _ := cmd.Flags().GetBool("warn")
+	noPush, _ := cmd.Flags().GetBool("no-push")
+	wait, _ := cmd.Flags().GetBool("wait")
+	waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+	resume, _ := cmd.Flags().GetBool("resume")
+	enclaveName, _ := cmd.Flags().GetString("enclave")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	w := StatusWriter(cmd)
+
+	gitState := cfg.GitState.Enabled && cfg.GitState.RepoPath != ""
+	if enclaveName == "" && gitState {
+		// In the git-state layout the directory is enclaves/<enclave>/.
+		enclaveName = filepath.Base(root)
+	}
+
+	dirs, err := discoverTentacles(root)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no workflow.yaml found under %s", root)
+	}
+
+	// Validate every tentacle before anything is deployed.
+	_, _ = fmt.Fprintf(w, "Validating %d tentacles in %s...\n", len(dirs), root)
+	profileCluster := activeClusterName(cmd, cfg)
+	secretsSource := ""
+	if env, ok := cfg.Clusters[profileCluster]; ok {
+		secretsSource = env.SecretsSource
+	}
+	locker := newDeployLocker(cmd, profileCluster)
+	var releases []func()
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+	tentacles := make([]*batchTentacle, 0, len(dirs))
+	byName := map[string]*batchTentacle{}
+	for _, dir := range dirs {
+		t := &batchTentacle{dir: dir, entry: &batchDeployEntry{Name: filepath.Base(dir), Dir: dir}}
+		tentacles = append(tentacles, t)
+		wf, problem := validateBatchTentacle(dir, profileCluster, runtimeClass, secretsSource, warnMode, w)
+		if problem == "" && gitState {
+			release, lockErr := locker.lockGitState(cfg.GitState.RepoPath, enclaveName, wf.Name)
+			releases = append(releases, release)
+			if lockErr != nil {
+				problem = lockErr.Error()
+			} else if gitErr := checkGitStateClean(cfg.GitState.RepoPath, enclaveName, wf.Name); gitErr != nil {
+				problem = gitErr.Error()
+			}
+		}
+		if wf != nil {
+			t.wf = wf
+			t.entry.Name = wf.Name
+			if other, dup := byName[wf.Name]; dup {
+				problem = fmt.Sprintf("duplicate workflow name %q (also in %s)", wf.Name, other.dir)
+			} else {
+				byName[wf.Name] = t
+			}
+		}
+		if problem != "" {
+			t.entry.Status, t.entry.Error = batchInvalid, problem
+		}
+	}
+	deps := tentacleDependencies(byName)
+	for _, t := range tentacles {
+		if t.wf != nil && byName[t.wf.Name] == t {
+			t.dependsOn = deps[t.wf.Name]
+			t.entry.DependsOn = t.dependsOn
+		}
+	}
+	if cycle := dependencyCycle(deps); len(cycle) > 0 {
+		for _, name := range cycle[:len(cycle)-1] {
+			if e := byName[name].entry; e.Status == "" {
+				e.Status, e.Error = batchInvalid, "dependency cycle: "+strings.Join(cycle, " -> ")
+			}
+		}
+	}
+	invalid := 0
+	for _, t := range tentacles {
+		if t.entry.Status == batchInvalid {
+			invalid++
+			_, _ = fmt.Fprintf(w, "  ✗ %s: %s\n", t.entry.Name, t.entry.Error)
+		}
+	}
+	if invalid > 0 {
+		return emitDeployResult(cmd, "fail", fmt.Sprintf("%d of %d tentacles failed validation; nothing deployed", invalid, len(tentacles)), batchEntries(tentacles), startedAt)
+	}
+
+	// Git-state: push once for the whole batch.
+	var gitMeta GitMeta
+	if gitState {
+		branch, branchErr := getCurrentBranch(cfg.GitState.RepoPath)
+		if branchErr != nil {
+			return emitDeployResult(cmd, "fail", "reading git branch: "+branchErr.Error(), nil, startedAt)
+		}
+		if noPush {
+			_, _ = fmt.Fprintln(w, "WARNING: --no-push bypasses remote sync; cluster state will diverge from git")
+		} else if pushErr := pushGitState(cfg.GitState.RepoPath, branch); pushErr != nil {
+			return emitDeployResult(cmd, "fail", "git push failed — deploy aborted: "+pushErr.Error(), nil, startedAt)
+		}
+		var metaErr error
+		if gitMeta, metaErr = captureGitMeta(cfg.GitState.RepoPath); metaErr != nil {
+			return emitDeployResult(cmd, "fail", "reading git metadata: "+metaErr.Error(), nil, startedAt)
+		}
+	}
+
+	direct := isDirect(cmd)
+	var client mcp.WorkflowClient
+	var mcpClient *mcp.Client
+	kubeContext := ""
+	if direct {
+		kubeContext = resolveKubeContext(cmd)
+		client, err = newDirectClient(kubeContext)
+	} else {
+		mcpClient, err = requireMCPClient(cmd)
+		client = mcpClient
+	}
+	if err != nil {
+		return emitDeployResult(cmd, "fail", err.Error(), nil, startedAt)
+	}
+	enclaveNS := ""
+	switch {
+	case enclaveName == "":
+	case direct && enclaveName == "auto":
+		return emitDeployResult(cmd, "fail", "--enclave auto requires the MCP server; name the enclave explicitly with --direct", nil, startedAt)
+	case direct:
+		enclaveNS = enclaveName
+	default:
+		if enclaveNS, err = resolveEnclaveNamespace(cmd, mcpClient, enclaveName); err != nil {
+			return emitDeployResult(cmd, "fail", err.Error(), nil, startedAt)
+		}
+	}
+	for _, t := range tentacles {
+		t.namespace = enclaveNS
+		if t.namespace == "" {
+			t.namespace = resolveNamespace(cmd, t.dir)
+		}
+		t.entry.Namespace = t.namespace
+	}
+
+	if devEnv, envErr := cfg.LoadEnvironment("dev"); envErr == nil && devEnv.Namespace != "" && !force && !skipLiveTest {
+		_, _ = fmt.Fprintln(w, "Skipping pre-deploy live tests: not run with --all (use tntc test --live per tentacle)")
+	}
+
+	deployer := resolveDeployerIdentity(profileCluster)
+	keda := clusterHasKEDA(profileCluster)
+	_, _ = fmt.Fprintf(w, "Deploying %d tentacles (concurrency %d)...\n", len(tentacles), concurrency)
+	var outMu, revMu sync.Mutex
+	deployOne := func(t *batchTentacle) error {
+		started := time.Now()
+		var buf bytes.Buffer
+		release, err := locker.lockCluster(cmd.Context(), client, t.namespace, t.entry.Name)
+		defer release()
+		if err == nil {
+			err = checkNotSuspended(cmd.Context(), client, t.namespace, t.entry.Name, resume, &buf)
+		}
+		if err == nil {
+			err = deployBatchTentacle(cmd, t, client, InternalDeployOptions{
+				Namespace:    t.namespace,
+				Image:        resolveDeployImage(t.dir, image, cfg),
+				RuntimeClass: runtimeClass,
+				Context:      kubeContext,
+				StatusOut:    &buf,
+				GitMeta:      gitMeta,
+				Deployer:     &deployer,
+				KEDA:         keda,
+			}, profileCluster, wait, waitTimeout, &revMu)
+		}
+		t.entry.DurationMs = time.Since(started).Milliseconds()
+		outMu.Lock()
+		writePrefixed(w, "["+t.entry.Name+"] ", buf.String())
+		outMu.Unlock()
+		return err
+	}
+	runBatch(tentacles, byName, concurrency, deployOne)
+
+	deployed, failed, skipped := 0, 0, 0
+	for _, t := range tentacles {
+		switch t.entry.Status {
+		case batchDeployed:
+			deployed++
+			_, _ = fmt.Fprintf(w, "  ✓ %s deployed to %s\n", t.entry.Name, t.namespace)
+		case batchFailed:
+			failed++
+			_, _ = fmt.Fprintf(w, "  ✗ %s failed: %s\n", t.entry.Name, t.entry.Error)
+		case batchSkipped:
+			skipped++
+			_, _ = fmt.Fprintf(w, "  - %s skipped: %s\n", t.entry.Name, t.entry.Error)
+		}
+	}
+	summary := fmt.Sprintf("deployed %d of %d tentacles from %s", deployed, len(tentacles), root)
+	if failed+skipped > 0 {
+		summary += fmt.Sprintf(" (%d failed, %d skipped)", failed, skipped)
+		return emitDeployResult(cmd, "fail", summary, batchEntries(tentacles), startedAt)
+	}
+	return emitDeployResult(cmd, "pass", summary, batchEntries(tentacles), startedAt)
+}
+
+// discoverTentacles returns the directories under root holding a
+// workflow.yaml, sorted. Hidden directories and node_modules are skipped.
+func discoverTentacles(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if _, statErr := os.Stat(filepath.Join(path, "workflow.yaml")); statErr == nil {
+			dirs = append(dirs, path)
+			return filepath.SkipDir // tentacles do not nest
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering tentacles under %s: %w", root, err)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// validateBatchTentacle runs the deploy preflight checks for one tentacle:
+// spec, contract and cluster profile. It returns the parsed workflow (when
+// the spec parses) and a problem description ("" when valid).
+func validateBatchTentacle(dir, profileCluster, runtimeClass, secretsSource string, warnMode bool, w io.Writer) (*spec.Workflow, string) {
+	data, err := os.ReadFile(filepath.Join(dir, "workflow.yaml")) //nolint:gosec // path is derived from the discovered tentacle directory
+	if err != nil {
+		return nil, fmt.Sprintf("reading workflow spec: %v", err)
+	}
+	wf, errs := spec.Parse(data)
+	if len(errs) > 0 {
+		return nil, fmt.Sprintf("workflow spec has %d validation error(s): %s", len(errs), strings.Join(errs, "; "))
+	}
+	if wf.Contract != nil && !warnMode {
+		if contractErrs := spec.ValidateContract(wf.Contract); len(contractErrs) > 0 {
+			return wf, fmt.Sprintf("contract validation failed: %s", strings.Join(contractErrs, "; "))
+		}
+	}
+	findings, compatErr := checkProfileCompatibility(profileCluster, wf, runtimeClass, secretsSource)
+	if compatErr != nil {
+		return wf, ""
+	}
+	if k8s.HasCompatErrors(findings) && !warnMode {
+		_, _ = fmt.Fprintf(w, "Cluster profile compatibility (%s):\n", wf.Name)
+		printCompatFindings(w, findings)
+		return wf, "workflow is incompatible with the saved cluster profile (use --warn for audit mode)"
+	}
+	return wf, ""
+}
+
+// tentacleDependencies derives the deploy order edges between tentacles of
+// one batch: a tentacle depends on another when a contract dependency
+// targets the other's in-cluster Service (<name>, <name>.<ns> or
+// <name>.<ns>.svc.cluster.local), or when it publishes (nats dependency) to
+// a subject the other consumes with a queue trigger.
+func tentacleDependencies(tentacles map[string]*batchTentacle) map[string][]string {
+	consumers := map[string][]string{}
+	for name, t := range tentacles {
+		for _, trig := range t.wf.Triggers {
+			if trig.Type == "queue" && trig.Subject != "" {
+				consumers[trig.Subject] = append(consumers[trig.Subject], name)
+			}
+		}
+	}
+	deps := map[string][]string{}
+	for name, t := range tentacles {
+		if t.wf.Contract == nil {
+			continue
+		}
+		set := map[string]bool{}
+		for _, dep := range t.wf.Contract.Dependencies {
+			if dep.Protocol == "nats" && dep.Subject != "" {
+				for _, c := range consumers[dep.Subject] {
+					set[c] = true
+				}
+			}
+			if target := serviceHostTarget(dep.Host); tentacles[target] != nil {
+				set[target] = true
+			}
+		}
+		delete(set, name)
+		for d := range set {
+			deps[name] = append(deps[name], d)
+		}
+		sort.Strings(deps[name])
+	}
+	return deps
+}
+
+// serviceHostTarget returns the Service name of an in-cluster host, or ""
+// for external hosts.
+func serviceHostTarget(host string) string {
+	switch parts := strings.Split(host, "."); {
+	case host == "":
+		return ""
+	case len(parts) == 1, len(parts) == 2, strings.HasSuffix(host, ".svc.cluster.local"), strings.HasSuffix(host, ".svc"):
+		return parts[0]
+	}
+	return ""
+}
+
+// dependencyCycle returns one dependency cycle as a path whose first and
+// last elements are equal, or nil.
+func dependencyCycle(deps map[string][]string) []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(string) []string
+	visit = func(n string) []string {
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, d := range deps[n] {
+			switch state[d] {
+			case visiting:
+				for i, s := range stack {
+					if s == d {
+						return append(append([]string(nil), stack[i:]...), d)
+					}
+				}
+			case 0:
+				if c := visit(d); c != nil {
+					return c
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = done
+		return nil
+	}
+	names := make([]string, 0, len(deps))
+	for n := range deps {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if state[n] == 0 {
+			if c := visit(n); c != nil {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// runBatch calls deploy for each tentacle once all its dependencies are
+// deployed, at most concurrency at a time, and records the outcome in the
+// tentacle's entry. Tentacles whose dependencies failed or were skipped are
+// marked skipped instead.
+func runBatch(tentacles []*batchTentacle, byName map[string]*batchTentacle, concurrency int, deploy func(*batchTentacle) error) {
+	type outcome struct {
+		t   *batchTentacle
+		err error
+	}
+	done := make(chan outcome)
+	started := map[*batchTentacle]bool{}
+	running := 0
+	for {
+		for progress := true; progress; {
+			progress = false
+			for _, t := range tentacles {
+				if started[t] || running >= concurrency {
+					continue
+				}
+				ready := true
+				for _, d := range t.dependsOn {
+					switch byName[d].entry.Status {
+					case batchDeployed:
+					case batchFailed, batchSkipped:
+						started[t] = true
+						t.entry.Status, t.entry.Error = batchSkipped, fmt.Sprintf("dependency %s %s", d, byName[d].entry.Status)
+						progress = true
+						ready = false
+					default:
+						ready = false
+					}
+					if started[t] {
+						break
+					}
+				}
+				if ready {
+					started[t] = true
+					running++
+					go func() {
+						done <- outcome{t, deploy(t)}
+					}()
+				}
+			}
+		}
+		if running == 0 {
+			return
+		}
+		o := <-done
+		running--
+		if o.err != nil {
+			o.t.entry.Status, o.t.entry.Error = batchFailed, o.err.Error()
+		} else {
+			o.t.entry.Status = batchDeployed
+		}
+	}
+}
+
+// deployBatchTentacle applies one tentacle, records its revision and, with
+// wait, watches the rollout so dependents start against a ready service.
+func deployBatchTentacle(cmd *cobra.Command, t *batchTentacle, client mcp.WorkflowClient, opts InternalDeployOptions, cluster string, wait bool, waitTimeout time.Duration, revMu *sync.Mutex) error {
+	result, err := deployWorkflow(t.dir, opts, client)
+	if err != nil {
+		return err
+	}
+	revMu.Lock()
+	recordRevision(cmd, client, newDeployRevision(result, cluster, t.dir, opts.GitMeta))
+	revMu.Unlock()
+	if wait {
+		if _, err := watchRollout(cmd.Context(), client, result.Namespace, result.WorkflowName, watchPollInterval, waitTimeout, opts.StatusOut); err != nil {
+			return fmt.Errorf("rollout: %w", err)
+		}
+	}
+	return nil
+}
+
+func writePrefixed(w io.Writer, prefix, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line != "" {
+			_, _ = fmt.Fprintf(w, "%s%s\n", prefix, line)
+		}
+	}
+}
+
+func batchEntries(tentacles []*batchTentacle) []batchDeployEntry {
+	entries := make([]batchDeployEntry, 0, len(tentacles))
+	for _, t := range tentacles {
+		entries = append(entries, *t.entry)
+	}
+	return entries
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// writeTentacle writes a workflow named name under root/name. extra is
+// appended to the workflow spec (e.g. a contract).
+func writeTentacle(t *testing.T, root, name, triggers, extra string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wf := "name: " + name + "\nversion: \"1.0\"\ntriggers:\n" + triggers + "nodes:\n  handler:\n    path: ./handler.ts\n    description: \"Test node\"\n" + extra
+	for file, content := range map[string]string{"workflow.yaml": wf, "handler.ts": "export default async () => ({})\n"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const manualTrigger = "  - type: manual\n"
+
+func batchFixture(t *testing.T, yamls map[string]string) map[string]*batchTentacle {
+	t.Helper()
+	byName := map[string]*batchTentacle{}
+	for name, y := range yamls {
+		wf, errs := spec.Parse([]byte(y))
+		if len(errs) > 0 {
+			t.Fatalf("%s: %v", name, errs)
+		}
+		byName[name] = &batchTentacle{wf: wf, entry: &batchDeployEntry{Name: name}}
+	}
+	return byName
+}
+
+func TestTentacleDependencies(t *testing.T) {
+	byName := batchFixture(t, map[string]string{
+		"api": "name: api\nversion: \"1.0\"\ntriggers:\n  - type: manual\nnodes:\n  h:\n    path: ./h.ts\n    description: h\n",
+		"worker": "name: worker\nversion: \"1.0\"\ntriggers:\n  - type: queue\n    subject: jobs.new\nnodes:\n  h:\n    path: ./h.ts\n    description: h\n" +
+			"contract:\n  version: \"1\"\n  dependencies:\n    api:\n      protocol: https\n      host: api.prod.svc.cluster.local\n      port: 8080\n",
+		"producer": "name: producer\nversion: \"1.0\"\ntriggers:\n  - type: manual\nnodes:\n  h:\n    path: ./h.ts\n    description: h\n" +
+			"contract:\n  version: \"1\"\n  dependencies:\n    bus:\n      protocol: nats\n      host: nats.example.com\n      port: 4222\n      subject: jobs.new\n" +
+			"    github:\n      protocol: https\n      host: api.github.com\n      port: 443\n",
+	})
+	deps := tentacleDependencies(byName)
+	if got := strings.Join(deps["worker"], ","); got != "api" {
+		t.Errorf("expected worker to depend on api via its Service host, got %q", got)
+	}
+	if got := strings.Join(deps["producer"], ","); got != "worker" {
+		t.Errorf("expected producer to depend on the queue consumer only, got %q", got)
+	}
+	if len(deps["api"]) != 0 {
+		t.Errorf("expected api to have no dependencies, got %v", deps["api"])
+	}
+	if cycle := dependencyCycle(deps); cycle != nil {
+		t.Errorf("unexpected cycle %v", cycle)
+	}
+	if cycle := dependencyCycle(map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}); strings.Join(cycle, " -> ") != "a -> b -> c -> a" {
+		t.Errorf("unexpected cycle %v", cycle)
+	}
+}
+
+func TestRunBatch_FailureSkipsOnlyDependents(t *testing.T) {
+	names := []string{"base", "broken", "mid", "leaf", "other"}
+	dependsOn := map[string][]string{"mid": {"broken"}, "leaf": {"mid", "base"}, "other": {"base"}}
+	byName := map[string]*batchTentacle{}
+	var tentacles []*batchTentacle
+	for _, n := range names {
+		bt := &batchTentacle{entry: &batchDeployEntry{Name: n}, dependsOn: dependsOn[n]}
+		byName[n] = bt
+		tentacles = append(tentacles, bt)
+	}
+	var mu sync.Mutex
+	var order []string
+	runBatch(tentacles, byName, 2, func(bt *batchTentacle) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, d := range bt.dependsOn {
+			if !slices.Contains(order, d) {
+				t.Errorf("%s started before dependency %s was deployed", bt.entry.Name, d)
+			}
+		}
+		order = append(order, bt.entry.Name)
+		if bt.entry.Name == "broken" {
+			return errors.New("apply failed")
+		}
+		return nil
+	})
+
+	want := map[string]string{"base": batchDeployed, "broken": batchFailed, "mid": batchSkipped, "leaf": batchSkipped, "other": batchDeployed}
+	for n, status := range want {
+		if got := byName[n].entry.Status; got != status {
+			t.Errorf("%s: expected %s, got %s (%s)", n, status, got, byName[n].entry.Error)
+		}
+	}
+	if e := byName["mid"].entry.Error; e != "dependency broken failed" {
+		t.Errorf("unexpected skip reason %q", e)
+	}
+	if len(order) != 3 {
+		t.Errorf("expected 3 tentacles deployed or attempted, got %v", order)
+	}
+}
+
+func TestDeployAll_DeploysEnclaveInDependencyOrder(t *testing.T) {
+	srv := deployRunFixture(t)
+	root := filepath.Join(t.TempDir(), "payments")
+	writeTentacle(t, root, "ledger", manualTrigger, "")
+	writeTentacle(t, root, "checkout", manualTrigger,
+		"contract:\n  version: \"1\"\n  dependencies:\n    ledger:\n      protocol: https\n      host: ledger.default.svc.cluster.local\n      port: 8080\n")
+	if err := os.MkdirAll(filepath.Join(root, "node_modules", "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "node_modules", "pkg", "workflow.yaml"), []byte("not: a tentacle\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		_, err = runHistoryCmd(t, NewDeployCmd, root, "--all", "--force", "--runtime-class", "", "--image", "engine:2.0")
+	})
+	if err != nil {
+		t.Fatalf("deploy --all: %v\n%s", err, out)
+	}
+	for _, name := range []string{"ledger", "checkout"} {
+		if wf, ok := srv.Workflow("default", name); !ok || wf.Image != "engine:2.0" {
+			t.Errorf("expected %s deployed with engine:2.0, got %+v (found=%v)", name, wf, ok)
+		}
+	}
+	if !strings.Contains(out, "deployed 2 of 2 tentacles") || !strings.Contains(out, "[checkout] ") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if revs, _ := resolveRevisionStore(context.Background(), LoadConfig(), srv.Client()).list("", "default", "checkout"); len(revs) != 1 {
+		t.Errorf("expected a revision recorded for checkout, got %d", len(revs))
+	}
+}
+
+func TestDeployAll_ValidatesEverythingFirst(t *testing.T) {
+	srv := deployRunFixture(t)
+	root := t.TempDir()
+	writeTentacle(t, root, "good", manualTrigger, "")
+	writeTentacle(t, root, "bad", "  - type: cron\n", "")
+
+	var err error
+	out := captureStdout(t, func() {
+		_, err = runHistoryCmd(t, NewDeployCmd, root, "--all", "--force", "--runtime-class", "")
+	})
+	if err == nil {
+		t.Fatal("expected validation failure")
+	}
+	if !strings.Contains(out, "1 of 2 tentacles failed validation; nothing deployed") || !strings.Contains(out, "✗ bad:") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if _, ok := srv.Workflow("default", "good"); ok {
+		t.Error("expected nothing deployed when any tentacle fails validation")
+	}
+	if _, err := runHistoryCmd(t, NewDeployCmd, root, "--all", "--dry-run"); err == nil || !strings.Contains(err.Error(), "not supported with --all") {
+		t.Errorf("expected --dry-run rejected with --all, got %v", err)
+	}
+}
@@ -0,0 +1,456 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// Diff actions for one manifest.
+const (
+	diffCreate    = "create"
+	diffUpdate    = "update"
+	diffUnchanged = "unchanged"
+	diffLiveOnly  = "live-only" // deployed, but no longer rendered; deploy leaves it in place
+)
+
+// deployDiff compares the locally rendered manifests with the live objects.
+type deployDiff struct {
+	Workflow  string         `json:"workflow"`
+	Namespace string         `json:"namespace"`
+	Manifests []manifestDiff `json:"manifests"`
+	Create    int            `json:"create"`
+	Update    int            `json:"update"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// manifestDiff is the difference for one object. Changes lists field-level
+// differences; ConfigMap data is compared per key in Files instead, and
+// Secrets only by key name in Keys (values are never shown).
+type manifestDiff struct {
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	Action  string        `json:"action"`
+	Summary string        `json:"summary,omitempty"`
+	Changes []fieldChange `json:"changes,omitempty"`
+	Files   []fileDiff    `json:"files,omitempty"`
+	Keys    []string      `json:"keys,omitempty"` // Secret keys, prefixed with +, - or ~
+}
+
+type fieldChange struct {
+	Live  any    `json:"live,omitempty"`
+	Local any    `json:"local,omitempty"`
+	Path  string `json:"path"`
+}
+
+type fileDiff struct {
+	Name    string `json:"name"`
+	Diff    string `json:"diff"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// liveManifests fetches the workflow's deployed objects: from wf_describe
+// over MCP, or with client-go in direct mode. A workflow that is not
+// deployed yet has no live objects.
+func liveManifests(ctx context.Context, client mcp.WorkflowClient, namespace, name string) ([]map[string]any, error) {
+	switch c := client.(type) {
+	case *k8s.DirectClient:
+		return c.LiveManifests(ctx, namespace, name)
+	case *mcp.Client:
+		result, err := c.WfDescribe(ctx, namespace, name)
+		if err != nil {
+			if mcp.IsToolError(err) && strings.Contains(err.Error(), "not found") {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return result.Manifests, nil
+	default:
+		return nil, fmt.Errorf("reading live manifests is not supported by %T", client)
+	}
+}
+
+// diffManifests compares local manifests with live objects. Only fields set
+// in the local manifest are compared, so server-managed and defaulted fields
+// (status, metadata.uid, resourceVersion, managedFields, clusterIP, ...) are
+// ignored.
+func diffManifests(workflow, namespace string, local, live []map[string]any) deployDiff {
+	d := deployDiff{Workflow: workflow, Namespace: namespace}
+	liveByID := map[string]map[string]any{}
+	for _, m := range live {
+		liveByID[manifestKey(m)] = m
+	}
+	seen := map[string]bool{}
+	for _, m := range local {
+		id := manifestKey(m)
+		seen[id] = true
+		md := diffManifest(normalizeManifest(m), liveByID[id])
+		switch md.Action {
+		case diffCreate:
+			d.Create++
+		case diffUpdate:
+			d.Update++
+		default:
+			d.Unchanged++
+		}
+		d.Manifests = append(d.Manifests, md)
+	}
+	for _, m := range live {
+		if !seen[manifestKey(m)] {
+			kind, name := manifestKindName(m)
+			d.Manifests = append(d.Manifests, manifestDiff{Kind: kind, Name: name, Action: diffLiveOnly})
+		}
+	}
+	return d
+}
+
+func manifestKindName(m map[string]any) (kind, name string) {
+	kind, _ = m["kind"].(string)
+	meta, _ := m["metadata"].(map[string]any)
+	name, _ = meta["name"].(string)
+	return kind, name
+}
+
+func manifestKey(m map[string]any) string {
+	kind, name := manifestKindName(m)
+	return kind + "/" + name
+}
+
+// normalizeManifest round-trips a manifest through JSON so numbers compare
+// equal to the live objects (float64).
+func normalizeManifest(m map[string]any) map[string]any {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return m
+	}
+	var out map[string]any
+	if json.Unmarshal(data, &out) != nil {
+		return m
+	}
+	return out
+}
+
+func diffManifest(local, live map[string]any) manifestDiff {
+	kind, name := manifestKindName(local)
+	md := manifestDiff{Kind: kind, Name: name, Action: diffCreate}
+	if live == nil {
+		return md
+	}
+
+	compared := local
+	switch kind {
+	case "ConfigMap":
+		md.Files = diffConfigMapData(name, local, live)
+		compared = without(local, "data")
+	case "Secret":
+		md.Keys = diffSecretKeys(local, live)
+		compared = without(local, "data", "stringData")
+	}
+	compareFields("", compared, live, &md.Changes)
+
+	if len(md.Changes) == 0 && len(md.Files) == 0 && len(md.Keys) == 0 {
+		md.Action = diffUnchanged
+		return md
+	}
+	md.Action = diffUpdate
+	md.Summary = summarizeManifestDiff(&md, local, live)
+	return md
+}
+
+func without(m map[string]any, keys ...string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return out
+}
+
+// compareFields appends the differences of the fields set in local.
+func compareFields(path string, local, live any, out *[]fieldChange) {
+	switch l := local.(type) {
+	case map[string]any:
+		lv, ok := live.(map[string]any)
+		if !ok {
+			*out = append(*out, fieldChange{Path: path, Live: live, Local: local})
+			return
+		}
+		keys := make([]string, 0, len(l))
+		for k := range l {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			if _, ok := lv[k]; !ok {
+				if !isEmptyValue(l[k]) {
+					*out = append(*out, fieldChange{Path: child, Local: l[k]})
+				}
+				continue
+			}
+			compareFields(child, l[k], lv[k], out)
+		}
+	case []any:
+		lv, ok := live.([]any)
+		if !ok {
+			*out = append(*out, fieldChange{Path: path, Live: live, Local: local})
+			return
+		}
+		for i := range l {
+			child := path + "[" + strconv.Itoa(i) + "]"
+			if i >= len(lv) {
+				*out = append(*out, fieldChange{Path: child, Local: l[i]})
+				continue
+			}
+			compareFields(child, l[i], lv[i], out)
+		}
+		for i := len(l); i < len(lv); i++ {
+			*out = append(*out, fieldChange{Path: path + "[" + strconv.Itoa(i) + "]", Live: lv[i]})
+		}
+	default:
+		if fmt.Sprint(local) != fmt.Sprint(live) {
+			*out = append(*out, fieldChange{Path: path, Live: live, Local: local})
+		}
+	}
+}
+
+// isEmptyValue reports values the API server drops on write (null, {}, []).
+func isEmptyValue(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(t) == 0
+	case []any:
+		return len(t) == 0
+	}
+	return false
+}
+
+// diffConfigMapData returns unified diffs of the changed data keys. Keys of
+// the code ConfigMap are shown as workflow file paths (nodes/x.ts).
+func diffConfigMapData(name string, local, live map[string]any) []fileDiff {
+	ld, _ := local["data"].(map[string]any)
+	vd, _ := live["data"].(map[string]any)
+	keys := map[string]bool{}
+	for k := range ld {
+		keys[k] = true
+	}
+	for k := range vd {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var files []fileDiff
+	for _, k := range sorted {
+		file := k
+		if strings.HasSuffix(name, "-code") {
+			file = strings.ReplaceAll(k, "__", "/")
+		}
+		before, _ := vd[k].(string)
+		after, _ := ld[k].(string)
+		aName, bName := "live/"+file, "local/"+file
+		if _, ok := vd[k]; !ok {
+			aName = "/dev/null"
+		}
+		if _, ok := ld[k]; !ok {
+			bName = "/dev/null"
+		}
+		if diff, added, removed := unifiedDiff(aName, bName, before, after); diff != "" {
+			files = append(files, fileDiff{Name: file, Diff: diff, Added: added, Removed: removed})
+		}
+	}
+	return files
+}
+
+// diffSecretKeys compares Secret keys, and values where the live object
+// exposes them, returning "+key", "-key" and "~key" entries.
+func diffSecretKeys(local, live map[string]any) []string {
+	localValues := secretValues(local)
+	liveValues := secretValues(live)
+	var keys []string
+	for k, v := range localValues {
+		lv, ok := liveValues[k]
+		switch {
+		case !ok:
+			keys = append(keys, "+"+k)
+		case lv != v:
+			keys = append(keys, "~"+k)
+		}
+	}
+	for k := range liveValues {
+		if _, ok := localValues[k]; !ok {
+			keys = append(keys, "-"+k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i][1:] < keys[j][1:] })
+	return keys
+}
+
+// secretValues returns a Secret's decoded values from stringData and data.
+func secretValues(m map[string]any) map[string]string {
+	values := map[string]string{}
+	if data, ok := m["data"].(map[string]any); ok {
+		for k, v := range data {
+			s, _ := v.(string)
+			if decoded, err := base64.StdEncoding.DecodeString(s); err == nil {
+				s = string(decoded)
+			}
+			values[k] = s
+		}
+	}
+	if data, ok := m["stringData"].(map[string]any); ok {
+		for k, v := range data {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	return values
+}
+
+// summarizeManifestDiff describes an update in one line, e.g.
+// "image engine:1.0 -> engine:2.0" or "egress +1 host".
+func summarizeManifestDiff(md *manifestDiff, local, live map[string]any) string {
+	var parts []string
+	switch md.Kind {
+	case "Deployment":
+		localImage, _ := deploymentImageAndVersion(local)
+		liveImage, _ := deploymentImageAndVersion(live)
+		if localImage != liveImage {
+			parts = append(parts, fmt.Sprintf("image %s -> %s", orDash(liveImage), orDash(localImage)))
+		}
+	case "NetworkPolicy":
+		if s := peerChange("egress", "to", "host", local, live); s != "" {
+			parts = append(parts, s)
+		}
+		if s := peerChange("ingress", "from", "source", local, live); s != "" {
+			parts = append(parts, s)
+		}
+	case "Secret":
+		parts = append(parts, fmt.Sprintf("keys %s", strings.Join(md.Keys, " ")))
+	}
+	if len(md.Files) > 0 {
+		added, removed := 0, 0
+		for _, f := range md.Files {
+			added += f.Added
+			removed += f.Removed
+		}
+		parts = append(parts, fmt.Sprintf("%d %s changed (+%d -%d)", len(md.Files), plural(len(md.Files), "file"), added, removed))
+	}
+	if len(parts) == 0 && len(md.Changes) > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s changed", len(md.Changes), plural(len(md.Changes), "field")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// peerChange counts NetworkPolicy peers (a peer with its rule's ports)
+// added and removed in the given direction, e.g. "egress +1 host".
+func peerChange(direction, peersField, noun string, local, live map[string]any) string {
+	localPeers, livePeers := policyPeers(local, direction, peersField), policyPeers(live, direction, peersField)
+	added, removed := 0, 0
+	for p, n := range localPeers {
+		added += max(0, n-livePeers[p])
+	}
+	for p, n := range livePeers {
+		removed += max(0, n-localPeers[p])
+	}
+	var parts []string
+	if added > 0 {
+		parts = append(parts, fmt.Sprintf("+%d %s", added, plural(added, noun)))
+	}
+	if removed > 0 {
+		parts = append(parts, fmt.Sprintf("-%d %s", removed, plural(removed, noun)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return direction + " " + strings.Join(parts, " ")
+}
+
+func policyPeers(m map[string]any, direction, peersField string) map[string]int {
+	peers := map[string]int{}
+	spec, _ := m["spec"].(map[string]any)
+	rules, _ := spec[direction].([]any)
+	for _, r := range rules {
+		rule, _ := r.(map[string]any)
+		ports, _ := json.Marshal(rule["ports"])
+		list, _ := rule[peersField].([]any)
+		if len(list) == 0 {
+			list = []any{nil} // a rule without peers allows all
+		}
+		for _, p := range list {
+			peer, _ := json.Marshal(p)
+			peers[string(peer)+string(ports)]++
+		}
+	}
+	return peers
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+// summaryLine returns e.g. "1 to create, 2 to update, 3 unchanged".
+func (d *deployDiff) summaryLine() string {
+	return fmt.Sprintf("%d to create, %d to update, %d unchanged", d.Create, d.Update, d.Unchanged)
+}
+
+// writeDeployDiff prints the diff: + create, ~ update, ! live only.
+func writeDeployDiff(w io.Writer, d *deployDiff) {
+	_, _ = fmt.Fprintf(w, "Diff of %s against namespace %s:\n", d.Workflow, d.Namespace)
+	for _, md := range d.Manifests {
+		switch md.Action {
+		case diffCreate:
+			_, _ = fmt.Fprintf(w, "  + %s/%s (new)\n", md.Kind, md.Name)
+		case diffLiveOnly:
+			_, _ = fmt.Fprintf(w, "  ! %s/%s is deployed but no longer rendered (deploy does not remove it)\n", md.Kind, md.Name)
+		case diffUpdate:
+			_, _ = fmt.Fprintf(w, "  ~ %s/%s: %s\n", md.Kind, md.Name, md.Summary)
+			for _, c := range md.Changes {
+				_, _ = fmt.Fprintf(w, "      %s: %s -> %s\n", c.Path, diffValue(c.Live), diffValue(c.Local))
+			}
+			for _, f := range md.Files {
+				for _, line := range strings.Split(strings.TrimSuffix(f.Diff, "\n"), "\n") {
+					_, _ = fmt.Fprintf(w, "      %s\n", line)
+				}
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(w, "Summary: %s\n", d.summaryLine())
+}
+
+// diffValue renders a field value compactly for the diff listing.
+func diffValue(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	const maxLen = 80
+	if s := string(data); len(s) > maxLen {
+		return s[:maxLen-3] + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+	diff, added, removed := unifiedDiff("live/x", "local/x", a, b)
+	want := `--- live/x
++++ local/x
+@@ -1,6 +1,6 @@
+ one
+ two
+-three
++THREE
+ four
+ five
+ six
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+	if diff != want || added != 2 || removed != 1 {
+		t.Errorf("got (+%d -%d):\n%s\nwant:\n%s", added, removed, diff, want)
+	}
+	if diff, _, _ := unifiedDiff("a", "b", a, a); diff != "" {
+		t.Errorf("expected no diff for equal input, got %q", diff)
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	local := []map[string]any{
+		{"kind": "ConfigMap", "metadata": map[string]any{"name": "wf-code"}, "data": map[string]any{
+			"workflow.yaml": "name: wf\n", "nodes__fetch.ts": "export default 2\n",
+		}},
+		{"kind": "Secret", "metadata": map[string]any{"name": "wf-secrets"}, "stringData": map[string]any{"token": "new", "extra": "x"}},
+		{"kind": "Service", "metadata": map[string]any{"name": "wf"}, "spec": map[string]any{"ports": []any{map[string]any{"port": 8080}}}},
+		{"kind": "NetworkPolicy", "metadata": map[string]any{"name": "wf-netpol"}, "spec": map[string]any{"egress": []any{
+			map[string]any{"to": []any{
+				map[string]any{"ipBlock": map[string]any{"cidr": "10.0.0.1/32"}},
+				map[string]any{"ipBlock": map[string]any{"cidr": "10.0.0.2/32"}},
+			}},
+		}}},
+		{"kind": "Deployment", "metadata": map[string]any{"name": "wf"}},
+	}
+	live := []map[string]any{
+		{"kind": "ConfigMap", "metadata": map[string]any{"name": "wf-code", "uid": "abc", "resourceVersion": "7"}, "data": map[string]any{
+			"workflow.yaml": "name: wf\n", "nodes__fetch.ts": "export default 1\n",
+		}},
+		{"kind": "Secret", "metadata": map[string]any{"name": "wf-secrets"}, "data": map[string]any{"token": "b2xk", "stale": "eA=="}},
+		{"kind": "Service", "metadata": map[string]any{"name": "wf"}, "spec": map[string]any{
+			"clusterIP": "10.96.0.10", "ports": []any{map[string]any{"port": float64(8080), "protocol": "TCP"}},
+		}, "status": map[string]any{}},
+		{"kind": "NetworkPolicy", "metadata": map[string]any{"name": "wf-netpol"}, "spec": map[string]any{"egress": []any{
+			map[string]any{"to": []any{map[string]any{"ipBlock": map[string]any{"cidr": "10.0.0.1/32"}}}},
+		}}},
+		{"kind": "ConfigMap", "metadata": map[string]any{"name": "wf-old"}},
+	}
+
+	d := diffManifests("wf", "prod", local, live)
+	if d.Create != 1 || d.Update != 3 || d.Unchanged != 1 {
+		t.Fatalf("expected 1 create, 3 updates, 1 unchanged, got %s", d.summaryLine())
+	}
+	byName := map[string]manifestDiff{}
+	for _, md := range d.Manifests {
+		byName[md.Kind+"/"+md.Name] = md
+	}
+	if code := byName["ConfigMap/wf-code"]; len(code.Files) != 1 || code.Files[0].Name != "nodes/fetch.ts" || code.Summary != "1 file changed (+1 -1)" || len(code.Changes) != 0 {
+		t.Errorf("unexpected code diff: %+v", code)
+	}
+	if s := byName["Secret/wf-secrets"]; strings.Join(s.Keys, " ") != "+extra -stale ~token" || strings.Contains(s.Summary, "new") {
+		t.Errorf("unexpected secret diff: %+v", s)
+	}
+	if svc := byName["Service/wf"]; svc.Action != diffUnchanged {
+		t.Errorf("expected server-managed fields ignored, got %+v", svc)
+	}
+	if np := byName["NetworkPolicy/wf-netpol"]; np.Summary != "egress +1 host" {
+		t.Errorf("unexpected netpol summary: %q", np.Summary)
+	}
+	if old := byName["ConfigMap/wf-old"]; old.Action != diffLiveOnly {
+		t.Errorf("expected live-only ConfigMap, got %+v", old)
+	}
+}
+
+func TestDeployDryRun(t *testing.T) {
+	srv := deployRunFixture(t)
+
+	var err error
+	out := captureStdout(t, func() { err = runDeployCmd(t, "--image", "engine:2.0", "--dry-run") })
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected --dry-run to apply nothing, live image is %s", wf.Image)
+	}
+	for _, want := range []string{
+		"~ Deployment/test-workflow: image engine:1.0 -> engine:2.0",
+		`spec.template.spec.containers[0].image: "engine:1.0" -> "engine:2.0"`,
+		"Summary: 0 to create, 1 to update,",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if revs, _ := resolveRevisionStore(context.Background(), LoadConfig(), srv.Client()).list("", "default", "test-workflow"); len(revs) != 0 {
+		t.Errorf("expected no revision recorded by --dry-run, got %d", len(revs))
+	}
+
+	// A workflow that is not deployed yet diffs as all new.
+	home, _ := os.UserHomeDir()
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte("namespace: other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := withRootFlags(NewDeployCmd())
+	cmd.SetArgs([]string{".", "--force", "--runtime-class", "", "--dry-run", "-o", "json"})
+	cmd.SetOut(&bytes.Buffer{})
+	out = captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	if err != nil || !strings.Contains(out, `"summary":"dry run: 5 to create, 0 to update, 0 unchanged"`) {
+		t.Errorf("expected all objects to be created in a new namespace, got %v:\n%s", err, out)
+	}
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// Post-deploy gates, in the order they run.
+const (
+	gateReady  = "ready"
+	gateVerify = "verify"
+	gateSoak   = "soak"
+)
+
+// postDeployGates are the checks a deploy must pass once applied.
+type postDeployGates struct {
+	ready        bool
+	readyTimeout time.Duration
+	verify       bool
+	verifyInput  json.RawMessage
+	soak         time.Duration
+	rollback     bool
+}
+
+func (g postDeployGates) enabled() bool {
+	return g.ready || g.verify || g.soak > 0
+}
+
+// resolvePostDeployGates combines --wait, --verify, --verify-input, --soak
+// and --no-rollback with the environment's post_deploy config. The
+// verification input is --verify-input, else the configured fixture, else
+// --input (runInput).
+func resolvePostDeployGates(cmd *cobra.Command, cfg PostDeployConfig, workflowDir string, runInput json.RawMessage) (postDeployGates, error) {
+	g := postDeployGates{verifyInput: runInput}
+	g.ready, _ = cmd.Flags().GetBool("wait")
+	g.readyTimeout, _ = cmd.Flags().GetDuration("wait-timeout")
+	if cfg.ReadyTimeout > 0 {
+		g.ready = true
+		if !cmd.Flags().Changed("wait-timeout") {
+			g.readyTimeout = cfg.ReadyTimeout
+		}
+	}
+
+	g.verify, _ = cmd.Flags().GetBool("verify")
+	fixture, label := flagString(cmd, "verify-input"), "--verify-input"
+	if fixture == "" && cfg.VerifyInput != "" {
+		fixture, label = cfg.VerifyInput, "post_deploy.verify_input"
+		if !filepath.IsAbs(fixture) {
+			fixture = filepath.Join(workflowDir, fixture)
+		}
+	}
+	if fixture != "" {
+		input, err := loadInputFixture(fixture, label, workflowDir)
+		if err != nil {
+			return g, err
+		}
+		g.verify, g.verifyInput = true, input
+	}
+
+	g.soak = cfg.Soak
+	if cmd.Flags().Changed("soak") {
+		g.soak, _ = cmd.Flags().GetDuration("soak")
+	}
+
+	noRollback, _ := cmd.Flags().GetBool("no-rollback")
+	g.rollback = !noRollback && !cfg.NoRollback
+	return g, nil
+}
+
+// gateResult is the outcome of one post-deploy gate.
+type gateResult struct {
+	Gate       string `json:"gate"`   // "ready", "verify" or "soak"
+	Status     string `json:"status"` // "pass" or "fail"
+	Detail     string `json:"detail,omitempty"`
+	Output     any    `json:"output,omitempty"` // the verification run's output when it failed
+	DurationMs int64  `json:"durationMs"`
+}
+
+// gateRollback reports the automatic rollback after a failed gate.
+type gateRollback struct {
+	Status   string `json:"status"`             // "pass", "fail" or "skipped"
+	Revision int    `json:"revision,omitempty"` // revision re-applied
+	Detail   string `json:"detail,omitempty"`
+}
+
+func (r *gateRollback) summary() string {
+	switch r.Status {
+	case "pass":
+		return fmt.Sprintf("rolled back to revision %d", r.Revision)
+	case "fail":
+		return fmt.Sprintf("rollback to revision %d failed: %s", r.Revision, r.Detail)
+	}
+	return "not rolled back: " + r.Detail
+}
+
+// postDeployReport is the deploy result's execution when gates ran.
+type postDeployReport struct {
+	Gates    []gateResult  `json:"gates"`
+	Rollback *gateRollback `json:"rollback,omitempty"`
+}
+
+// failed returns the gate that failed, or nil.
+func (r *postDeployReport) failed() *gateResult {
+	for i := range r.Gates {
+		if r.Gates[i].Status == "fail" {
+			return &r.Gates[i]
+		}
+	}
+	return nil
+}
+
+// runPostDeployGates runs the readiness, verification and soak gates in
+// order against the deployed workflow, stopping at the first failure.
+// runner executes the verification run and is nil with --direct.
+func runPostDeployGates(cmd *cobra.Command, client mcp.WorkflowClient, runner *mcp.Client, namespace, name string, g postDeployGates, w io.Writer) *postDeployReport {
+	ctx := cmd.Context()
+	report := &postDeployReport{Gates: []gateResult{}}
+	check := func(gate string, fn func() (any, error)) bool {
+		start := time.Now()
+		output, err := fn()
+		r := gateResult{Gate: gate, Status: "pass", DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			r.Status, r.Detail, r.Output = "fail", err.Error(), output
+		}
+		report.Gates = append(report.Gates, r)
+		return err == nil
+	}
+
+	if g.ready && !check(gateReady, func() (any, error) {
+		_, _ = fmt.Fprintln(w, "Waiting for rollout...")
+		if _, err := watchRollout(ctx, client, namespace, name, watchPollInterval, g.readyTimeout, w); err != nil {
+			return nil, err
+		}
+		_, _ = fmt.Fprintln(w, "  Rollout complete")
+		return nil, nil
+	}) {
+		return report
+	}
+
+	if g.verify && !check(gateVerify, func() (any, error) {
+		_, _ = fmt.Fprintln(w, "Verifying deployment...")
+		runStartedAt := time.Now()
+		runResult, runErr := runner.WfRun(ctx, namespace, name, g.verifyInput, 120)
+		recordRun(cmd, runTriggerDeployVerify, runResult, runErr, namespace, name, g.verifyInput, runStartedAt)
+		if runErr != nil {
+			return nil, fmt.Errorf("workflow run failed: %w", runErr)
+		}
+		var execResult map[string]any
+		if json.Unmarshal(runResult.Output, &execResult) == nil {
+			if success, ok := execResult["success"].(bool); ok && !success {
+				return execResult, errors.New("workflow returned success=false")
+			}
+		}
+		_, _ = fmt.Fprintln(w, "  Verification passed")
+		return nil, nil
+	}) {
+		return report
+	}
+
+	if g.soak > 0 {
+		check(gateSoak, func() (any, error) {
+			_, _ = fmt.Fprintf(w, "Soaking for %s...\n", g.soak)
+			if err := soakPods(ctx, client, namespace, name, g.soak, watchPollInterval); err != nil {
+				return nil, err
+			}
+			_, _ = fmt.Fprintf(w, "  No restarts over %s\n", g.soak)
+			return nil, nil
+		})
+	}
+	return report
+}
+
+// soakPods polls the workflow's pods for window and fails on any container
+// restart, counted from the first poll, or rollout failure reason.
+func soakPods(ctx context.Context, client mcp.WorkflowClient, namespace, name string, window, interval time.Duration) error {
+	deadline := time.Now().Add(window)
+	var baseline map[string]int32
+	for {
+		status, err := client.WfStatus(ctx, namespace, name, true)
+		if err != nil {
+			return fmt.Errorf("getting status: %w", err)
+		}
+		if pod, reason := failedPod(status); reason != "" {
+			return fmt.Errorf("pod %s is in %s", pod, reason)
+		}
+		if baseline == nil {
+			baseline = map[string]int32{}
+			for _, p := range status.Pods {
+				baseline[p.Name] = p.Restarts
+			}
+		}
+		for _, p := range status.Pods {
+			if restarts := p.Restarts - baseline[p.Name]; restarts > 0 {
+				return fmt.Errorf("pod %s restarted %d time(s) during the soak", p.Name, restarts)
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(interval, remaining)):
+		}
+	}
+}
+
+// rollBackFailedDeploy re-applies previous, the revision live before the
+// deploy, after a failed gate. held is the deploy's locker: re-taking its
+// lock with the same lock ID renews it, so the rollback runs under the
+// deploy's lock and is refused if another deploy took the lock meanwhile.
+func rollBackFailedDeploy(cmd *cobra.Command, client mcp.WorkflowClient, held deployLocker, previous *deployRevision, cluster string, w io.Writer) *gateRollback {
+	if previous == nil {
+		return &gateRollback{Status: "skipped", Detail: "no previous revision to roll back to"}
+	}
+	// The deploy releases the lock when it returns.
+	if _, err := held.lockCluster(cmd.Context(), client, previous.Namespace, previous.Workflow); err != nil {
+		return &gateRollback{Status: "fail", Revision: previous.Revision, Detail: err.Error()}
+	}
+	_, _ = fmt.Fprintf(w, "Rolling back %s in %s to revision %d (%s)...\n", previous.Workflow, previous.Namespace, previous.Revision, orDash(previous.Version))
+	if err := reapplyRevision(cmd, client, previous, cluster, w); err != nil {
+		return &gateRollback{Status: "fail", Revision: previous.Revision, Detail: err.Error()}
+	}
+	return &gateRollback{Status: "pass", Revision: previous.Revision}
+}
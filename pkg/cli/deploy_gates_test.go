@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeploy_FailedVerifyGateRollsBack(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := runDeployCmd(t, "--image", "engine:1.0"); err != nil {
+		t.Fatalf("first deploy: %v", err)
+	}
+	if err := srv.SetRunOutput("default", "test-workflow", json.RawMessage(`{"success":false}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("fixture.json", []byte(`{"url":"https://example.com"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		_, err = runHistoryCmd(t, NewDeployCmd, ".", "--force", "--runtime-class", "", "--image", "engine:2.0", "--verify-input", "fixture.json", "-o", "json")
+	})
+	if err == nil {
+		t.Fatal("expected the failed verification to fail the deploy")
+	}
+	var result struct {
+		Status    string           `json:"status"`
+		Summary   string           `json:"summary"`
+		Execution postDeployReport `json:"execution"`
+	}
+	if jsonErr := json.Unmarshal([]byte(out), &result); jsonErr != nil {
+		t.Fatalf("decoding result: %v\n%s", jsonErr, out)
+	}
+	if result.Status != "fail" || !strings.Contains(result.Summary, "post-deploy gate verify failed: workflow returned success=false; rolled back to revision 1") {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	if gates := result.Execution.Gates; len(gates) != 1 || gates[0].Gate != gateVerify || gates[0].Status != "fail" {
+		t.Errorf("unexpected gates %+v", gates)
+	}
+	if rb := result.Execution.Rollback; rb == nil || rb.Status != "pass" || rb.Revision != 1 {
+		t.Errorf("unexpected rollback %+v", rb)
+	}
+
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected engine:1.0 live after the rollback, got %s", wf.Image)
+	}
+	history, _ := runHistoryCmd(t, NewHistoryCmd, "test-workflow")
+	if !strings.Contains(history, "rollback:1") {
+		t.Errorf("expected the rollback recorded as a revision:\n%s", history)
+	}
+
+	// The fixture is validated against the input schema before deploying.
+	if err := os.WriteFile("fixture.json", []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runDeployCmd(t, "--image", "engine:3.0", "--verify-input", "fixture.json"); err == nil || !strings.Contains(err.Error(), "input schema") {
+		t.Errorf("expected the fixture rejected by the input schema, got %v", err)
+	}
+}
+
+func TestDeploy_SoakGateFromConfigFailsOnRestart(t *testing.T) {
+	srv := deployRunFixture(t)
+	orig := watchPollInterval
+	watchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { watchPollInterval = orig })
+	home, _ := os.UserHomeDir()
+	cfg := "default_cluster: prod\nclusters:\n  prod:\n    namespace: default\n    post_deploy:\n      soak: 5s\n"
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Crash the new pod once after the soak's first poll.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image == "engine:2.0" && srv.Calls("wf_status") > 0 {
+				_ = srv.SetPodReason("default", "test-workflow", "CrashLoopBackOff")
+				_ = srv.SetPodReason("default", "test-workflow", "")
+				return
+			}
+		}
+	}()
+
+	var err error
+	captureStdout(t, func() { err = runDeployCmd(t, "--image", "engine:2.0") })
+	<-done
+	if err == nil || !strings.Contains(err.Error(), "post-deploy gate soak failed: pod") || !strings.Contains(err.Error(), "restarted 1 time(s)") {
+		t.Fatalf("expected the soak gate to fail on the restart, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "not rolled back: no previous revision") {
+		t.Errorf("expected no rollback on a first deploy, got %v", err)
+	}
+}
@@ -179,19 +179,7 @@ func injectGitAnnotations(mcpManifests []map[string]any, meta GitMeta) {
 		if kind != "Deployment" {
 			continue
 		}
-
-		// Navigate (and create if absent) metadata.annotations.
-		metadata, ok := obj["metadata"].(map[string]any)
-		if !ok {
-			metadata = make(map[string]any)
-			obj["metadata"] = metadata
-		}
-		annotations, ok := metadata["annotations"].(map[string]any)
-		if !ok {
-			annotations = make(map[string]any)
-			metadata["annotations"] = annotations
-		}
-
+		annotations := manifestAnnotations(obj)
 		annotations["tentacular.io/git-sha"] = meta.SHA
 		if meta.Repo != "" {
 			annotations["tentacular.io/git-repo"] = meta.Repo
@@ -201,3 +189,19 @@ func injectGitAnnotations(mcpManifests []map[string]any, meta GitMeta) {
 		}
 	}
 }
+
+// manifestAnnotations returns the metadata.annotations map of a manifest,
+// creating metadata and annotations if absent.
+func manifestAnnotations(obj map[string]any) map[string]any {
+	metadata, ok := obj["metadata"].(map[string]any)
+	if !ok {
+		metadata = make(map[string]any)
+		obj["metadata"] = metadata
+	}
+	annotations, ok := metadata["annotations"].(map[string]any)
+	if !ok {
+		annotations = make(map[string]any)
+		metadata["annotations"] = annotations
+	}
+	return annotations
+}
@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+const (
+	phaseLiveTest = "live-test"
+
+	defaultLiveTestEnvironment = "dev"
+	defaultLiveTestTimeout     = 120 * time.Second
+)
+
+// deployPhase is one step of a deploy, reported in the result's phases.
+type deployPhase struct {
+	Phase       string        `json:"phase"`
+	Status      string        `json:"status"` // "pass", "fail" or "skipped"
+	Detail      string        `json:"detail,omitempty"`
+	Environment string        `json:"environment,omitempty"`
+	Namespace   string        `json:"namespace,omitempty"`
+	Runs        []liveTestRun `json:"runs,omitempty"`
+	DurationMs  int64         `json:"durationMs"`
+}
+
+// liveTestRun is one run of the live test, with one fixture.
+type liveTestRun struct {
+	Fixture     string   `json:"fixture,omitempty"` // as configured; empty for --input
+	Status      string   `json:"status"`
+	ExecutionID string   `json:"executionId,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Failures    []string `json:"failures,omitempty"` // expectations the output did not meet
+	DurationMs  int64    `json:"durationMs"`
+}
+
+type liveTestFixture struct {
+	name  string
+	input json.RawMessage
+}
+
+// liveTestGate is the pre-deploy live test of a target environment.
+type liveTestGate struct {
+	envName  string
+	env      *EnvironmentConfig
+	fixtures []liveTestFixture
+	timeout  time.Duration
+	expect   map[string]any
+}
+
+// resolveLiveTestGate reads the live_test config of the target environment.
+// Without an environment to test in the gate is nil and the reason is
+// returned, unless live_test names that environment explicitly. --input
+// (inputSet) replaces the configured fixtures.
+func resolveLiveTestGate(cfg TentacularConfig, target, workflowDir string, runInput json.RawMessage, inputSet bool) (*liveTestGate, string, error) {
+	lt := cfg.Clusters[target].LiveTest
+	envName := lt.Environment
+	if envName == "" {
+		envName = defaultLiveTestEnvironment
+	}
+	env, err := cfg.LoadEnvironment(envName)
+	if err == nil && env.Namespace == "" {
+		err = fmt.Errorf("environment %q has no namespace", envName)
+	}
+	if err != nil {
+		if lt.Environment != "" {
+			return nil, "", fmt.Errorf("live_test.environment: %w", err)
+		}
+		return nil, fmt.Sprintf("no %s environment configured", envName), nil
+	}
+
+	gate := &liveTestGate{envName: envName, env: env, timeout: lt.Timeout, expect: lt.Expect}
+	if gate.timeout <= 0 {
+		gate.timeout = defaultLiveTestTimeout
+	}
+	for path := range lt.Expect {
+		if _, err := parseJSONPath(path); err != nil {
+			return nil, "", fmt.Errorf("live_test.expect: %w", err)
+		}
+	}
+	if inputSet || len(lt.Fixtures) == 0 {
+		gate.fixtures = []liveTestFixture{{input: runInput}}
+		return gate, "", nil
+	}
+	for _, name := range lt.Fixtures {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workflowDir, path)
+		}
+		input, err := loadInputFixture(path, "live_test fixture", workflowDir)
+		if err != nil {
+			return nil, "", err
+		}
+		gate.fixtures = append(gate.fixtures, liveTestFixture{name: name, input: input})
+	}
+	return gate, "", nil
+}
+
+// runLiveTestGate deploys the workflow to the gate's environment, runs it
+// once per fixture, stopping at the first failure, and removes it again.
+// The environment's own MCP server is used when it names one.
+func runLiveTestGate(cmd *cobra.Command, gate *liveTestGate, workflowDir, image string, target *mcp.Client, w io.Writer) deployPhase {
+	start := time.Now()
+	phase := deployPhase{Phase: phaseLiveTest, Status: "pass", Environment: gate.envName, Namespace: gate.env.Namespace}
+	finish := func(detail string) deployPhase {
+		if detail != "" {
+			phase.Status, phase.Detail = "fail", detail
+		}
+		phase.DurationMs = time.Since(start).Milliseconds()
+		return phase
+	}
+
+	client := target
+	if gate.env.MCPEndpoint != "" {
+		var err error
+		if client, err = buildMCPClientForEnv(gate.envName); err != nil {
+			return finish(err.Error())
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Running pre-deploy live test in %s environment...\n", gate.envName)
+	liveOpts := InternalDeployOptions{
+		Namespace:    gate.env.Namespace,
+		Image:        image,
+		RuntimeClass: gate.env.RuntimeClass,
+		StatusOut:    w,
+	}
+	liveResult, err := deployWorkflow(workflowDir, liveOpts, client)
+	if err != nil {
+		return finish(fmt.Sprintf("deploying to %s: %v", gate.envName, err))
+	}
+	// Clean up the test deployment regardless of run outcome
+	defer func() {
+		_, _ = client.WfRemove(context.WithoutCancel(cmd.Context()), liveResult.Namespace, liveResult.WorkflowName)
+	}()
+
+	for _, f := range gate.fixtures {
+		run := runLiveTestFixture(cmd, client, liveResult.Namespace, liveResult.WorkflowName, f, gate)
+		phase.Runs = append(phase.Runs, run)
+		if run.Status == "fail" {
+			if f.name != "" {
+				return finish(f.name + ": " + run.Error)
+			}
+			return finish(run.Error)
+		}
+	}
+	_, _ = fmt.Fprintln(w, "  Pre-deploy live test passed")
+	return finish("")
+}
+
+func runLiveTestFixture(cmd *cobra.Command, client *mcp.Client, namespace, name string, f liveTestFixture, gate *liveTestGate) liveTestRun {
+	runStartedAt := time.Now()
+	timeoutSeconds := int((gate.timeout + time.Second - 1) / time.Second)
+	result, err := client.WfRun(cmd.Context(), namespace, name, f.input, timeoutSeconds)
+	recordRun(cmd, runTriggerDeployGate, result, err, namespace, name, f.input, runStartedAt)
+	run := liveTestRun{Fixture: f.name, Status: "pass", DurationMs: time.Since(runStartedAt).Milliseconds()}
+	if err != nil {
+		run.Status, run.Error = "fail", "workflow run failed: "+err.Error()
+		return run
+	}
+	run.ExecutionID = result.ExecutionID
+	if failures := checkExpectations(result.Output, gate.expect); len(failures) > 0 {
+		run.Status, run.Failures, run.Error = "fail", failures, strings.Join(failures, "; ")
+	}
+	return run
+}
+
+// checkExpectations returns a failure for output reporting success=false and
+// for each JSONPath expectation the output does not meet. Output that is not
+// JSON only fails when there are expectations.
+func checkExpectations(output json.RawMessage, expect map[string]any) []string {
+	var doc any
+	if err := json.Unmarshal(output, &doc); err != nil {
+		if len(expect) == 0 {
+			return nil
+		}
+		return []string{"output is not JSON"}
+	}
+	var failures []string
+	if result, ok := doc.(map[string]any); ok {
+		if success, ok := result["success"].(bool); ok && !success {
+			failures = append(failures, "workflow returned success=false")
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(expect)) {
+		got, err := evalJSONPath(doc, path)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		want := normalizeJSON(expect[path])
+		if !reflect.DeepEqual(got, want) {
+			failures = append(failures, fmt.Sprintf("%s: expected %s, got %s", path, compactJSON(want), compactJSON(got)))
+		}
+	}
+	return failures
+}
+
+// parseJSONPath parses a JSONPath expression such as $.result.items[0].id.
+func parseJSONPath(path string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+	jp := jsonpath.New(path)
+	if err := jp.Parse("{" + path + "}"); err != nil {
+		return nil, fmt.Errorf("parsing JSONPath %q: %w", path, err)
+	}
+	return jp, nil
+}
+
+// evalJSONPath returns the value at path in doc; a path matching several
+// values (a wildcard or filter) returns them as a list.
+func evalJSONPath(doc any, path string) (any, error) {
+	jp, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	values := []any{}
+	for _, r := range results {
+		for _, v := range r {
+			values = append(values, v.Interface())
+		}
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// normalizeJSON converts a value decoded from YAML to its JSON-decoded form
+// (numbers as float64), so it compares equal to workflow output.
+func normalizeJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if json.Unmarshal(data, &out) != nil {
+		return v
+	}
+	return out
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckExpectations(t *testing.T) {
+	output := json.RawMessage(`{"success":true,"result":{"count":3,"items":[{"status":"ok"},{"status":"stale"}]}}`)
+	expect := map[string]any{
+		"$.result.count":            3,
+		"$.result.items[0].status":  "ok",
+		"$.result.items[*].status":  []any{"ok", "stale"},
+		"$.result.items[1].missing": nil,
+	}
+	failures := checkExpectations(output, expect)
+	if len(failures) != 1 || !strings.HasPrefix(failures[0], "$.result.items[1].missing: ") {
+		t.Errorf("expected only the missing key to fail, got %v", failures)
+	}
+
+	failures = checkExpectations(output, map[string]any{"$.result.count": 4})
+	if strings.Join(failures, "; ") != "$.result.count: expected 4, got 3" {
+		t.Errorf("unexpected failures %v", failures)
+	}
+	if failures := checkExpectations(json.RawMessage(`{"success":false}`), nil); len(failures) != 1 {
+		t.Errorf("expected success=false to fail without expectations, got %v", failures)
+	}
+	if failures := checkExpectations(json.RawMessage(`plain text`), nil); len(failures) != 0 {
+		t.Errorf("expected non-JSON output to pass without expectations, got %v", failures)
+	}
+	if _, err := parseJSONPath("result.count"); err == nil {
+		t.Error("expected a path not rooted at $ rejected")
+	}
+}
+
+func TestDeploy_LiveTestGateFromTargetConfig(t *testing.T) {
+	srv := deployRunFixture(t)
+	home, _ := os.UserHomeDir()
+	cfg := `default_cluster: prod
+clusters:
+  prod:
+    namespace: default
+    live_test:
+      environment: staging
+      fixtures: [fixtures/smoke.json]
+      timeout: 30s
+      expect:
+        $.result.count: 3
+  staging:
+    namespace: staging
+`
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("fixtures", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("fixtures", "smoke.json"), []byte(`{"url":"https://example.com"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deploy := func() (map[string]any, error) {
+		var err error
+		out := captureStdout(t, func() {
+			_, err = runHistoryCmd(t, NewDeployCmd, ".", "--runtime-class", "", "--image", "engine:2.0", "-o", "json")
+		})
+		var result map[string]any
+		if jsonErr := json.Unmarshal([]byte(out), &result); jsonErr != nil {
+			t.Fatalf("decoding result: %v\n%s", jsonErr, out)
+		}
+		return result, err
+	}
+
+	// A staging copy that already returns the expected output is updated,
+	// run with the fixture and removed.
+	var status bytes.Buffer
+	if _, err := deployWorkflow(".", InternalDeployOptions{Namespace: "staging", Image: "engine:1.0", StatusOut: &status}, srv.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetRunOutput("staging", "test-workflow", json.RawMessage(`{"success":true,"result":{"count":3}}`)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := deploy()
+	if err != nil {
+		t.Fatalf("deploy: %v (%v)", err, result["summary"])
+	}
+	phases, _ := result["phases"].([]any)
+	if len(phases) != 1 {
+		t.Fatalf("expected the live test recorded in phases, got %v", result["phases"])
+	}
+	phase, _ := phases[0].(map[string]any)
+	runs, _ := phase["runs"].([]any)
+	if phase["phase"] != phaseLiveTest || phase["status"] != "pass" || phase["environment"] != "staging" || len(runs) != 1 {
+		t.Errorf("unexpected phase %v", phase)
+	}
+	if run, _ := runs[0].(map[string]any); run["fixture"] != "fixtures/smoke.json" {
+		t.Errorf("unexpected run %v", run)
+	}
+	if _, ok := srv.Workflow("staging", "test-workflow"); ok {
+		t.Error("expected the live-test deployment removed")
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:2.0" {
+		t.Errorf("expected the target deployed, got image %s", wf.Image)
+	}
+
+	// A fresh staging copy returns the default output, which misses the
+	// expectation; the target is left alone.
+	result, err = deploy()
+	if err == nil {
+		t.Fatal("expected the live test to fail the deploy")
+	}
+	if summary, _ := result["summary"].(string); !strings.Contains(summary, "pre-deploy live test failed: fixtures/smoke.json: $.result.count: result is not found") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if phases, _ := result["phases"].([]any); len(phases) != 1 || phases[0].(map[string]any)["status"] != "fail" {
+		t.Errorf("expected a failed live-test phase, got %v", result["phases"])
+	}
+	if revs, _ := resolveRevisionStore(context.Background(), LoadConfig(), srv.Client()).list("prod", "default", "test-workflow"); len(revs) != 1 {
+		t.Errorf("expected no revision recorded for the failed deploy, got %d", len(revs))
+	}
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// defaultDeployLockTTL bounds how long a deploy that died without releasing
+// its lock blocks everyone else.
+const defaultDeployLockTTL = 10 * time.Minute
+
+// deployLocker takes the per-tentacle deploy locks: a lock on the target
+// cluster (wf_lock over MCP, a Lease with --direct) and, with git-state, a
+// lock in the git-state repo. Each deploy has its own lock ID, so a lock is
+// only ever released by the deploy that took it.
+type deployLocker struct {
+	w      io.Writer
+	holder string
+	lockID string
+	ttl    time.Duration
+	steal  bool
+}
+
+// addLockFlags adds --lock-ttl and --steal to a command that applies a
+// workflow under its deploy lock.
+func addLockFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("lock-ttl", defaultDeployLockTTL, "How long the deploy lock is held before others may take it over")
+	cmd.Flags().Bool("steal", false, "Break a deploy lock held by someone else (break-glass)")
+}
+
+// newDeployLocker reads --lock-ttl and --steal and identifies the holder as
+// the deployer for cluster.
+func newDeployLocker(cmd *cobra.Command, cluster string) deployLocker {
+	ttl, _ := cmd.Flags().GetDuration("lock-ttl")
+	if ttl <= 0 {
+		ttl = defaultDeployLockTTL
+	}
+	steal, _ := cmd.Flags().GetBool("steal")
+	holder := resolveDeployerIdentity(cluster).String()
+	if host, err := os.Hostname(); err == nil && host != "" {
+		holder += " on " + host
+	}
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return deployLocker{w: StatusWriter(cmd), holder: holder, lockID: hex.EncodeToString(id), ttl: ttl, steal: steal}
+}
+
+func (l deployLocker) params(namespace, name string) mcp.WfLockParams {
+	return mcp.WfLockParams{
+		Namespace:  namespace,
+		Name:       name,
+		Holder:     l.holder,
+		LockID:     l.lockID,
+		TTLSeconds: int(l.ttl / time.Second),
+		Steal:      l.steal,
+	}
+}
+
+// lockCluster takes the deploy lock of namespace/name on the cluster behind
+// client. A server without wf_lock is warned about, not fatal. The returned
+// release func is never nil.
+func (l deployLocker) lockCluster(ctx context.Context, client mcp.WorkflowClient, namespace, name string) (release func(), err error) {
+	locker, ok := client.(mcp.DeployLocker)
+	if !ok {
+		return func() {}, nil
+	}
+	result, err := locker.WfLock(ctx, l.params(namespace, name))
+	if mcp.IsServerTooOld(err) {
+		_, _ = fmt.Fprintln(l.w, "WARNING: the MCP server does not support deploy locks (wf_lock); deploying without a lock")
+		return func() {}, nil
+	}
+	if err != nil {
+		return func() {}, fmt.Errorf("acquiring deploy lock: %w", err)
+	}
+	target := namespace + "/" + name
+	if !result.Acquired {
+		return func() {}, lockHeldError(target, result.Lock)
+	}
+	l.warnStolen(target, result.StolenFrom)
+	return func() {
+		_, _ = locker.WfUnlock(context.WithoutCancel(ctx), namespace, name, l.lockID)
+	}, nil
+}
+
+// lockGitState takes the deploy lock of enclave/name in the git-state repo:
+// a lock file under the repo's git directory, shared by every deploy run
+// from that checkout and never committed.
+func (l deployLocker) lockGitState(repoPath, enclave, name string) (release func(), err error) {
+	dir, err := gitStateLockDir(repoPath)
+	if err != nil {
+		return func() {}, err
+	}
+	path := filepath.Join(dir, filepath.Base(enclave), filepath.Base(name)+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return func() {}, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	current, err := readLockFile(path)
+	if err != nil {
+		return func() {}, err
+	}
+	target := enclave + "/" + name
+	result := mcp.GrantLock(current, l.params(enclave, name), time.Now())
+	if !result.Acquired {
+		return func() {}, lockHeldError(target+" (git-state)", result.Lock)
+	}
+	data, err := json.MarshalIndent(result.Lock, "", "  ")
+	if err != nil {
+		return func() {}, fmt.Errorf("encoding lock: %w", err)
+	}
+	if current == nil {
+		// O_EXCL: of two deploys racing for a free lock, one create fails.
+		f, createErr := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // path is within the git directory
+		if errors.Is(createErr, os.ErrExist) {
+			return l.lockGitState(repoPath, enclave, name)
+		}
+		if createErr != nil {
+			return func() {}, fmt.Errorf("writing lock: %w", createErr)
+		}
+		_, err = f.Write(data)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	} else {
+		tmp := path + "." + l.lockID
+		if err = os.WriteFile(tmp, data, 0o600); err == nil {
+			err = os.Rename(tmp, path)
+		}
+	}
+	if err != nil {
+		return func() {}, fmt.Errorf("writing lock: %w", err)
+	}
+	// Taking over an expired or stolen lock can race; the last writer wins.
+	if now, readErr := readLockFile(path); readErr != nil || now == nil || now.LockID != l.lockID {
+		if now != nil {
+			return func() {}, lockHeldError(target+" (git-state)", *now)
+		}
+		return func() {}, fmt.Errorf("deploy lock for %s changed while acquiring it; retry", target)
+	}
+	l.warnStolen(target+" (git-state)", result.StolenFrom)
+	return func() {
+		if held, _ := readLockFile(path); held != nil && held.LockID == l.lockID {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
+
+func (l deployLocker) warnStolen(target string, from *mcp.DeployLock) {
+	if from != nil {
+		_, _ = fmt.Fprintf(l.w, "WARNING: --steal broke the deploy lock on %s held by %s since %s\n", target, from.Holder, from.AcquiredAt)
+	}
+}
+
+// lockHeldError explains who holds a lock and how to proceed.
+func lockHeldError(target string, lock mcp.DeployLock) error {
+	expires := lock.ExpiresAt
+	if exp, err := time.Parse(time.RFC3339, lock.ExpiresAt); err == nil {
+		expires = "in " + time.Until(exp).Round(time.Second).String()
+	}
+	return fmt.Errorf("deploy of %s is locked by %s since %s (expires %s); wait for that deploy to finish, or re-run with --steal to break the lock",
+		target, orDash(lock.Holder), orDash(lock.AcquiredAt), orDash(expires))
+}
+
+// gitStateLockDir returns the lock directory inside the git-state repo's
+// git directory (shared by linked worktrees).
+func gitStateLockDir(repoPath string) (string, error) {
+	out, err := exec.CommandContext(context.Background(), "git", "-C", repoPath, "rev-parse", "--git-common-dir").Output() //nolint:gosec // repoPath is config-controlled
+	if err != nil {
+		return "", fmt.Errorf("locating git-state git directory: %w", err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return filepath.Join(dir, "tntc-locks"), nil
+}
+
+// readLockFile returns the lock recorded at path, or nil when there is none.
+func readLockFile(path string) (*mcp.DeployLock, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is within the git directory
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lock: %w", err)
+	}
+	var lock mcp.DeployLock
+	if json.Unmarshal(data, &lock) != nil {
+		return &mcp.DeployLock{}, nil // a torn file holds nothing; it reads as expired
+	}
+	return &lock, nil
+}
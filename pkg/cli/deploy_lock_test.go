@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+func TestDeploy_LockHeldByAnotherDeploy(t *testing.T) {
+	srv := deployRunFixture(t)
+	now := time.Now().UTC()
+	srv.SetLock("default", "test-workflow", mcp.DeployLock{
+		Holder:     "bob@example.com on ci-runner",
+		LockID:     "bob-1",
+		AcquiredAt: now.Format(time.RFC3339),
+		ExpiresAt:  now.Add(5 * time.Minute).Format(time.RFC3339),
+	})
+
+	err := runDeployCmd(t, "--image", "engine:2.0")
+	if err == nil || !strings.Contains(err.Error(), "locked by bob@example.com on ci-runner") || !strings.Contains(err.Error(), "--steal") {
+		t.Fatalf("expected the lock holder named in the error, got %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected nothing applied while locked, got image %s", wf.Image)
+	}
+
+	if err := runDeployCmd(t, "--image", "engine:2.0", "--steal"); err != nil {
+		t.Fatalf("deploy --steal: %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:2.0" {
+		t.Errorf("expected --steal to deploy, got image %s", wf.Image)
+	}
+	if lock, held := srv.Lock("default", "test-workflow"); held {
+		t.Errorf("expected the lock released after deploy, still held by %s", lock.Holder)
+	}
+}
+
+func TestDeployLocker_GitState(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	alice := deployLocker{w: io.Discard, holder: "alice", lockID: "a1", ttl: time.Minute}
+	bob := deployLocker{w: io.Discard, holder: "bob", lockID: "b1", ttl: time.Minute}
+
+	releaseAlice, err := alice.lockGitState(repo, "payments", "checkout")
+	if err != nil {
+		t.Fatalf("alice: %v", err)
+	}
+	if _, err := bob.lockGitState(repo, "payments", "checkout"); err == nil || !strings.Contains(err.Error(), "locked by alice") {
+		t.Fatalf("expected bob blocked by alice, got %v", err)
+	}
+	if release, err := bob.lockGitState(repo, "payments", "ledger"); err != nil {
+		t.Errorf("expected other tentacles unaffected: %v", err)
+	} else {
+		release()
+	}
+
+	bob.steal = true
+	releaseBob, err := bob.lockGitState(repo, "payments", "checkout")
+	if err != nil {
+		t.Fatalf("bob --steal: %v", err)
+	}
+	releaseAlice() // must not release bob's lock
+	carol := deployLocker{w: io.Discard, holder: "carol", lockID: "c1", ttl: time.Minute}
+	if _, err := carol.lockGitState(repo, "payments", "checkout"); err == nil || !strings.Contains(err.Error(), "locked by bob") {
+		t.Errorf("expected bob to keep the lock, got %v", err)
+	}
+	releaseBob()
+	if release, err := carol.lockGitState(repo, "payments", "checkout"); err != nil {
+		t.Errorf("expected a released lock to be free: %v", err)
+	} else {
+		release()
+	}
+}
+
+func TestRollbackAndPromote_TakeTheDeployLock(t *testing.T) {
+	srv := deployRunFixture(t)
+	writePromoteConfig(t)
+	for _, args := range [][]string{{"--image", "engine:1.0"}, {"--image", "engine:2.0"}, {"-c", "dev", "--image", "engine:2.0"}} {
+		if err := runDeployCmd(t, args...); err != nil {
+			t.Fatalf("deploy %v: %v", args, err)
+		}
+	}
+	now := time.Now().UTC()
+	bob := mcp.DeployLock{
+		Holder:     "bob@example.com on ci-runner",
+		LockID:     "bob-1",
+		AcquiredAt: now.Format(time.RFC3339),
+		ExpiresAt:  now.Add(5 * time.Minute).Format(time.RFC3339),
+	}
+	srv.SetLock("default", "test-workflow", bob)
+	srv.SetLock("prod", "test-workflow", bob)
+
+	if _, err := runHistoryCmd(t, NewRollbackCmd, "test-workflow"); err == nil || !strings.Contains(err.Error(), "locked by bob@example.com") {
+		t.Errorf("expected rollback blocked by the lock, got %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:2.0" {
+		t.Errorf("expected nothing rolled back while locked, got image %s", wf.Image)
+	}
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod"); err == nil || !strings.Contains(err.Error(), "locked by bob@example.com") {
+		t.Errorf("expected promote blocked by the target's lock, got %v", err)
+	}
+	if _, ok := srv.Workflow("prod", "test-workflow"); ok {
+		t.Error("expected nothing promoted while locked")
+	}
+
+	if _, err := runHistoryCmd(t, NewRollbackCmd, "test-workflow", "--steal"); err != nil {
+		t.Fatalf("rollback --steal: %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected rollback --steal to apply, got image %s", wf.Image)
+	}
+	if lock, held := srv.Lock("default", "test-workflow"); held {
+		t.Errorf("expected the lock released after rollback, still held by %s", lock.Holder)
+	}
+}
+
+func TestRollBackFailedDeploy_UsesTheDeployLock(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	client := srv.Client()
+	previous := &deployRevision{Workflow: "wf", Namespace: "ns", Revision: 1, Manifests: []map[string]any{{
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": map[string]any{"name": "wf-code", "namespace": "ns"},
+	}}}
+	cmd := withRootFlags(NewDeployCmd())
+	deploy := deployLocker{w: io.Discard, holder: "alice", lockID: "a1", ttl: time.Minute}
+	if _, err := deploy.lockCluster(t.Context(), client, "ns", "wf"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-taking the deploy's own lock renews it.
+	if rb := rollBackFailedDeploy(cmd, client, deploy, previous, "", io.Discard); rb.Status != "pass" {
+		t.Fatalf("expected the rollback to run under the deploy's lock, got %+v", rb)
+	}
+	if lock, _ := srv.Lock("ns", "wf"); lock.LockID != "a1" {
+		t.Errorf("expected the deploy to keep its lock, got %+v", lock)
+	}
+
+	// A lock taken over during the gates refuses the rollback.
+	thief := deployLocker{w: io.Discard, holder: "bob", lockID: "b1", ttl: time.Minute, steal: true}
+	if _, err := thief.lockCluster(t.Context(), client, "ns", "wf"); err != nil {
+		t.Fatal(err)
+	}
+	if rb := rollBackFailedDeploy(cmd, client, deploy, previous, "", io.Discard); rb.Status != "fail" || !strings.Contains(rb.Detail, "locked by bob") {
+		t.Errorf("expected the rollback refused, got %+v", rb)
+	}
+}
@@ -439,3 +439,13 @@ func TestCountModuleProxyDeps_EmptyDependencies(t *testing.T) {
 		t.Errorf("expected 0 for empty dependencies, got %d", n)
 	}
 }
+
+func TestDeployCmdHasWaitFlags(t *testing.T) {
+	cmd := NewDeployCmd()
+	if f := cmd.Flags().Lookup("wait"); f == nil || f.DefValue != "false" {
+		t.Fatal("expected --wait flag defaulting to false on deploy command")
+	}
+	if f := cmd.Flags().Lookup("wait-timeout"); f == nil || f.DefValue != defaultWatchTimeout.String() {
+		t.Fatal("expected --wait-timeout flag on deploy command")
+	}
+}
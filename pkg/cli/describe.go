@@ -0,0 +1,412 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+func NewDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Show a deployed tentacle's metadata, contract, DAG and health",
+		Long: `Show everything known about a deployed tentacle in one view: triggers,
+contract summary, parameter schema, node descriptions, the DAG as a Mermaid
+diagram, deployed image, git provenance, who deployed it and pod health.
+
+Output formats (-o): text (default), json, markdown.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runDescribe,
+	}
+	return cmd
+}
+
+// describeNode is a workflow node and its description.
+type describeNode struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// describeGit is the git provenance of the deployed tentacle.
+type describeGit struct {
+	SHA    string `json:"sha,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Repo   string `json:"repo,omitempty"`
+	Dirty  bool   `json:"dirty,omitempty"`
+}
+
+// describeDeployer is who last deployed the tentacle, from the deployed-by
+// annotations.
+type describeDeployer struct {
+	By      string `json:"by"`
+	Subject string `json:"subject,omitempty"`
+	Source  string `json:"source,omitempty"` // "oidc", "git" or "local"
+	Via     string `json:"via,omitempty"`
+	At      string `json:"at,omitempty"`
+}
+
+// describeResult is the combined view rendered by tntc describe.
+type describeResult struct {
+	Git          *describeGit      `json:"git,omitempty"`
+	Deployer     *describeDeployer `json:"deployer,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Version      string            `json:"version,omitempty"`
+	Description  string            `json:"description,omitempty"`
+	Image        string            `json:"image,omitempty"`
+	Contract     string            `json:"contract,omitempty"`
+	ParamsSchema string            `json:"params_schema,omitempty"`
+	Readme       string            `json:"readme,omitempty"`
+	DAG          string            `json:"dag,omitempty"`
+	StatusError  string            `json:"status_error,omitempty"`
+	Triggers     []string          `json:"triggers,omitempty"`
+	Nodes        []describeNode    `json:"nodes,omitempty"`
+	Edges        [][2]string       `json:"edges,omitempty"`
+	Pods         []mcp.PodInfo     `json:"pods,omitempty"`
+	Replicas     int32             `json:"replicas"`
+	Available    int32             `json:"available"`
+	Ready        bool              `json:"ready"`
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	namespace := resolveNamespace(cmd, ".")
+	output, _ := cmd.Flags().GetString("output")
+
+	client, err := requireMCPClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	desc, err := client.WfDescribe(cmd.Context(), namespace, name)
+	if err != nil {
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("describing %s: %w\n  hint: %s", name, err, hint)
+		}
+		return fmt.Errorf("describing %s: %w", name, err)
+	}
+	result := buildDescribeResult(desc)
+
+	// Pod health comes from wf_status; describe still renders without it.
+	status, statusErr := client.WfStatus(cmd.Context(), namespace, name, true)
+	if statusErr != nil {
+		result.StatusError = statusErr.Error()
+	} else {
+		result.Pods = status.Pods
+		result.Ready = status.Ready
+		result.Replicas = status.Replicas
+		result.Available = status.Available
+		if result.Version == "" {
+			result.Version = status.Version
+		}
+	}
+
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "markdown", "md":
+		writeDescribeMarkdown(os.Stdout, result)
+	default:
+		writeDescribeText(os.Stdout, result)
+	}
+	return nil
+}
+
+// buildDescribeResult assembles the describe view from a wf_describe response,
+// reading structure from the tentacular.io/* annotations and rich content
+// from the metadata ConfigMap.
+func buildDescribeResult(desc *mcp.WfDescribeResult) describeResult {
+	ann := desc.Annotations
+	meta := desc.MetadataData()
+	result := describeResult{
+		Name:         desc.Name,
+		Namespace:    desc.Namespace,
+		Image:        desc.Image,
+		Annotations:  ann,
+		Version:      ann["tentacular.io/version"],
+		Description:  ann["tentacular.io/description"],
+		Contract:     meta["contract"],
+		ParamsSchema: meta["params_schema"],
+		Readme:       meta["readme"],
+		Triggers:     desc.Triggers,
+		Replicas:     desc.Replicas,
+		Available:    desc.Available,
+		Ready:        desc.Ready,
+	}
+	if len(result.Triggers) == 0 && ann["tentacular.io/trigger-type"] != "" {
+		result.Triggers = strings.Split(ann["tentacular.io/trigger-type"], ",")
+	}
+
+	result.Git = describeGitInfo(ann, meta["git_provenance"])
+	if by := ann[annotationDeployedBy]; by != "" {
+		result.Deployer = &describeDeployer{
+			By:      by,
+			Subject: ann[annotationDeployedBySubject],
+			Source:  ann[annotationDeployedBySource],
+			Via:     ann[annotationDeployedVia],
+			At:      ann[annotationDeployedAt],
+		}
+	}
+	result.Nodes = describeNodes(ann["tentacular.io/nodes"], meta["node_descriptions"])
+	if raw := ann["tentacular.io/edges"]; raw != "" {
+		_ = json.Unmarshal([]byte(raw), &result.Edges)
+	}
+	if len(result.Nodes) > 0 {
+		result.DAG = describeMermaid(result.Nodes, result.Edges)
+	}
+	return result
+}
+
+// describeGitInfo prefers the git-state annotations written at deploy time and
+// falls back to the provenance recorded in the metadata ConfigMap.
+func describeGitInfo(ann map[string]string, provenance string) *describeGit {
+	git := &describeGit{
+		SHA:    ann["tentacular.io/git-sha"],
+		Branch: ann["tentacular.io/git-branch"],
+		Repo:   ann["tentacular.io/git-repo"],
+	}
+	if provenance != "" {
+		var prov struct {
+			Commit string `json:"commit"`
+			Branch string `json:"branch"`
+			Repo   string `json:"repo"`
+			Dirty  bool   `json:"dirty"`
+		}
+		if err := json.Unmarshal([]byte(provenance), &prov); err == nil {
+			if git.SHA == "" {
+				git.SHA = prov.Commit
+			}
+			if git.Branch == "" {
+				git.Branch = prov.Branch
+			}
+			if git.Repo == "" {
+				git.Repo = prov.Repo
+			}
+			git.Dirty = prov.Dirty
+		}
+	}
+	if *git == (describeGit{}) {
+		return nil
+	}
+	return git
+}
+
+// describeNodes merges the node list annotation with node descriptions from
+// the metadata ConfigMap, sorted by name.
+func describeNodes(namesJSON, descriptionsJSON string) []describeNode {
+	byName := map[string]string{}
+	if namesJSON != "" {
+		var names []string
+		if err := json.Unmarshal([]byte(namesJSON), &names); err == nil {
+			for _, n := range names {
+				byName[n] = ""
+			}
+		}
+	}
+	if descriptionsJSON != "" {
+		var nodes []describeNode
+		if err := json.Unmarshal([]byte(descriptionsJSON), &nodes); err == nil {
+			for _, n := range nodes {
+				byName[n.Name] = n.Description
+			}
+		}
+	}
+	nodes := make([]describeNode, 0, len(byName))
+	for name, description := range byName {
+		nodes = append(nodes, describeNode{Name: name, Description: description})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes
+}
+
+// describeMermaid renders the deployed DAG with the same generator as
+// tntc visualize.
+func describeMermaid(nodes []describeNode, edges [][2]string) string {
+	wf := &spec.Workflow{Nodes: make(map[string]spec.NodeSpec, len(nodes))}
+	for _, n := range nodes {
+		wf.Nodes[n.Name] = spec.NodeSpec{Description: n.Description}
+	}
+	for _, e := range edges {
+		wf.Edges = append(wf.Edges, spec.Edge{From: e[0], To: e[1]})
+	}
+	return generateMermaidDiagram(wf, false)
+}
+
+func (r describeResult) readiness() string {
+	state := "not ready"
+	if r.Ready {
+		state = "ready"
+	}
+	return fmt.Sprintf("%s (%d/%d replicas)", state, r.Available, r.Replicas)
+}
+
+func (g *describeGit) String() string {
+	s := g.SHA
+	if g.Branch != "" {
+		s += " (" + g.Branch + ")"
+	}
+	if g.Dirty {
+		s += " [dirty]"
+	}
+	if g.Repo != "" {
+		s += " " + g.Repo
+	}
+	return strings.TrimSpace(s)
+}
+
+func (d *describeDeployer) String() string {
+	s := d.By
+	if d.Source != "" && d.Source != "oidc" {
+		s += " (" + d.Source + " identity)"
+	}
+	if d.Via != "" {
+		s += " via " + d.Via
+	}
+	if d.At != "" {
+		s += " at " + d.At
+	}
+	return s
+}
+
+func writeDescribeText(w io.Writer, r describeResult) {
+	_, _ = fmt.Fprintf(w, "Name:        %s\n", r.Name)
+	_, _ = fmt.Fprintf(w, "Namespace:   %s\n", r.Namespace)
+	if r.Version != "" {
+		_, _ = fmt.Fprintf(w, "Version:     %s\n", r.Version)
+	}
+	if r.Description != "" {
+		_, _ = fmt.Fprintf(w, "Description: %s\n", r.Description)
+	}
+	if r.Image != "" {
+		_, _ = fmt.Fprintf(w, "Image:       %s\n", r.Image)
+	}
+	if r.Git != nil {
+		_, _ = fmt.Fprintf(w, "Git:         %s\n", r.Git)
+	}
+	if r.Deployer != nil {
+		_, _ = fmt.Fprintf(w, "Deployed by: %s\n", r.Deployer)
+	}
+	_, _ = fmt.Fprintf(w, "Status:      %s\n", r.readiness())
+	if len(r.Triggers) > 0 {
+		_, _ = fmt.Fprintf(w, "Triggers:    %s\n", strings.Join(r.Triggers, ", "))
+	}
+
+	if len(r.Nodes) > 0 {
+		_, _ = fmt.Fprintln(w, "\nNodes:")
+		for _, n := range r.Nodes {
+			_, _ = fmt.Fprintf(w, "  %-20s %s\n", n.Name, n.Description)
+		}
+	}
+	if r.DAG != "" {
+		_, _ = fmt.Fprintln(w, "\nDAG:")
+		_, _ = fmt.Fprint(w, r.DAG)
+	}
+	if r.Contract != "" {
+		_, _ = fmt.Fprintln(w, "\nContract:")
+		_, _ = fmt.Fprintln(w, indentLines(strings.TrimSpace(r.Contract), "  "))
+	}
+	if r.ParamsSchema != "" {
+		_, _ = fmt.Fprintln(w, "\nParameters:")
+		_, _ = fmt.Fprintln(w, indentLines(strings.TrimSpace(r.ParamsSchema), "  "))
+	}
+
+	_, _ = fmt.Fprintln(w, "\nPods:")
+	switch {
+	case r.StatusError != "":
+		_, _ = fmt.Fprintf(w, "  unavailable: %s\n", r.StatusError)
+	case len(r.Pods) == 0:
+		_, _ = fmt.Fprintln(w, "  (none)")
+	default:
+		for _, pod := range r.Pods {
+			podReady := "not ready"
+			if pod.Ready {
+				podReady = "ready"
+			}
+			_, _ = fmt.Fprintf(w, "  %-40s %-12s %s\n", pod.Name, pod.Phase, podReady)
+		}
+	}
+
+	if r.Readme != "" {
+		_, _ = fmt.Fprintln(w, "\nREADME:")
+		_, _ = fmt.Fprintln(w, indentLines(strings.TrimSpace(r.Readme), "  "))
+	}
+}
+
+func writeDescribeMarkdown(w io.Writer, r describeResult) {
+	_, _ = fmt.Fprintf(w, "# %s\n\n", r.Name)
+	if r.Description != "" {
+		_, _ = fmt.Fprintf(w, "%s\n\n", r.Description)
+	}
+
+	_, _ = fmt.Fprintln(w, "| Field | Value |")
+	_, _ = fmt.Fprintln(w, "|-------|-------|")
+	_, _ = fmt.Fprintf(w, "| Namespace | %s |\n", r.Namespace)
+	if r.Version != "" {
+		_, _ = fmt.Fprintf(w, "| Version | %s |\n", r.Version)
+	}
+	if r.Image != "" {
+		_, _ = fmt.Fprintf(w, "| Image | `%s` |\n", r.Image)
+	}
+	if r.Git != nil {
+		_, _ = fmt.Fprintf(w, "| Git | %s |\n", r.Git)
+	}
+	if r.Deployer != nil {
+		_, _ = fmt.Fprintf(w, "| Deployed by | %s |\n", r.Deployer)
+	}
+	_, _ = fmt.Fprintf(w, "| Status | %s |\n", r.readiness())
+	if len(r.Triggers) > 0 {
+		_, _ = fmt.Fprintf(w, "| Triggers | %s |\n", strings.Join(r.Triggers, ", "))
+	}
+
+	if r.DAG != "" {
+		_, _ = fmt.Fprintf(w, "\n## DAG\n\n%s", r.DAG)
+	}
+	if len(r.Nodes) > 0 {
+		_, _ = fmt.Fprintln(w, "\n## Nodes\n\n| Node | Description |\n|------|-------------|")
+		for _, n := range r.Nodes {
+			_, _ = fmt.Fprintf(w, "| %s | %s |\n", n.Name, n.Description)
+		}
+	}
+	if r.Contract != "" {
+		_, _ = fmt.Fprintf(w, "\n## Contract\n\n%s\n", strings.TrimSpace(r.Contract))
+	}
+	if r.ParamsSchema != "" {
+		_, _ = fmt.Fprintf(w, "\n## Parameters\n\n```yaml\n%s\n```\n", strings.TrimSpace(r.ParamsSchema))
+	}
+
+	_, _ = fmt.Fprintln(w, "\n## Pods")
+	_, _ = fmt.Fprintln(w)
+	switch {
+	case r.StatusError != "":
+		_, _ = fmt.Fprintf(w, "Pod status unavailable: %s\n", r.StatusError)
+	case len(r.Pods) == 0:
+		_, _ = fmt.Fprintln(w, "No pods.")
+	default:
+		_, _ = fmt.Fprintln(w, "| Pod | Phase | Ready |\n|-----|-------|-------|")
+		for _, pod := range r.Pods {
+			_, _ = fmt.Fprintf(w, "| %s | %s | %t |\n", pod.Name, pod.Phase, pod.Ready)
+		}
+	}
+
+	if r.Readme != "" {
+		_, _ = fmt.Fprintf(w, "\n## README\n\n%s\n", strings.TrimSpace(r.Readme))
+	}
+}
+
+// indentLines prefixes every line of s with prefix.
+func indentLines(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+const describeWorkflowYAML = `name: test-workflow
+version: "1.0"
+description: "Fetches and summarizes"
+triggers:
+  - type: manual
+  - type: cron
+    schedule: "0 * * * *"
+nodes:
+  fetch:
+    path: ./fetch.ts
+    description: "Fetch data"
+  summarize:
+    path: ./summarize.ts
+    description: "Summarize data"
+edges:
+  - from: fetch
+    to: summarize
+`
+
+// deployDescribeFixture deploys a two-node workflow with a README to the fake
+// server configured through TNTC_MCP_ENDPOINT.
+func deployDescribeFixture(t *testing.T) *mcptest.Server {
+	t.Helper()
+	srv := mcptest.NewServer(t)
+	cleanup := setupMCPEnv(t, srv.URL())
+	t.Cleanup(cleanup)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"workflow.yaml": describeWorkflowYAML,
+		"fetch.ts":      "export default async () => ({})\n",
+		"summarize.ts":  "export default async () => ({})\n",
+		"README.md":     "# Test workflow\n\nSummarizes things.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var status bytes.Buffer
+	if _, err := deployWorkflow(dir, InternalDeployOptions{Namespace: "default", Image: "engine:1.0", StatusOut: &status}, srv.Client()); err != nil {
+		t.Fatalf("deploying fixture: %v", err)
+	}
+	return srv
+}
+
+func runDescribeCmd(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := withRootFlags(NewDescribeCmd())
+	cmd.SetArgs(args)
+	var err error
+	out := captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	if err != nil {
+		t.Fatalf("describe %v: %v", args, err)
+	}
+	return out
+}
+
+func TestDescribe_JSON(t *testing.T) {
+	deployDescribeFixture(t)
+
+	out := runDescribeCmd(t, "test-workflow", "-o", "json")
+	var result describeResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.Name != "test-workflow" || result.Description != "Fetches and summarizes" {
+		t.Errorf("unexpected identity: %+v", result)
+	}
+	if len(result.Nodes) != 2 || result.Nodes[0].Description != "Fetch data" {
+		t.Errorf("expected node descriptions from metadata, got %+v", result.Nodes)
+	}
+	if !strings.Contains(result.DAG, "fetch --> summarize") {
+		t.Errorf("expected mermaid edge, got %q", result.DAG)
+	}
+	if strings.Join(result.Triggers, ",") != "manual,cron" {
+		t.Errorf("expected triggers from annotations, got %v", result.Triggers)
+	}
+	if !strings.Contains(result.Readme, "Summarizes things") {
+		t.Errorf("expected README from metadata ConfigMap, got %q", result.Readme)
+	}
+	if !result.Ready || len(result.Pods) != 1 {
+		t.Errorf("expected pod health from wf_status, got ready=%v pods=%+v", result.Ready, result.Pods)
+	}
+}
+
+func TestDescribe_TextAndMarkdown(t *testing.T) {
+	deployDescribeFixture(t)
+
+	text := runDescribeCmd(t, "test-workflow")
+	for _, want := range []string{"Name:        test-workflow", "Triggers:    manual, cron", "```mermaid", "Pods:", "README:"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	md := runDescribeCmd(t, "test-workflow", "-o", "markdown")
+	for _, want := range []string{"# test-workflow", "## DAG", "| fetch | Fetch data |", "## README"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestBuildDescribeResult_GitProvenanceFallback(t *testing.T) {
+	desc := &mcp.WfDescribeResult{
+		Name:        "wf",
+		Annotations: map[string]string{"tentacular.io/git-branch": "release"},
+		Metadata:    map[string]string{"git_provenance": `{"commit":"abc1234","branch":"main","dirty":true}`},
+	}
+	got := buildDescribeResult(desc)
+	if got.Git == nil || got.Git.SHA != "abc1234" || got.Git.Branch != "release" || !got.Git.Dirty {
+		t.Errorf("expected annotation branch with provenance SHA, got %+v", got.Git)
+	}
+	if got.Git.String() != "abc1234 (release) [dirty]" {
+		t.Errorf("unexpected git summary %q", got.Git.String())
+	}
+}
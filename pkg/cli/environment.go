@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // EnvironmentConfig holds per-environment overrides.
@@ -18,12 +19,37 @@ type EnvironmentConfig struct {
 	Enforcement     string         `yaml:"enforcement,omitempty"` // "strict" (default) or "audit"
 	MCPEndpoint     string         `yaml:"mcp_endpoint,omitempty"`
 
+	// LiveTest configures the pre-deploy live test of deploys to this environment.
+	LiveTest LiveTestConfig `yaml:"live_test,omitempty"`
+	// PostDeploy gates deploys to this environment once applied.
+	PostDeploy PostDeployConfig `yaml:"post_deploy,omitempty"`
+
 	// OIDC fields (optional). When present, `tntc login` uses device authorization flow.
 	OIDCIssuer       string `yaml:"oidc_issuer,omitempty"`
 	OIDCClientID     string `yaml:"oidc_client_id,omitempty"`
 	OIDCClientSecret string `yaml:"oidc_client_secret,omitempty"`
 }
 
+// LiveTestConfig sets the pre-deploy live test of deploys to an environment:
+// the workflow is deployed to Environment, run once per fixture, checked
+// against Expect and removed before the real deploy proceeds.
+type LiveTestConfig struct {
+	Environment string         `yaml:"environment,omitempty"` // environment to test in (default: dev)
+	Fixtures    []string       `yaml:"fixtures,omitempty"`    // input fixtures, relative to the workflow directory (default: one run with --input)
+	Timeout     time.Duration  `yaml:"timeout,omitempty"`     // per run (default: 120s)
+	Expect      map[string]any `yaml:"expect,omitempty"`      // JSONPath -> expected output value, e.g. $.result.count: 3
+}
+
+// PostDeployConfig sets the gates a deploy must pass after it is applied.
+// When a gate fails, the previous revision is re-applied. Flags on
+// `tntc deploy` override each field.
+type PostDeployConfig struct {
+	ReadyTimeout time.Duration `yaml:"ready_timeout,omitempty"` // rollout must be ready within this long
+	VerifyInput  string        `yaml:"verify_input,omitempty"`  // input fixture for a verification run, relative to the workflow directory
+	Soak         time.Duration `yaml:"soak,omitempty"`          // pods must not restart for this long after the rollout
+	NoRollback   bool          `yaml:"no_rollback,omitempty"`   // leave a deploy that failed a gate live
+}
+
 // ResolveEnvironment loads the merged config and returns the named environment.
 // Resolution cascade: explicit clusterName > TENTACULAR_CLUSTER env var > default_cluster config > "" (top-level defaults).
 // When clusterName is empty (and TENTACULAR_CLUSTER is unset and default_cluster is not set),
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health [name]",
+		Short: "Show engine health and telemetry",
+		Long: `Show a deployed workflow's engine telemetry snapshot (GET /health?detail=1):
+recent executions, error rate, node durations and GenAI token usage.
+
+Each workflow is classified client-side as green, amber or red:
+  red    telemetry unavailable, or execution error rate >= --red-error-rate
+  amber  error rate >= --amber-error-rate, the last run failed, or more than
+         --max-in-flight executions in flight
+  green  otherwise
+
+Thresholds default to the health section of ~/.tentacular/config.yaml.
+--all summarises every workflow in the enclave.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runHealth,
+	}
+	cmd.Flags().Bool("all", false, "Show health of every workflow in the enclave")
+	cmd.Flags().Float64("amber-error-rate", defaultHealthThresholds.AmberErrorRate, "Execution error rate (0..1) classified amber")
+	cmd.Flags().Float64("red-error-rate", defaultHealthThresholds.RedErrorRate, "Execution error rate (0..1) classified red")
+	cmd.Flags().Int("max-in-flight", defaultHealthThresholds.MaxInFlight, "Executions in flight above which a workflow is amber (0 disables)")
+	addDirectFlags(cmd)
+	return cmd
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) == 1) {
+		return errors.New("specify a workflow name or --all")
+	}
+	output, _ := cmd.Flags().GetString("output")
+	thresholds := resolveHealthThresholds(cmd, LoadConfig())
+
+	client, err := resolveWorkflowClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		name := args[0]
+		namespace := resolveNamespace(cmd, ".")
+		result, err := client.WfHealth(cmd.Context(), namespace, name)
+		if err != nil {
+			if hint := mcpErrorHint(err); hint != "" {
+				return fmt.Errorf("getting health: %w\n  hint: %s", err, hint)
+			}
+			return fmt.Errorf("getting health: %w", err)
+		}
+		report := newHealthReport(result)
+		report.classify(thresholds)
+		if output == "json" {
+			return writeHealthJSON(cmd.OutOrStdout(), report)
+		}
+		writeHealthText(cmd.OutOrStdout(), &report)
+		return nil
+	}
+
+	namespace := resolveNamespace(cmd, "")
+	workflows, err := client.WfList(cmd.Context(), namespace)
+	if err != nil {
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("listing workflows: %w\n  hint: %s", err, hint)
+		}
+		return fmt.Errorf("listing workflows: %w", err)
+	}
+	reports := make([]healthReport, 0, len(workflows))
+	for _, wf := range workflows {
+		ns := wf.Namespace
+		if ns == "" {
+			ns = namespace
+		}
+		report := healthReport{Name: wf.Name, Namespace: ns}
+		if result, err := client.WfHealth(cmd.Context(), ns, wf.Name); err != nil {
+			report.Error = err.Error()
+		} else {
+			report = newHealthReport(result)
+		}
+		report.classify(thresholds)
+		reports = append(reports, report)
+	}
+	if output == "json" {
+		return writeHealthJSON(cmd.OutOrStdout(), reports)
+	}
+	if len(reports) == 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No workflows found in namespace %s\n", namespace)
+		return nil
+	}
+	writeHealthTable(cmd.OutOrStdout(), reports)
+	return nil
+}
+
+// resolveHealthThresholds layers the config file and then explicitly set
+// flags over the defaults.
+func resolveHealthThresholds(cmd *cobra.Command, cfg TentacularConfig) healthThresholds {
+	t := defaultHealthThresholds.withConfig(cfg.Health)
+	if cmd.Flags().Changed("amber-error-rate") {
+		t.AmberErrorRate, _ = cmd.Flags().GetFloat64("amber-error-rate")
+	}
+	if cmd.Flags().Changed("red-error-rate") {
+		t.RedErrorRate, _ = cmd.Flags().GetFloat64("red-error-rate")
+	}
+	if cmd.Flags().Changed("max-in-flight") {
+		t.MaxInFlight, _ = cmd.Flags().GetInt("max-in-flight")
+	}
+	return t
+}
+
+func writeHealthJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	_, _ = fmt.Fprintln(w, string(data))
+	return nil
+}
+
+func healthLabel(r *healthReport) string {
+	if len(r.Reasons) == 0 {
+		return r.Status
+	}
+	return r.Status + " (" + strings.Join(r.Reasons, "; ") + ")"
+}
+
+func writeHealthText(w io.Writer, r *healthReport) {
+	_, _ = fmt.Fprintf(w, "Name:        %s\n", r.Name)
+	_, _ = fmt.Fprintf(w, "Namespace:   %s\n", r.Namespace)
+	_, _ = fmt.Fprintf(w, "Health:      %s\n", healthLabel(r))
+	_, _ = fmt.Fprintf(w, "Uptime:      %s\n", time.Duration(r.UptimeMs*int64(time.Millisecond)).Round(time.Second))
+	_, _ = fmt.Fprintf(w, "In flight:   %d\n", r.InFlight)
+	_, _ = fmt.Fprintf(w, "Executions:  %d recent, %d failed (%.1f%%)\n", r.ExecutionCount, r.FailedExecutions, r.ErrorRate*100)
+	if r.LastError != "" {
+		at := ""
+		if r.LastErrorAt != nil {
+			at = " at " + r.LastErrorAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "Last error:  %s%s\n", r.LastError, at)
+	}
+
+	if len(r.Executions) > 0 {
+		_, _ = fmt.Fprintln(w, "\nRecent executions:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "  STARTED\tDURATION\tRESULT")
+		for _, e := range r.Executions {
+			result := "success"
+			if e.Failed {
+				result = "failed: " + strings.Join(e.Errors, "; ")
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%dms\t%s\n", e.StartedAt.Format(time.RFC3339), e.DurationMs, result)
+		}
+		_ = tw.Flush()
+	}
+
+	if len(r.Nodes) > 0 {
+		_, _ = fmt.Fprintln(w, "\nNodes:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "  NODE\tRUNS\tERRORS\tAVG\tMAX")
+		for _, n := range r.Nodes {
+			_, _ = fmt.Fprintf(tw, "  %s\t%d\t%d\t%dms\t%dms\n", n.Node, n.Runs, n.Errors, n.AvgMs, n.MaxMs)
+		}
+		_ = tw.Flush()
+	}
+
+	if len(r.Tokens) > 0 {
+		_, _ = fmt.Fprintln(w, "\nGenAI usage:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "  MODEL\tCALLS\tINPUT TOKENS\tOUTPUT TOKENS")
+		for _, t := range r.Tokens {
+			_, _ = fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\n", t.Model, t.Calls, t.InputTokens, t.OutputTokens)
+		}
+		_ = tw.Flush()
+	}
+}
+
+func writeHealthTable(w io.Writer, reports []healthReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tHEALTH\tEXECUTIONS\tERROR RATE\tIN FLIGHT\tREASON")
+	for i := range reports {
+		r := &reports[i]
+		reason := strings.Join(r.Reasons, "; ")
+		if r.Error != "" {
+			reason += ": " + r.Error
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f%%\t%d\t%s\n",
+			r.Name, healthStatusCell(r.Status), r.ExecutionCount, r.ErrorRate*100, r.InFlight, orDash(reason))
+	}
+	_ = tw.Flush()
+}
+
+// healthStatusCell renders a classification as its G/A/R letter and name.
+func healthStatusCell(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return strings.ToUpper(status[:1]) + " " + status
+}
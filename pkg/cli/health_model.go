@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// Health classifications, worst last.
+const (
+	healthGreen = "green"
+	healthAmber = "amber"
+	healthRed   = "red"
+)
+
+// healthRecentExecutions caps the executions listed in a health report.
+const healthRecentExecutions = 10
+
+// healthThresholds are the client-side green/amber/red rules.
+type healthThresholds struct {
+	AmberErrorRate float64 `json:"amber_error_rate"`
+	RedErrorRate   float64 `json:"red_error_rate"`
+	MaxInFlight    int     `json:"max_in_flight"`
+}
+
+// defaultHealthThresholds: amber from 10% failed executions, red from 50%,
+// amber with more than 5 executions in flight.
+var defaultHealthThresholds = healthThresholds{AmberErrorRate: 0.1, RedErrorRate: 0.5, MaxInFlight: 5}
+
+// withConfig returns t with the fields set in cfg applied.
+func (t healthThresholds) withConfig(cfg HealthConfig) healthThresholds {
+	if cfg.AmberErrorRate > 0 {
+		t.AmberErrorRate = cfg.AmberErrorRate
+	}
+	if cfg.RedErrorRate > 0 {
+		t.RedErrorRate = cfg.RedErrorRate
+	}
+	if cfg.MaxInFlight > 0 {
+		t.MaxInFlight = cfg.MaxInFlight
+	}
+	return t
+}
+
+// healthReport is a workflow's telemetry snapshot summarised and classified.
+type healthReport struct {
+	LastErrorAt      *time.Time         `json:"lastErrorAt,omitempty"`
+	Name             string             `json:"name"`
+	Namespace        string             `json:"namespace"`
+	Status           string             `json:"status"`
+	ServerStatus     string             `json:"serverStatus,omitempty"`
+	Error            string             `json:"error,omitempty"`
+	LastError        string             `json:"lastError,omitempty"`
+	Reasons          []string           `json:"reasons,omitempty"`
+	Executions       []healthExecution  `json:"executions,omitempty"`
+	Nodes            []healthNodeStats  `json:"nodes,omitempty"`
+	Tokens           []healthTokenUsage `json:"tokens,omitempty"`
+	UptimeMs         int64              `json:"uptimeMs"`
+	ExecutionCount   int                `json:"executionCount"`
+	FailedExecutions int                `json:"failedExecutions"`
+	ErrorRate        float64            `json:"errorRate"`
+	InFlight         int                `json:"inFlight"`
+	LastRunFailed    bool               `json:"lastRunFailed"`
+}
+
+// healthExecution is one /run request reconstructed from telemetry events.
+type healthExecution struct {
+	StartedAt  time.Time `json:"startedAt"`
+	Errors     []string  `json:"errors,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	Failed     bool      `json:"failed"`
+}
+
+// healthNodeStats aggregates node-complete and node-error events per node.
+type healthNodeStats struct {
+	Node   string `json:"node"`
+	Runs   int    `json:"runs"`
+	Errors int    `json:"errors"`
+	AvgMs  int64  `json:"avgMs"`
+	MaxMs  int64  `json:"maxMs"`
+
+	totalMs int64
+}
+
+// healthTokenUsage sums GenAI token usage recorded in event metadata.
+type healthTokenUsage struct {
+	Model        string `json:"model"`
+	Calls        int    `json:"calls"`
+	InputTokens  int64  `json:"inputTokens"`
+	OutputTokens int64  `json:"outputTokens"`
+}
+
+// newHealthReport summarises a wf_health result. Executions, node durations
+// and token usage are derived from the snapshot's recent events, so they
+// cover at most the engine's telemetry ring buffer.
+func newHealthReport(result *mcp.WfHealthResult) healthReport {
+	report := healthReport{
+		Name:         result.Name,
+		Namespace:    result.Namespace,
+		ServerStatus: result.Status,
+	}
+	snap := result.Detail
+	if snap == nil {
+		return report
+	}
+	report.UptimeMs = snap.UptimeMs
+	report.InFlight = snap.InFlight
+	report.LastRunFailed = snap.LastRunFailed
+	if snap.LastError != nil {
+		report.LastError = *snap.LastError
+	}
+	if snap.LastErrorAt != nil {
+		at := time.UnixMilli(*snap.LastErrorAt).UTC()
+		report.LastErrorAt = &at
+	}
+
+	var executions []healthExecution
+	var current *healthExecution
+	nodes := map[string]*healthNodeStats{}
+	nodeStarts := map[string]int64{}
+	tokens := map[string]*healthTokenUsage{}
+	for _, ev := range snap.RecentEvents {
+		node := metadataString(ev.Metadata, "node")
+		switch ev.Type {
+		case "request-in":
+			current = &healthExecution{StartedAt: time.UnixMilli(ev.Timestamp).UTC()}
+		case "request-out":
+			if current != nil {
+				current.DurationMs = ev.Timestamp - current.StartedAt.UnixMilli()
+				executions = append(executions, *current)
+				current = nil
+			}
+		case "node-start":
+			nodeStarts[node] = ev.Timestamp
+		case "node-complete":
+			ms, ok := metadataNumber(ev.Metadata, "durationMs")
+			if !ok {
+				ms = float64(ev.Timestamp - nodeStarts[node])
+			}
+			nodeStat(nodes, node).add(int64(ms), false)
+		case "node-error":
+			var ms int64
+			if start, ok := nodeStarts[node]; ok {
+				ms = ev.Timestamp - start
+			}
+			nodeStat(nodes, node).add(ms, true)
+			if current != nil {
+				current.Failed = true
+				current.Errors = append(current.Errors, fmt.Sprintf("%s: %s", node, metadataString(ev.Metadata, "error")))
+			}
+		}
+		addTokenUsage(tokens, ev.Metadata)
+	}
+
+	report.ExecutionCount = len(executions)
+	for _, e := range executions {
+		if e.Failed {
+			report.FailedExecutions++
+		}
+	}
+	if report.ExecutionCount > 0 {
+		report.ErrorRate = float64(report.FailedExecutions) / float64(report.ExecutionCount)
+	} else {
+		report.ErrorRate = snap.ErrorRate
+	}
+	// Newest first.
+	for i := len(executions) - 1; i >= 0 && len(report.Executions) < healthRecentExecutions; i-- {
+		report.Executions = append(report.Executions, executions[i])
+	}
+
+	for _, n := range nodes {
+		if n.Runs > 0 {
+			n.AvgMs = n.totalMs / int64(n.Runs)
+		}
+		report.Nodes = append(report.Nodes, *n)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Node < report.Nodes[j].Node })
+	for _, t := range tokens {
+		report.Tokens = append(report.Tokens, *t)
+	}
+	sort.Slice(report.Tokens, func(i, j int) bool { return report.Tokens[i].Model < report.Tokens[j].Model })
+	return report
+}
+
+func nodeStat(nodes map[string]*healthNodeStats, node string) *healthNodeStats {
+	if nodes[node] == nil {
+		nodes[node] = &healthNodeStats{Node: node}
+	}
+	return nodes[node]
+}
+
+func (n *healthNodeStats) add(ms int64, failed bool) {
+	n.Runs++
+	if failed {
+		n.Errors++
+	}
+	n.totalMs += ms
+	n.MaxMs = max(n.MaxMs, ms)
+}
+
+// addTokenUsage adds the gen_ai.usage.* attributes of an event, keyed by
+// model (or system when the model is unknown).
+func addTokenUsage(tokens map[string]*healthTokenUsage, meta map[string]any) {
+	in, hasIn := metadataNumber(meta, "gen_ai.usage.input_tokens")
+	out, hasOut := metadataNumber(meta, "gen_ai.usage.output_tokens")
+	if !hasIn && !hasOut {
+		return
+	}
+	model := "unknown"
+	for _, key := range []string{"gen_ai.response.model", "gen_ai.request.model", "gen_ai.system"} {
+		if m := metadataString(meta, key); m != "" {
+			model = m
+			break
+		}
+	}
+	if tokens[model] == nil {
+		tokens[model] = &healthTokenUsage{Model: model}
+	}
+	tokens[model].Calls++
+	tokens[model].InputTokens += int64(in)
+	tokens[model].OutputTokens += int64(out)
+}
+
+func metadataString(meta map[string]any, key string) string {
+	s, _ := meta[key].(string)
+	return s
+}
+
+func metadataNumber(meta map[string]any, key string) (float64, bool) {
+	n, ok := meta[key].(float64)
+	return n, ok
+}
+
+// classify sets Status and Reasons from the report and thresholds: red when
+// telemetry is unavailable or the execution error rate reaches
+// RedErrorRate; amber when it reaches AmberErrorRate, the last run failed or
+// more than MaxInFlight executions are in flight; green otherwise.
+func (r *healthReport) classify(t healthThresholds) {
+	r.Status = healthGreen
+	r.Reasons = nil
+	flag := func(status, reason string) {
+		if status == healthRed || r.Status == healthGreen {
+			r.Status = status
+		}
+		r.Reasons = append(r.Reasons, reason)
+	}
+
+	if r.Error != "" {
+		flag(healthRed, "telemetry unavailable")
+		return
+	}
+	rate := fmt.Sprintf("error rate %.0f%%", r.ErrorRate*100)
+	switch {
+	case t.RedErrorRate > 0 && r.ErrorRate >= t.RedErrorRate:
+		flag(healthRed, fmt.Sprintf("%s >= %.0f%%", rate, t.RedErrorRate*100))
+	case t.AmberErrorRate > 0 && r.ErrorRate >= t.AmberErrorRate:
+		flag(healthAmber, fmt.Sprintf("%s >= %.0f%%", rate, t.AmberErrorRate*100))
+	}
+	if r.LastRunFailed {
+		flag(healthAmber, "last run failed")
+	}
+	if t.MaxInFlight > 0 && r.InFlight > t.MaxInFlight {
+		flag(healthAmber, fmt.Sprintf("%d executions in flight > %d", r.InFlight, t.MaxInFlight))
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// telemetryRun returns the events of one /run execution starting at ms.
+func telemetryRun(ms int64, failNode string) []mcp.TelemetryEvent {
+	events := []mcp.TelemetryEvent{
+		{Type: "request-in", Timestamp: ms},
+		{Type: "node-start", Timestamp: ms + 1, Metadata: map[string]any{"node": "fetch"}},
+		{Type: "node-complete", Timestamp: ms + 21, Metadata: map[string]any{"node": "fetch", "durationMs": float64(20)}},
+		{Type: "node-start", Timestamp: ms + 21, Metadata: map[string]any{"node": "summarize"}},
+	}
+	if failNode != "" {
+		events = append(events, mcp.TelemetryEvent{Type: "node-error", Timestamp: ms + 61, Metadata: map[string]any{"node": failNode, "error": "model timeout"}})
+	} else {
+		events = append(events,
+			mcp.TelemetryEvent{Type: "node-complete", Timestamp: ms + 101, Metadata: map[string]any{
+				"node": "summarize", "durationMs": float64(80),
+				"gen_ai.request.model": "claude", "gen_ai.usage.input_tokens": float64(100), "gen_ai.usage.output_tokens": float64(20),
+			}})
+	}
+	return append(events, mcp.TelemetryEvent{Type: "request-out", Timestamp: ms + 110})
+}
+
+func TestNewHealthReport_DerivesExecutionsNodesAndTokens(t *testing.T) {
+	var events []mcp.TelemetryEvent
+	events = append(events, mcp.TelemetryEvent{Type: "engine-start", Timestamp: 0})
+	events = append(events, telemetryRun(1000, "")...)
+	events = append(events, telemetryRun(2000, "summarize")...)
+	events = append(events, telemetryRun(3000, "")...)
+	lastErr := "model timeout"
+
+	report := newHealthReport(&mcp.WfHealthResult{Name: "wf", Namespace: "ns", Detail: &mcp.TelemetrySnapshot{
+		RecentEvents: events, LastError: &lastErr, LastRunFailed: false, UptimeMs: 60000,
+	}})
+
+	if report.ExecutionCount != 3 || report.FailedExecutions != 1 {
+		t.Fatalf("expected 3 executions with 1 failure, got %d/%d", report.ExecutionCount, report.FailedExecutions)
+	}
+	if got := report.Executions[0].StartedAt.UnixMilli(); got != 3000 {
+		t.Errorf("expected newest execution first, got start %d", got)
+	}
+	if e := report.Executions[1]; !e.Failed || e.DurationMs != 110 || len(e.Errors) != 1 || e.Errors[0] != "summarize: model timeout" {
+		t.Errorf("unexpected failed execution: %+v", e)
+	}
+	if len(report.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %+v", report.Nodes)
+	}
+	if n := report.Nodes[1]; n.Node != "summarize" || n.Runs != 3 || n.Errors != 1 || n.MaxMs != 80 || n.AvgMs != (80+40+80)/3 {
+		t.Errorf("unexpected summarize stats: %+v", n)
+	}
+	if len(report.Tokens) != 1 || report.Tokens[0] != (healthTokenUsage{Model: "claude", Calls: 2, InputTokens: 200, OutputTokens: 40}) {
+		t.Errorf("unexpected token usage: %+v", report.Tokens)
+	}
+	if report.LastError != "model timeout" {
+		t.Errorf("expected last error, got %q", report.LastError)
+	}
+}
+
+func TestHealthReport_Classify(t *testing.T) {
+	tests := []struct {
+		name   string
+		report healthReport
+		want   string
+		reason string
+	}{
+		{"green", healthReport{ExecutionCount: 10, ErrorRate: 0.05}, healthGreen, ""},
+		{"amber error rate", healthReport{ErrorRate: 0.2}, healthAmber, "error rate 20% >= 10%"},
+		{"red error rate", healthReport{ErrorRate: 0.5, LastRunFailed: true}, healthRed, "error rate 50% >= 50%; last run failed"},
+		{"last run failed", healthReport{LastRunFailed: true}, healthAmber, "last run failed"},
+		{"in flight", healthReport{InFlight: 6}, healthAmber, "6 executions in flight > 5"},
+		{"unavailable", healthReport{Error: "connection refused"}, healthRed, "telemetry unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.classify(defaultHealthThresholds)
+			if tt.report.Status != tt.want || strings.Join(tt.report.Reasons, "; ") != tt.reason {
+				t.Errorf("got %s (%v), want %s (%s)", tt.report.Status, tt.report.Reasons, tt.want, tt.reason)
+			}
+		})
+	}
+}
+
+func TestHealthThresholds_WithConfig(t *testing.T) {
+	got := defaultHealthThresholds.withConfig(HealthConfig{RedErrorRate: 0.3})
+	want := healthThresholds{AmberErrorRate: 0.1, RedErrorRate: 0.3, MaxInFlight: 5}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
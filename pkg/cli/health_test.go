@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func runHealthCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := withRootFlags(NewHealthCmd())
+	cmd.SetArgs(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestHealth_SingleWorkflow(t *testing.T) {
+	srv := deployRunFixture(t)
+	var events []mcp.TelemetryEvent
+	events = append(events, telemetryRun(1000, "")...)
+	events = append(events, telemetryRun(2000, "summarize")...)
+	if err := srv.SetTelemetry("default", "test-workflow", mcp.TelemetrySnapshot{RecentEvents: events, LastRunFailed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runHealthCmd(t, "test-workflow")
+	if err != nil {
+		t.Fatalf("health: %v", err)
+	}
+	for _, want := range []string{
+		"Health:      red (error rate 50% >= 50%; last run failed)",
+		"Executions:  2 recent, 1 failed (50.0%)",
+		"failed: summarize: model timeout",
+		"summarize  2     1       60ms  80ms",
+		"claude  1      100           20",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out, err = runHealthCmd(t, "test-workflow", "--red-error-rate", "0.9", "-o", "json")
+	if err != nil {
+		t.Fatalf("health -o json: %v", err)
+	}
+	var report healthReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if report.Status != healthAmber || report.ExecutionCount != 2 || len(report.Nodes) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestHealth_AllUsesConfigThresholds(t *testing.T) {
+	srv := deployRunFixture(t)
+	home, _ := os.UserHomeDir()
+	if err := os.MkdirAll(filepath.Join(home, ".tentacular"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte("health:\n  max_in_flight: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetTelemetry("default", "test-workflow", mcp.TelemetrySnapshot{InFlight: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runHealthCmd(t, "--all")
+	if err != nil {
+		t.Fatalf("health --all: %v", err)
+	}
+	if !strings.Contains(out, "test-workflow") || !strings.Contains(out, "A amber") || !strings.Contains(out, "2 executions in flight > 1") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+
+	if _, err := runHealthCmd(t); err == nil {
+		t.Error("expected error without a name or --all")
+	}
+}
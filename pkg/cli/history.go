@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func NewHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "List deployed revisions of a workflow",
+		Long: `List the revisions recorded by tntc deploy and tntc rollback for the
+active cluster, newest first. The current revision is marked with *.
+
+Revisions are committed to the git-state repo (revisions/) when git-state is
+enabled, and otherwise kept in the cluster, in the <name>-revisions ConfigMap
+of the workflow namespace.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runHistory,
+	}
+	addDirectFlags(cmd)
+	return cmd
+}
+
+func NewRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <name>",
+		Short: "Re-apply a previously deployed revision",
+		Long: `Re-apply the manifests of a recorded revision: the previous one by default,
+or --to <rev>. Secrets are not stored in revisions; they are re-resolved from
+the workflow's .secrets.yaml at its current source. The rollback is recorded
+as a new revision. The rollback holds the workflow's deploy lock while it
+applies, like tntc deploy.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRollback,
+	}
+	cmd.Flags().Int("to", 0, "Revision to roll back to (default: the previous revision)")
+	cmd.Flags().Bool("wait", false, "Wait for the rollout to become ready")
+	cmd.Flags().Duration("wait-timeout", defaultWatchTimeout, "Maximum time to --wait for the rollout")
+	addLockFlags(cmd)
+	addResumeFlag(cmd)
+	addDirectFlags(cmd)
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	namespace := resolveNamespace(cmd, ".")
+	cfg := LoadConfig()
+
+	var client mcp.WorkflowClient
+	if !gitStateRevisions(cfg) {
+		var err error
+		if client, err = resolveWorkflowClient(cmd); err != nil {
+			return err
+		}
+	}
+	revs, err := resolveRevisionStore(cmd.Context(), cfg, client).list(activeClusterName(cmd, cfg), namespace, name)
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if flagString(cmd, "output") == "json" {
+		// Manifests are omitted from the listing; rollback reads them.
+		for i := range revs {
+			revs[i].Manifests = nil
+		}
+		data, err := json.MarshalIndent(revs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(w, string(data))
+		return nil
+	}
+	if len(revs) == 0 {
+		_, _ = fmt.Fprintf(w, "No revisions recorded for %s in namespace %s\n", name, namespace)
+		return nil
+	}
+	writeRevisionsText(w, revs)
+	return nil
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	startedAt := time.Now().UTC()
+	name := args[0]
+	namespace := resolveNamespace(cmd, ".")
+	to, _ := cmd.Flags().GetInt("to")
+	cfg := LoadConfig()
+	cluster := activeClusterName(cmd, cfg)
+	w := StatusWriter(cmd)
+
+	client, err := resolveWorkflowClient(cmd)
+	if err != nil {
+		return err
+	}
+	target, err := rollbackTarget(resolveRevisionStore(cmd.Context(), cfg, client), cluster, namespace, name, to)
+	if err != nil {
+		return err
+	}
+	release, err := newDeployLocker(cmd, cluster).lockCluster(cmd.Context(), client, namespace, name)
+	defer release()
+	if err != nil {
+		return err
+	}
+	resume, _ := cmd.Flags().GetBool("resume")
+	if err := checkNotSuspended(cmd.Context(), client, namespace, name, resume, w); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "Rolling back %s in %s to revision %d (%s)...\n", name, namespace, target.Revision, orDash(target.Version))
+	if err := reapplyRevision(cmd, client, target, cluster, w); err != nil {
+		return err
+	}
+
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		if _, err := watchRollout(cmd.Context(), client, namespace, name, watchPollInterval, timeout, w); err != nil {
+			return fmt.Errorf("rollback of %s: %w", name, err)
+		}
+	}
+
+	return EmitResult(cmd, CommandResult{
+		Version: "1",
+		Command: "rollback",
+		Status:  "pass",
+		Summary: fmt.Sprintf("rolled back %s in %s to revision %d", name, namespace, target.Revision),
+		Hints:   []string{},
+		Timing: TimingInfo{
+			StartedAt:  startedAt.Format(time.RFC3339),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		},
+	}, cmd.OutOrStdout())
+}
+
+// reapplyRevision applies the manifests of target, stamped with the current
+// deployer, and records the result as a new revision rolling back to it.
+// The caller holds the workflow's deploy lock.
+func reapplyRevision(cmd *cobra.Command, client mcp.WorkflowClient, target *deployRevision, cluster string, w io.Writer) error {
+	manifests, err := rollbackManifests(target, w)
+	if err != nil {
+		return err
+	}
+	injectDeployerAnnotations(manifests, resolveDeployerIdentity(cluster), time.Now())
+
+	applied, err := client.WfApply(cmd.Context(), target.Namespace, target.Workflow, manifests)
+	if err != nil {
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("applying revision %d: %w\n  hint: %s", target.Revision, err, hint)
+		}
+		return fmt.Errorf("applying revision %d: %w", target.Revision, err)
+	}
+	for _, a := range applied.Applied {
+		_, _ = fmt.Fprintf(w, "  applied %s\n", a)
+	}
+
+	rev := *target
+	rev.RollbackOf = target.Revision
+	rev.DeployedAt = time.Now().UTC()
+	recordRevision(cmd, client, rev)
+	return nil
+}
+
+// rollbackTarget returns revision to, or with to == 0 the revision before
+// the current one.
+func rollbackTarget(store revisionStore, cluster, namespace, name string, to int) (*deployRevision, error) {
+	if to > 0 {
+		return store.get(cluster, namespace, name, to)
+	}
+	revs, err := store.list(cluster, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) < 2 {
+		return nil, fmt.Errorf("no previous revision of %s in %s to roll back to (see tntc history %s)", name, namespace, name)
+	}
+	return &revs[1], nil
+}
+
+// rollbackManifests returns the revision's manifests with the Secret
+// re-resolved from the workflow's current .secrets.yaml. When the workflow
+// directory is gone the Secret currently deployed is left in place.
+func rollbackManifests(rev *deployRevision, w io.Writer) ([]map[string]any, error) {
+	manifests := append([]map[string]any(nil), rev.Manifests...)
+	if !rev.HasSecrets {
+		return manifests, nil
+	}
+	if _, err := os.Stat(filepath.Join(rev.WorkflowDir, ".secrets.yaml")); rev.WorkflowDir == "" || errors.Is(err, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(w, "Warning: secrets source for revision %d not found; keeping the deployed %s-secrets\n", rev.Revision, rev.Workflow)
+		return manifests, nil
+	}
+	secret, err := buildSecretManifest(rev.WorkflowDir, rev.Workflow, rev.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("re-resolving secrets for revision %d: %w", rev.Revision, err)
+	}
+	if secret == nil {
+		return manifests, nil
+	}
+	var obj map[string]any
+	if err := yaml.Unmarshal([]byte(secret.Content), &obj); err != nil {
+		return nil, fmt.Errorf("serializing manifest Secret/%s: %w", secret.Name, err)
+	}
+	return append(manifests, obj), nil
+}
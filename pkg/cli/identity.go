@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/version"
+)
+
+// Deployer annotations stamped on the workflow Deployment by every deploy,
+// rollback and promote.
+const (
+	annotationDeployedBy        = "tentacular.io/deployed-by"
+	annotationDeployedBySubject = "tentacular.io/deployed-by-subject"
+	annotationDeployedBySource  = "tentacular.io/deployed-by-source"
+	annotationDeployedVia       = "tentacular.io/deployed-via"
+	annotationDeployedAt        = "tentacular.io/deployed-at"
+)
+
+// deployerIdentity is who is running a deploy: the OIDC login for the
+// cluster when there is one, otherwise the local git user.
+type deployerIdentity struct {
+	Email   string `json:"email,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Source  string `json:"source"` // "oidc", "git" or "local"
+}
+
+// String returns the most specific identifier available.
+func (d deployerIdentity) String() string {
+	switch {
+	case d.Email != "":
+		return d.Email
+	case d.Subject != "":
+		return d.Subject
+	case d.Name != "":
+		return d.Name
+	}
+	return "unknown"
+}
+
+// resolveDeployerIdentity reads the claims of TNTC_ACCESS_TOKEN or the cached
+// OIDC token for clusterName. Expired tokens still identify the user and are
+// not refreshed here. Without a token it falls back to git's user.email and
+// user.name, then to $USER.
+func resolveDeployerIdentity(clusterName string) deployerIdentity {
+	token := os.Getenv("TNTC_ACCESS_TOKEN")
+	if token == "" {
+		tokenEnv := clusterName
+		if tokenEnv == "" {
+			tokenEnv = "default"
+		}
+		if store, err := LoadOIDCToken(tokenEnv); err == nil && store != nil {
+			token = store.AccessToken
+		}
+	}
+	if token != "" {
+		if claims, err := DecodeJWTClaims(token); err == nil && (claims.Email != "" || claims.Sub != "") {
+			email := claims.Email
+			if email == "" {
+				email = claims.PreferredUsername
+			}
+			return deployerIdentity{Email: email, Name: claims.Name, Subject: claims.Sub, Source: "oidc"}
+		}
+	}
+
+	id := deployerIdentity{Email: gitConfig("user.email"), Name: gitConfig("user.name"), Source: "git"}
+	if id.Email == "" && id.Name == "" {
+		id = deployerIdentity{Name: os.Getenv("USER"), Source: "local"}
+	}
+	return id
+}
+
+// gitConfig returns a git config value, or "" when unset or git is unavailable.
+func gitConfig(key string) string {
+	out, err := exec.CommandContext(context.Background(), "git", "config", "--get", key).Output() //nolint:gosec // key is a constant
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// injectDeployerAnnotations stamps who deployed, with which CLI version and
+// when, on every Deployment manifest. The OIDC subject is omitted for git
+// and local identities, which are self-asserted.
+func injectDeployerAnnotations(manifests []map[string]any, id deployerIdentity, at time.Time) {
+	for _, obj := range manifests {
+		if kind, _ := obj["kind"].(string); kind != "Deployment" {
+			continue
+		}
+		annotations := manifestAnnotations(obj)
+		annotations[annotationDeployedBy] = id.String()
+		annotations[annotationDeployedBySource] = id.Source
+		annotations[annotationDeployedVia] = "tntc/" + version.Version
+		annotations[annotationDeployedAt] = at.UTC().Format(time.RFC3339)
+		if id.Subject != "" {
+			annotations[annotationDeployedBySubject] = id.Subject
+		} else {
+			delete(annotations, annotationDeployedBySubject)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func TestResolveDeployerIdentity(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TNTC_ACCESS_TOKEN", buildTestJWT(map[string]any{"sub": "user-1", "email": "alice@example.com", "name": "Alice"}))
+	if id := resolveDeployerIdentity("prod"); id.Source != "oidc" || id.String() != "alice@example.com" || id.Subject != "user-1" {
+		t.Errorf("expected the OIDC identity, got %+v", id)
+	}
+
+	t.Setenv("TNTC_ACCESS_TOKEN", "")
+	gitConfigFile := filepath.Join(t.TempDir(), "gitconfig")
+	if err := os.WriteFile(gitConfigFile, []byte("[user]\n\temail = dev@example.com\n\tname = Dev\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfigFile)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(t.TempDir())
+	if id := resolveDeployerIdentity("prod"); id.Source != "git" || id.String() != "dev@example.com" {
+		t.Errorf("expected the git identity without a login, got %+v", id)
+	}
+}
+
+func TestDeploy_StampsDeployerIdentity(t *testing.T) {
+	srv := deployRunFixture(t)
+	t.Setenv("TNTC_ACCESS_TOKEN", buildTestJWT(map[string]any{"sub": "user-1", "email": "alice@example.com"}))
+	if err := runDeployCmd(t, "--image", "engine:2.0"); err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+
+	wf, _ := srv.Workflow("default", "test-workflow")
+	for key, want := range map[string]string{
+		annotationDeployedBy:        "alice@example.com",
+		annotationDeployedBySubject: "user-1",
+		annotationDeployedBySource:  "oidc",
+		annotationDeployedVia:       "tntc/dev",
+	} {
+		if got := wf.Annotations[key]; got != want {
+			t.Errorf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+	if wf.Annotations[annotationDeployedAt] == "" {
+		t.Error("expected a deploy timestamp annotation")
+	}
+
+	list := withRootFlags(NewListCmd())
+	list.SetArgs(nil)
+	list.SetOut(&bytes.Buffer{})
+	out := captureStdout(t, func() { _ = list.ExecuteContext(context.Background()) })
+	if !strings.Contains(out, "DEPLOYED BY") || !strings.Contains(out, "alice@example.com") {
+		t.Errorf("expected the deployer in tntc list:\n%s", out)
+	}
+
+	text := runDescribeCmd(t, "test-workflow")
+	if !strings.Contains(text, "Deployed by: alice@example.com via tntc/dev at ") {
+		t.Errorf("expected the deployer in tntc describe:\n%s", text)
+	}
+}
+
+func TestInjectDeployerAnnotations_MarksSelfAssertedIdentity(t *testing.T) {
+	manifests := []map[string]any{
+		{"kind": "ConfigMap", "metadata": map[string]any{"name": "wf-code"}},
+		{"kind": "Deployment", "metadata": map[string]any{"name": "wf", "annotations": map[string]any{
+			annotationDeployedBySubject: "user-1",
+		}}},
+	}
+	injectDeployerAnnotations(manifests, deployerIdentity{Email: "dev@example.com", Source: "git"}, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	if _, ok := manifests[0]["metadata"].(map[string]any)["annotations"]; ok {
+		t.Error("expected non-Deployment manifests untouched")
+	}
+	ann := manifests[1]["metadata"].(map[string]any)["annotations"].(map[string]any)
+	if ann[annotationDeployedBy] != "dev@example.com" || ann[annotationDeployedBySource] != "git" {
+		t.Errorf("unexpected annotations: %v", ann)
+	}
+	if _, ok := ann[annotationDeployedBySubject]; ok {
+		t.Error("expected a stale OIDC subject removed for a git identity")
+	}
+
+	d := buildDescribeResult(&mcp.WfDescribeResult{Name: "wf", Annotations: map[string]string{
+		annotationDeployedBy: "dev@example.com", annotationDeployedBySource: "git", annotationDeployedVia: "tntc/1.2.0",
+	}}).Deployer
+	if d == nil || d.String() != "dev@example.com (git identity) via tntc/1.2.0" {
+		t.Errorf("unexpected describe deployer: %v", d)
+	}
+}
@@ -9,24 +9,26 @@ import (
 )
 
 func NewListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List deployed workflows",
 		Args:  cobra.NoArgs,
 		RunE:  runList,
 	}
+	addDirectFlags(cmd)
+	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
 	namespace := resolveNamespace(cmd, "")
 	output, _ := cmd.Flags().GetString("output")
 
-	mcpClient, err := requireMCPClient(cmd)
+	client, err := resolveWorkflowClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	workflows, err := mcpClient.WfList(cmd.Context(), namespace)
+	workflows, err := client.WfList(cmd.Context(), namespace)
 	if err != nil {
 		if hint := mcpErrorHint(err); hint != "" {
 			return fmt.Errorf("listing workflows: %w\n  hint: %s", err, hint)
@@ -48,10 +50,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("%-24s %-8s %-16s %-10s %-10s %-6s %s\n", "NAME", "VERSION", "NAMESPACE", "STATUS", "REPLICAS", "AGE", "DESCRIPTION")
+	fmt.Printf("%-24s %-8s %-16s %-10s %-10s %-6s %-24s %s\n", "NAME", "VERSION", "NAMESPACE", "STATUS", "REPLICAS", "AGE", "DEPLOYED BY", "DESCRIPTION")
 	for _, w := range workflows {
 		status := "not ready"
-		if w.Ready {
+		switch {
+		case w.Suspended:
+			status = "suspended"
+		case w.Ready:
 			status = "ready"
 		}
 		age := w.Age
@@ -61,7 +66,8 @@ func runList(cmd *cobra.Command, args []string) error {
 			}
 		}
 		desc := truncateString(w.Description, 40)
-		fmt.Printf("%-24s %-8s %-16s %-10s %d/%-9d %-6s %s\n", w.Name, w.Version, w.Namespace, status, w.Available, w.Replicas, age, desc)
+		deployedBy := truncateString(orDash(w.DeployedBy), 24)
+		fmt.Printf("%-24s %-8s %-16s %-10s %d/%-9d %-6s %-24s %s\n", w.Name, w.Version, w.Namespace, status, w.Available, w.Replicas, age, deployedBy, desc)
 	}
 
 	return nil
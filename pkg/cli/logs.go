@@ -1,58 +1,366 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
+// logsPollInterval is how often --follow polls for new log lines.
+var logsPollInterval = 2 * time.Second
+
+// logsFollowTail caps the lines fetched per pod on each --follow poll.
+const logsFollowTail = 1000
+
 func NewLogsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logs <name>",
 		Short: "View workflow pod logs",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runLogs,
+		Long: `View workflow pod logs.
+
+By default the engine container of the workflow's first pod is shown. --all-pods
+shows every pod, prefixing each line with its pod name. --follow keeps polling
+for new lines (de-duplicated by timestamp) until interrupted.
+
+Engine log lines (JSON/OTel or "[node] LEVEL message") are parsed into time,
+level, node, execution ID and trace ID. --grep, --node and --execution filter
+on them. --execution reconstructs one run's timeline across all pods, ordered
+by time. -o json prints one normalised record per line; text output is
+coloured by level on a terminal (set NO_COLOR to disable).`,
+		Args: cobra.ExactArgs(1),
+		RunE: runLogs,
 	}
 	cmd.Flags().Int64("tail", 100, "Number of recent log lines to show")
+	cmd.Flags().BoolP("follow", "f", false, "Keep streaming new log lines")
+	cmd.Flags().String("container", "", "Container to read (default: the engine container)")
+	cmd.Flags().Duration("since", 0, "Only show lines newer than this (e.g. 10m, 1h)")
+	cmd.Flags().Bool("all-pods", false, "Show logs from every pod of the workflow")
+	cmd.Flags().String("grep", "", "Only show lines matching this regular expression")
+	cmd.Flags().String("node", "", "Only show engine lines logged by this node")
+	cmd.Flags().String("execution", "", "Show the timeline of one execution across all pods")
+	addDirectFlags(cmd)
 	return cmd
 }
 
+// logsOptions are the parsed flags of `tntc logs`.
+type logsOptions struct {
+	grep      *regexp.Regexp
+	container string
+	node      string
+	execution string
+	tail      int64
+	since     time.Duration
+	allPods   bool
+	json      bool
+	color     bool
+}
+
+// match reports whether a record passes the --grep, --node and --execution filters.
+func (o *logsOptions) match(rec *logRecord) bool {
+	if o.grep != nil && !o.grep.MatchString(rec.raw) {
+		return false
+	}
+	if o.node != "" && rec.Node != o.node {
+		return false
+	}
+	return o.execution == "" || rec.ExecutionID == o.execution
+}
+
 func runLogs(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	namespace := resolveNamespace(cmd, ".")
-	tailLines, _ := cmd.Flags().GetInt64("tail")
+	follow, _ := cmd.Flags().GetBool("follow")
+	out := cmd.OutOrStdout()
+
+	opts := logsOptions{container: flagString(cmd, "container"), node: flagString(cmd, "node"), execution: flagString(cmd, "execution")}
+	opts.tail, _ = cmd.Flags().GetInt64("tail")
+	opts.since, _ = cmd.Flags().GetDuration("since")
+	opts.allPods, _ = cmd.Flags().GetBool("all-pods")
+	opts.json = flagString(cmd, "output") == "json"
+	opts.color = !opts.json && useColor(out)
+	if opts.execution != "" {
+		// An execution may have been served by any replica.
+		opts.allPods = true
+	}
+	if pattern := flagString(cmd, "grep"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		opts.grep = re
+	}
 
-	mcpClient, err := requireMCPClient(cmd)
+	client, err := resolveWorkflowClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Resolve pod name from workflow name via wf_pods
-	pods, err := mcpClient.WfPods(cmd.Context(), namespace)
+	pods, err := workflowPodNames(cmd.Context(), client, namespace, name, opts.allPods)
 	if err != nil {
-		return fmt.Errorf("listing pods: %w", err)
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pod found for %s in namespace %s", name, namespace)
 	}
 
-	var podName string
+	if follow {
+		return followLogs(cmd.Context(), client, namespace, name, &opts, out)
+	}
+	var records []logRecord
+	for _, pod := range pods {
+		result, err := client.WfLogsQuery(cmd.Context(), mcp.WfLogsParams{
+			Namespace:    namespace,
+			Pod:          pod,
+			Container:    opts.container,
+			TailLines:    opts.tail,
+			SinceSeconds: sinceSeconds(opts.since),
+			Timestamps:   true,
+		})
+		if err != nil {
+			return logsError(err)
+		}
+		records = append(records, logRecords(result, stampLines(splitLogLines(result.LogText())))...)
+	}
+	if opts.execution != "" {
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	}
+	writeLogRecords(out, records, &opts)
+	return nil
+}
+
+func logsError(err error) error {
+	if hint := mcpErrorHint(err); hint != "" {
+		return fmt.Errorf("getting logs: %w\n  hint: %s", err, hint)
+	}
+	return fmt.Errorf("getting logs: %w", err)
+}
+
+// workflowPodNames returns the workflow's pods (name prefix "<name>-"): all of
+// them with allPods, otherwise the first one.
+func workflowPodNames(ctx context.Context, client mcp.WorkflowClient, namespace, name string, allPods bool) ([]string, error) {
+	pods, err := client.WfPods(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	var names []string
 	for _, p := range pods.Pods {
 		if strings.HasPrefix(p.Name, name+"-") {
-			podName = p.Name
-			break
+			names = append(names, p.Name)
+			if !allPods {
+				break
+			}
 		}
 	}
-	if podName == "" {
-		return fmt.Errorf("no pod found for %s in namespace %s", name, namespace)
+	return names, nil
+}
+
+// followLogs polls the workflow's pods for new lines until ctx is done. Pods
+// are re-listed every poll so a rollout's new pods are picked up.
+func followLogs(ctx context.Context, client mcp.WorkflowClient, namespace, name string, opts *logsOptions, out io.Writer) error {
+	cursors := map[string]*logCursor{}
+	for {
+		pods, err := workflowPodNames(ctx, client, namespace, name, opts.allPods)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		for _, pod := range pods {
+			cursor, seen := cursors[pod]
+			params := mcp.WfLogsParams{Namespace: namespace, Pod: pod, Container: opts.container, Timestamps: true}
+			if seen {
+				// Re-read a little before the last line so nothing written
+				// between polls is missed; the cursor drops the overlap.
+				params.SinceSeconds = int64(time.Since(cursor.last).Seconds()) + 2
+				params.TailLines = logsFollowTail
+			} else {
+				cursor = &logCursor{}
+				params.TailLines = opts.tail
+				params.SinceSeconds = sinceSeconds(opts.since)
+			}
+			result, err := client.WfLogsQuery(ctx, params)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: logs for %s: %v\n", pod, err)
+				continue
+			}
+			lines := splitLogLines(result.LogText())
+			if !seen && len(lines) == 0 {
+				// Nothing matched the initial window: start following from now.
+				cursor.last = time.Now()
+			}
+			cursors[pod] = cursor
+			writeLogRecords(out, logRecords(result, cursor.next(lines)), opts)
+		}
+		if err == nil {
+			for pod := range cursors {
+				if !slices.Contains(pods, pod) {
+					delete(cursors, pod)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logsPollInterval):
+		}
 	}
+}
+
+// stampedLine is a log line with the container runtime timestamp removed.
+type stampedLine struct {
+	at   time.Time // zero when the server returned no timestamp
+	text string
+}
+
+// stampLines splits the timestamps off lines fetched with timestamps enabled.
+func stampLines(lines []string) []stampedLine {
+	stamped := make([]stampedLine, len(lines))
+	for i, line := range lines {
+		at, text, _ := splitLogTimestamp(line)
+		stamped[i] = stampedLine{at: at, text: text}
+	}
+	return stamped
+}
+
+// logCursor tracks what a followed pod has already printed. Lines are
+// requested with timestamps and de-duplicated against the newest timestamp
+// seen; servers that ignore the timestamps option fall back to skipping the
+// overlap with the previous batch.
+type logCursor struct {
+	last   time.Time
+	atLast map[string]int // times each line stamped with last was printed
+	prev   []string       // previous untimestamped batch
+}
+
+// next returns the lines of batch not printed before. A line without a
+// timestamp inside a timestamped batch is treated as part of the entry
+// before it.
+func (c *logCursor) next(batch []string) []stampedLine {
+	if len(batch) == 0 {
+		return nil
+	}
+	if _, _, ok := splitLogTimestamp(batch[0]); !ok {
+		fresh := batch[logOverlap(c.prev, batch):]
+		c.prev = batch
+		return stampLines(fresh)
+	}
+
+	if c.atLast == nil {
+		c.atLast = map[string]int{}
+	}
+	var fresh []stampedLine
+	var at time.Time
+	seen := map[string]int{} // occurrences in this batch stamped with c.last
+	for _, line := range batch {
+		text := line
+		if t, rest, ok := splitLogTimestamp(line); ok {
+			at, text = t, rest
+		}
+		if at.Before(c.last) {
+			continue
+		}
+		if at.After(c.last) {
+			c.last = at
+			c.atLast = map[string]int{}
+			seen = map[string]int{}
+		}
+		seen[text]++
+		if seen[text] <= c.atLast[text] {
+			continue
+		}
+		c.atLast[text] = seen[text]
+		fresh = append(fresh, stampedLine{at: at, text: text})
+	}
+	return fresh
+}
 
-	result, err := mcpClient.WfLogs(cmd.Context(), namespace, podName, tailLines)
+// logOverlap returns the length of the longest suffix of prev that is a
+// prefix of next.
+func logOverlap(prev, next []string) int {
+	for k := min(len(prev), len(next)); k > 0; k-- {
+		if slices.Equal(prev[len(prev)-k:], next[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// splitLogTimestamp splits a "<RFC3339Nano> <text>" line as returned with
+// timestamps enabled.
+func splitLogTimestamp(line string) (time.Time, string, bool) {
+	ts, text, found := strings.Cut(line, " ")
+	if !found {
+		ts, text = line, ""
+	}
+	at, err := time.Parse(time.RFC3339Nano, ts)
 	if err != nil {
-		if hint := mcpErrorHint(err); hint != "" {
-			return fmt.Errorf("getting logs: %w\n  hint: %s", err, hint)
+		return time.Time{}, line, false
+	}
+	return at, text, true
+}
+
+func splitLogLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+// logRecords parses lines fetched from one pod.
+func logRecords(result *mcp.WfLogsResult, lines []stampedLine) []logRecord {
+	records := make([]logRecord, len(lines))
+	for i, l := range lines {
+		records[i] = parseLogLine(l.at, l.text)
+		records[i].Pod = result.Pod
+		records[i].Container = result.Container
+	}
+	return records
+}
+
+// writeLogRecords prints the records that pass the filters: one JSON object
+// per line with -o json, otherwise the original line, prefixed with the pod
+// name when several pods are shown and coloured by level.
+func writeLogRecords(w io.Writer, records []logRecord, opts *logsOptions) {
+	for i := range records {
+		rec := &records[i]
+		if !opts.match(rec) {
+			continue
+		}
+		if opts.json {
+			data, _ := json.Marshal(rec)
+			_, _ = fmt.Fprintln(w, string(data))
+			continue
+		}
+		line := rec.raw
+		if opts.allPods {
+			line = "[" + rec.Pod + "] " + line
 		}
-		return fmt.Errorf("getting logs: %w", err)
+		if color := levelColor(rec.Level); opts.color && color != "" {
+			line = color + line + ansiReset
+		}
+		_, _ = fmt.Fprintln(w, line)
 	}
+}
 
-	fmt.Println(result.LogText())
-	return nil
+func sinceSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return max(int64(d.Seconds()), 1)
 }
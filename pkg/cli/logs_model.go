@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalised log levels.
+const (
+	logLevelDebug = "debug"
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+)
+
+// logRecord is a log line normalised from the engine's JSON (OTel-style) or
+// plain "[node] LEVEL message" output. Lines that are neither keep only
+// Message and the container timestamp.
+type logRecord struct {
+	Time        time.Time      `json:"time"`
+	Pod         string         `json:"pod,omitempty"`
+	Container   string         `json:"container,omitempty"`
+	Level       string         `json:"level,omitempty"`
+	Node        string         `json:"node,omitempty"`
+	ExecutionID string         `json:"executionId,omitempty"`
+	TraceID     string         `json:"traceId,omitempty"`
+	SpanID      string         `json:"spanId,omitempty"`
+	Message     string         `json:"message"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+
+	raw string // the line as logged, without the container timestamp
+}
+
+// JSON keys recognised for each normalised field, in order of preference.
+var (
+	logTimeKeys      = []string{"time", "timestamp", "ts", "@timestamp"}
+	logLevelKeys     = []string{"level", "severityText", "severity", "lvl"}
+	logMessageKeys   = []string{"msg", "message", "body"}
+	logNodeKeys      = []string{"node", "node_id", "nodeId", "tentacular.node"}
+	logExecutionKeys = []string{"execution_id", "executionId", "tentacular.execution_id"}
+	logTraceKeys     = []string{"trace_id", "traceId", "traceID"}
+	logSpanKeys      = []string{"span_id", "spanId", "spanID"}
+)
+
+// parseLogLine normalises a log line. at is the container runtime timestamp
+// (zero when unknown); a timestamp in the line itself takes precedence.
+func parseLogLine(at time.Time, line string) logRecord {
+	rec := logRecord{Time: at, Message: line, raw: line}
+	trimmed := strings.TrimSpace(line)
+
+	if strings.HasPrefix(trimmed, "{") {
+		var fields map[string]any
+		if json.Unmarshal([]byte(trimmed), &fields) == nil {
+			parseLogFields(&rec, fields)
+			return rec
+		}
+	}
+
+	// Plain engine format: "[node] LEVEL message".
+	if rest, ok := strings.CutPrefix(trimmed, "["); ok {
+		if node, msg, found := strings.Cut(rest, "] "); found && node != "" && !strings.ContainsAny(node, " :") {
+			rec.Node = node
+			level, text, _ := strings.Cut(msg, " ")
+			if l := normaliseLogLevel(level); l != "" {
+				rec.Level = l
+				msg = text
+			}
+			rec.Message = msg
+		}
+	}
+	return rec
+}
+
+// parseLogFields fills rec from a JSON log object. Fields may sit at the top
+// level or under "attributes" (OTel log data model); anything not mapped to a
+// normalised field is kept in Attributes.
+func parseLogFields(rec *logRecord, fields map[string]any) {
+	attrs, _ := fields["attributes"].(map[string]any)
+	used := map[string]bool{"attributes": true}
+	take := func(keys []string) string {
+		for _, k := range keys {
+			if s := logFieldString(fields[k]); s != "" {
+				used[k] = true
+				return s
+			}
+			if s := logFieldString(attrs[k]); s != "" {
+				delete(attrs, k)
+				return s
+			}
+		}
+		return ""
+	}
+
+	if ts := take(logTimeKeys); ts != "" {
+		if t, ok := parseLogTime(ts); ok {
+			rec.Time = t
+		}
+	}
+	rec.Level = normaliseLogLevel(take(logLevelKeys))
+	rec.Message = take(logMessageKeys)
+	rec.Node = take(logNodeKeys)
+	rec.ExecutionID = take(logExecutionKeys)
+	rec.TraceID = take(logTraceKeys)
+	rec.SpanID = take(logSpanKeys)
+
+	for k, v := range fields {
+		if !used[k] {
+			if rec.Attributes == nil {
+				rec.Attributes = map[string]any{}
+			}
+			rec.Attributes[k] = v
+		}
+	}
+	for k, v := range attrs {
+		if rec.Attributes == nil {
+			rec.Attributes = map[string]any{}
+		}
+		rec.Attributes[k] = v
+	}
+}
+
+func logFieldString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// parseLogTime accepts RFC3339 strings and Unix epoch numbers in seconds,
+// milliseconds or nanoseconds.
+func parseLogTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), true
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case n >= 1e17:
+		return time.Unix(0, int64(n)).UTC(), true
+	case n >= 1e11:
+		return time.UnixMilli(int64(n)).UTC(), true
+	default:
+		return time.Unix(int64(n), int64((n-float64(int64(n)))*1e9)).UTC(), true
+	}
+}
+
+// normaliseLogLevel maps level names to debug, info, warn or error. Unknown
+// names return "".
+func normaliseLogLevel(level string) string {
+	switch strings.ToLower(level) {
+	case "trace", "debug":
+		return logLevelDebug
+	case "info", "notice":
+		return logLevelInfo
+	case "warn", "warning":
+		return logLevelWarn
+	case "error", "err", "fatal", "critical", "panic":
+		return logLevelError
+	}
+	return ""
+}
+
+// ANSI colours for text output by level.
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiGray   = "\033[90m"
+)
+
+func levelColor(level string) string {
+	switch level {
+	case logLevelError:
+		return ansiRed
+	case logLevelWarn:
+		return ansiYellow
+	case logLevelDebug:
+		return ansiGray
+	}
+	return ""
+}
+
+// useColor reports whether w is a terminal and NO_COLOR is unset.
+func useColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		line string
+		want logRecord
+	}{
+		{
+			name: "engine text format",
+			line: "[fetch] WARN slow response",
+			want: logRecord{Time: at, Node: "fetch", Level: logLevelWarn, Message: "slow response"},
+		},
+		{
+			name: "flat JSON",
+			line: `{"time":"2026-03-01T12:00:01Z","level":"ERROR","node":"summarize","execution_id":"exec-1","trace_id":"abc","msg":"boom","attempt":2}`,
+			want: logRecord{
+				Time: at.Add(time.Second), Level: logLevelError, Node: "summarize", ExecutionID: "exec-1",
+				TraceID: "abc", Message: "boom", Attributes: map[string]any{"attempt": float64(2)},
+			},
+		},
+		{
+			name: "OTel JSON with attributes and epoch millis",
+			line: `{"timestamp":1772366402000,"severityText":"warning","body":"retrying","traceId":"t1","spanId":"s1","attributes":{"tentacular.node":"fetch","executionId":"exec-2"}}`,
+			want: logRecord{
+				Time: at.Add(2 * time.Second), Level: logLevelWarn, Node: "fetch", ExecutionID: "exec-2",
+				TraceID: "t1", SpanID: "s1", Message: "retrying",
+			},
+		},
+		{
+			name: "unstructured",
+			line: "[2026-03-01T12:00:00Z] workflow started",
+			want: logRecord{Time: at, Message: "[2026-03-01T12:00:00Z] workflow started"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLogLine(at, tt.line)
+			got.raw = ""
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(tt.want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("parseLogLine:\n got %s\nwant %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestLogs_ExecutionTimelineJSON(t *testing.T) {
+	deployLogsFixture(t, []string{
+		`{"time":"2026-03-01T12:00:02Z","level":"info","node":"summarize","execution_id":"exec-7","msg":"done"}`,
+		`{"time":"2026-03-01T12:00:00Z","level":"info","node":"other","execution_id":"exec-8","msg":"skip"}`,
+		`{"time":"2026-03-01T12:00:01Z","level":"info","node":"fetch","execution_id":"exec-7","msg":"fetched"}`,
+	})
+
+	out, err := runLogsCmd(t, context.Background(), "test-workflow", "--execution", "exec-7", "-o", "json")
+	if err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	lines := splitLogLines(out)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %q", out)
+	}
+	var first, second logRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+	if first.Message != "fetched" || second.Message != "done" {
+		t.Errorf("expected records ordered by time, got %q then %q", first.Message, second.Message)
+	}
+	if first.Pod == "" || first.Node != "fetch" || first.ExecutionID != "exec-7" {
+		t.Errorf("expected normalised record with pod, got %+v", first)
+	}
+}
+
+func TestWriteLogRecords_ColoursByLevel(t *testing.T) {
+	records := []logRecord{
+		parseLogLine(time.Time{}, "[a] ERROR failed"),
+		parseLogLine(time.Time{}, "[a] INFO fine"),
+	}
+	var buf bytes.Buffer
+	writeLogRecords(&buf, records, &logsOptions{color: true})
+	lines := splitLogLines(buf.String())
+	if lines[0] != ansiRed+"[a] ERROR failed"+ansiReset || lines[1] != "[a] INFO fine" {
+		t.Errorf("unexpected colouring: %q", lines)
+	}
+
+	buf.Reset()
+	writeLogRecords(&buf, records, &logsOptions{})
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("expected no colour codes, got %q", buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+func deployLogsFixture(t *testing.T, lines []string) *mcptest.Server {
+	t.Helper()
+	srv := deployRunFixture(t)
+	if err := srv.SetLogs("default", "test-workflow", lines); err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func runLogsCmd(t *testing.T, ctx context.Context, args ...string) (string, error) {
+	t.Helper()
+	cmd := withRootFlags(NewLogsCmd())
+	cmd.SetArgs(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := cmd.ExecuteContext(ctx)
+	return out.String(), err
+}
+
+func TestLogs_Filters(t *testing.T) {
+	deployLogsFixture(t, []string{
+		"[fetch] INFO fetching https://example.com",
+		`{"level":"info","node":"summarize","execution_id":"exec-1","msg":"summarizing"}`,
+		`{"level":"error","node":"summarize","execution_id":"exec-2","msg":"model timeout"}`,
+		"server listening",
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"all", nil, []string{"fetching", "summarizing", "model timeout", "server listening"}},
+		{"grep", []string{"--grep", "time?out|listening"}, []string{"model timeout", "server listening"}},
+		{"node text format", []string{"--node", "fetch"}, []string{"fetching"}},
+		{"node json format", []string{"--node", "summarize"}, []string{"summarizing", "model timeout"}},
+		{"execution", []string{"--execution", "exec-2"}, []string{"model timeout"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runLogsCmd(t, context.Background(), append([]string{"test-workflow"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("logs: %v", err)
+			}
+			lines := splitLogLines(out)
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d lines, got %q", len(tt.want), out)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(lines[i], want) {
+					t.Errorf("line %d: expected %q, got %q", i, want, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLogs_ContainerAndAllPods(t *testing.T) {
+	srv := deployLogsFixture(t, []string{"engine line"})
+	if err := srv.SetContainerLogs("default", "test-workflow", "proxy", []string{"proxy line"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runLogsCmd(t, context.Background(), "test-workflow", "--container", "proxy")
+	if err != nil || strings.TrimSpace(out) != "proxy line" {
+		t.Fatalf("expected sidecar logs, got %q (%v)", out, err)
+	}
+	if _, err := runLogsCmd(t, context.Background(), "test-workflow", "--container", "missing"); err == nil {
+		t.Error("expected error for unknown container")
+	}
+
+	wf, _ := srv.Workflow("default", "test-workflow")
+	out, err = runLogsCmd(t, context.Background(), "test-workflow", "--all-pods")
+	if err != nil || strings.TrimSpace(out) != "["+wf.Pod+"] engine line" {
+		t.Fatalf("expected pod-prefixed logs, got %q (%v)", out, err)
+	}
+}
+
+func TestLogs_FollowPrintsNewLinesOnce(t *testing.T) {
+	srv := deployLogsFixture(t, []string{"first", "second"})
+	orig := logsPollInterval
+	logsPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { logsPollInterval = orig })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = srv.AppendLogs("default", "test-workflow", "third", "third")
+		time.Sleep(50 * time.Millisecond)
+		_ = srv.AppendLogs("default", "test-workflow", "fourth")
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	out, err := runLogsCmd(t, ctx, "test-workflow", "-f")
+	if err != nil {
+		t.Fatalf("logs -f: %v", err)
+	}
+	if got := strings.Join(splitLogLines(out), ","); got != "first,second,third,third,fourth" {
+		t.Errorf("expected each line once, got %q", got)
+	}
+}
+
+func lineTexts(lines []stampedLine) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, ",")
+}
+
+func TestLogCursor_TimestampDedup(t *testing.T) {
+	c := &logCursor{}
+	first := c.next([]string{
+		"2026-01-01T00:00:00.000000001Z a",
+		"2026-01-01T00:00:00.000000002Z b",
+		"2026-01-01T00:00:00.000000002Z b",
+	})
+	second := c.next([]string{
+		"2026-01-01T00:00:00.000000002Z b",
+		"2026-01-01T00:00:00.000000002Z b",
+		"2026-01-01T00:00:00.000000002Z b",
+		"2026-01-01T00:00:00.000000003Z c",
+	})
+	if lineTexts(first) != "a,b,b" || lineTexts(second) != "b,c" {
+		t.Errorf("unexpected batches: %q then %q", lineTexts(first), lineTexts(second))
+	}
+}
+
+func TestLogCursor_UntimestampedOverlap(t *testing.T) {
+	c := &logCursor{}
+	first := c.next([]string{"a", "b", "c"})
+	second := c.next([]string{"b", "c", "d", "e"})
+	if lineTexts(first) != "a,b,c" || lineTexts(second) != "d,e" {
+		t.Errorf("unexpected batches: %q then %q", lineTexts(first), lineTexts(second))
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcpfake"
+)
+
+// NewMCPCmd creates the "mcp" command group.
+func NewMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "MCP server utilities",
+	}
+	cmd.AddCommand(newMCPFakeCmd())
+	return cmd
+}
+
+func newMCPFakeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fake",
+		Short: "Run an in-memory fake MCP server for offline development",
+		Long: `Run an in-process fake of the tentacular-mcp server backed by an in-memory store.
+
+The fake implements the workflow (wf_*), enclave (enclave_*), audit_resources and
+cluster_profile tools over the real MCP streamable HTTP transport, so every tntc
+command can be exercised end-to-end without a cluster. State is lost on exit.
+
+Point tntc at it with:
+  export TNTC_MCP_ENDPOINT=http://127.0.0.1:8765/mcp`,
+		Args: cobra.NoArgs,
+		RunE: runMCPFake,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:8765", "Address to listen on")
+	cmd.Flags().StringSlice("enclave", nil, "Seed an enclave with this name (repeatable)")
+	return cmd
+}
+
+func runMCPFake(cmd *cobra.Command, _ []string) error {
+	addr, _ := cmd.Flags().GetString("listen")
+	enclaves, _ := cmd.Flags().GetStringSlice("enclave")
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return serveFakeMCP(ctx, ln, enclaves, cmd.OutOrStdout())
+}
+
+// serveFakeMCP serves a fresh fake MCP server on ln until ctx is done.
+func serveFakeMCP(ctx context.Context, ln net.Listener, enclaves []string, out io.Writer) error {
+	fake := mcpfake.New()
+	for _, name := range enclaves {
+		fake.AddEnclave(mcp.EnclaveInfoResult{Name: name, Owner: "dev@localhost"})
+	}
+
+	srv := &http.Server{Handler: fake.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	endpoint := fmt.Sprintf("http://%s/mcp", ln.Addr().String())
+	_, _ = fmt.Fprintf(out, "Fake MCP server listening on %s\n", endpoint)
+	_, _ = fmt.Fprintf(out, "  export TNTC_MCP_ENDPOINT=%s\n", endpoint)
+	_, _ = fmt.Fprintln(out, "Press Ctrl-C to stop (state is in-memory and discarded on exit).")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("fake MCP server: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Streaming MCP sessions hold connections open and the state is discarded
+	// anyway, so close immediately rather than shutting down gracefully.
+	if err := srv.Close(); err != nil {
+		return fmt.Errorf("stopping fake MCP server: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("fake MCP server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestServeFakeMCP_EndToEnd starts the fake server, deploys a workflow through
+// the normal MCP resolution path and lists it with the list command.
+func TestServeFakeMCP_EndToEnd(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- serveFakeMCP(ctx, ln, []string{"dev"}, &out) }()
+	t.Cleanup(func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("fake server did not stop")
+		}
+	})
+
+	endpoint := "http://" + ln.Addr().String() + "/mcp"
+	cleanup := setupMCPEnv(t, endpoint)
+	defer cleanup()
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(minimalWorkflowYAML), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "handler.ts"), []byte("export default async () => ({})\n"), 0o644)
+
+	cmd := withRootFlags(NewListCmd())
+	client, err := resolveWorkflowClient(cmd)
+	if err != nil {
+		t.Fatalf("resolving client: %v", err)
+	}
+	var status bytes.Buffer
+	if _, err := deployWorkflow(dir, InternalDeployOptions{Namespace: "default", Image: "engine:latest", StatusOut: &status}, client); err != nil {
+		t.Fatalf("deploy against fake: %v", err)
+	}
+
+	listOut := captureStdout(t, func() { err = cmd.RunE(cmd, nil) })
+	if err != nil {
+		t.Fatalf("list against fake: %v", err)
+	}
+	if !strings.Contains(listOut, "test-workflow") || !strings.Contains(listOut, "ready") {
+		t.Errorf("expected deployed workflow in list output, got:\n%s", listOut)
+	}
+	if !strings.Contains(out.String(), "export TNTC_MCP_ENDPOINT="+endpoint) {
+		t.Errorf("expected endpoint hint in banner, got:\n%s", out.String())
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
 // StatusWriter returns the appropriate writer for progress/status messages.
@@ -40,6 +42,9 @@ type CommandResult struct {
 	Execution any `json:"execution,omitempty"`
 	Manifests any `json:"manifests,omitempty"`
 
+	// MCP reports tool call attempts, retries and reconnects (set by EmitResult).
+	MCP *mcp.CallMetrics `json:"mcp,omitempty"`
+
 	Timing TimingInfo `json:"timing"`
 }
 
@@ -49,6 +54,9 @@ type CommandResult struct {
 func EmitResult(cmd *cobra.Command, result CommandResult, w io.Writer) error {
 	format, _ := cmd.Flags().GetString("output")
 	if format == "json" {
+		if result.MCP == nil {
+			result.MCP = mcpCallMetrics()
+		}
 		return emitJSON(result, w)
 	}
 	return emitText(result, w)
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -40,15 +44,27 @@ LimitRanges, and Pod Security Admission posture.
 
 Profiles are stored at .tentacular/envprofiles/<env>.md and <env>.json. AI agents
 load the markdown profile as context before designing tentacles for that environment.
+Each save also keeps a timestamped snapshot under envprofiles/history/<env>/; use
+'tntc cluster profile diff <env>' to report drift between snapshots.
+
+With --all, every configured environment is profiled concurrently (bounded by
+--concurrency, each limited by --timeout) and a combined summary table is printed.
+Failures are reported per environment without aborting the others.
+
+Saved JSON profiles are used by 'tntc validate' and 'tntc deploy' for an offline
+compatibility check (runtime class, egress enforcement, quota, secrets source).
 
 Profiles are automatically generated when running 'tntc configure'. Rebuild manually
 when the agent detects environment drift (new RuntimeClass, changed CNI, cluster upgrade).`,
 		RunE: runProfile,
 	}
+	cmd.AddCommand(NewProfileDiffCmd())
 	cmd.Flags().Bool("all", false, "Profile all configured environments")
 	cmd.Flags().String("output", "markdown", "Output format: markdown|json")
 	cmd.Flags().Bool("save", false, "Write profiles to .tentacular/envprofiles/")
 	cmd.Flags().Bool("force", false, "Rebuild even if a fresh profile exists (< 1h old)")
+	cmd.Flags().Int("concurrency", defaultProfileConcurrency, "Maximum environments profiled in parallel (with --all)")
+	cmd.Flags().Duration("timeout", defaultProfileTimeout, "Per-environment profiling timeout")
 	return cmd
 }
 
@@ -58,6 +74,20 @@ func runProfile(cmd *cobra.Command, args []string) error {
 	output, _ := cmd.Flags().GetString("output")
 	save, _ := cmd.Flags().GetBool("save")
 	force, _ := cmd.Flags().GetBool("force")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if all {
+		cfg := LoadConfig()
+		opts := profileAllOptions{
+			Output:      output,
+			Save:        save,
+			Force:       force,
+			Concurrency: concurrency,
+			Timeout:     timeout,
+		}
+		return runProfileAll(cfg, mcpEnvProfileFn(cfg), opts, os.Stdout, os.Stderr)
+	}
 
 	mcpClient, err := requireMCPClient(cmd)
 	if err != nil {
@@ -71,47 +101,323 @@ func runProfile(cmd *cobra.Command, args []string) error {
 		}
 		return result.Raw, nil
 	}
-
-	if all {
-		return runProfileAll(profileFn, output, save, force)
-	}
 	return runProfileForEnv(clusterName, output, save, force, profileFn)
 }
 
 // clusterProfileFn is the signature for the MCP-based profile function, injectable for tests.
 type clusterProfileFn func(ctx context.Context, namespace string) ([]byte, error)
 
-func runProfileAll(profileFn clusterProfileFn, output string, save, force bool) error {
-	cfg := LoadConfig()
+// envProfileFn profiles a named environment and returns the raw profile JSON.
+// Injectable for tests; mcpEnvProfileFn is the MCP-backed implementation.
+type envProfileFn func(ctx context.Context, clusterName string) ([]byte, error)
+
+// errNoMCPEndpoint marks environments that cannot be profiled because no MCP
+// endpoint is configured. Such environments are reported as skipped, not failed.
+var errNoMCPEndpoint = errors.New("MCP not configured")
+
+const (
+	defaultProfileConcurrency = 4
+	defaultProfileTimeout     = 60 * time.Second
+)
+
+// profileAllOptions controls multi-environment profiling.
+type profileAllOptions struct {
+	Output      string
+	Save        bool
+	Force       bool
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// profileEnvResult is the outcome of profiling a single environment.
+type profileEnvResult struct {
+	Profile  *k8s.ClusterProfile
+	Err      error
+	Env      string
+	Raw      []byte
+	Duration time.Duration
+	Skipped  bool // fresh profile on disk, or no MCP endpoint
+}
+
+// profileAllJSONEntry is one environment in the single document printed by
+// `profile --all -o json`: its profile, or why profiling it failed.
+type profileAllJSONEntry struct {
+	Profile json.RawMessage `json:"profile,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// mcpEnvProfileFn returns an envProfileFn that builds a per-environment MCP client
+// and calls cluster_profile for the environment's namespace.
+func mcpEnvProfileFn(cfg TentacularConfig) envProfileFn {
+	return func(ctx context.Context, clusterName string) ([]byte, error) {
+		profileFn, err := buildProfileFnForEnv(clusterName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if profileFn == nil {
+			return nil, errNoMCPEndpoint
+		}
+		return profileFn(ctx, profileNamespace(cfg, clusterName))
+	}
+}
+
+// profileNamespace resolves the namespace to profile for an environment:
+// env namespace > global namespace > "default".
+func profileNamespace(cfg TentacularConfig, clusterName string) string {
+	if env, ok := cfg.Clusters[clusterName]; ok && env.Namespace != "" {
+		return env.Namespace
+	}
+	if cfg.Namespace != "" {
+		return cfg.Namespace
+	}
+	return "default"
+}
+
+// profileIsFresh reports whether a saved markdown profile for clusterName is
+// younger than profileFreshnessThreshold, returning its age.
+func profileIsFresh(clusterName string) (time.Duration, bool) {
+	mdPath := filepath.Join(resolveProfileDir(), clusterName+".md")
+	fi, err := os.Stat(mdPath)
+	if err != nil {
+		return 0, false
+	}
+	age := time.Since(fi.ModTime())
+	return age, age < profileFreshnessThreshold
+}
+
+// profileEnvironments profiles envs with at most concurrency in flight, each bounded
+// by timeout. One line of progress is written to progress as each environment
+// finishes. Results are returned in the order of envs; failures never abort
+// the remaining environments.
+func profileEnvironments(ctx context.Context, envs []string, concurrency int, timeout time.Duration, fn envProfileFn, progress io.Writer) []profileEnvResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]profileEnvResult, len(envs))
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+
+	for i, env := range envs {
+		wg.Add(1)
+		go func(i int, env string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			envCtx, cancel := context.WithTimeout(ctx, timeout)
+			raw, err := callWithDeadline(envCtx, env, fn)
+			cancel()
+
+			r := profileEnvResult{Env: env, Raw: raw, Duration: time.Since(start)}
+			switch {
+			case errors.Is(err, errNoMCPEndpoint):
+				r.Skipped = true
+				r.Err = err
+			case errors.Is(err, context.DeadlineExceeded):
+				r.Err = fmt.Errorf("timed out after %s", timeout)
+			case err != nil:
+				r.Err = err
+			default:
+				var profile k8s.ClusterProfile
+				if jsonErr := json.Unmarshal(raw, &profile); jsonErr == nil {
+					profile.Environment = env
+					r.Profile = &profile
+				}
+			}
+			results[i] = r
+
+			mu.Lock()
+			done++
+			switch {
+			case r.Skipped:
+				_, _ = fmt.Fprintf(progress, "[%d/%d] - %s skipped (%s)\n", done, len(envs), env, r.Err)
+			case r.Err != nil:
+				_, _ = fmt.Fprintf(progress, "[%d/%d] \u26a0 %s failed: %s\n", done, len(envs), env, r.Err)
+			default:
+				_, _ = fmt.Fprintf(progress, "[%d/%d] \u2713 %s (%s)\n", done, len(envs), env, r.Duration.Truncate(100*time.Millisecond))
+			}
+			mu.Unlock()
+		}(i, env)
+	}
+	wg.Wait()
+	return results
+}
+
+// callWithDeadline runs fn and returns ctx.Err() as soon as ctx is done, even if
+// fn does not honour cancellation (the call is abandoned in that case).
+func callWithDeadline(ctx context.Context, env string, fn envProfileFn) ([]byte, error) {
+	type outcome struct {
+		err error
+		raw []byte
+	}
+	ch := make(chan outcome, 1)
+	go func() {
+		raw, err := fn(ctx, env)
+		ch <- outcome{raw: raw, err: err}
+	}()
+	select {
+	case o := <-ch:
+		return o.raw, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runProfileAll profiles every configured environment concurrently, then renders or
+// saves each profile in environment order and prints a combined summary table
+// (to progress with -o json).
+// Returns an error only when every attempted environment failed.
+func runProfileAll(cfg TentacularConfig, fn envProfileFn, opts profileAllOptions, out, progress io.Writer) error {
 	if len(cfg.Clusters) == 0 {
 		return errors.New("no environments configured in ~/.tentacular/config.yaml")
 	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultProfileTimeout
+	}
 
-	var errs []string
-	for clusterName := range cfg.Clusters {
-		fmt.Printf("Profiling environment %q...\n", clusterName)
-		if err := runProfileForEnv(clusterName, output, save, force, profileFn); err != nil {
-			fmt.Fprintf(os.Stderr, "  \u26a0 %s: %s\n", clusterName, err)
-			errs = append(errs, clusterName)
+	envs := make([]string, 0, len(cfg.Clusters))
+	for name := range cfg.Clusters {
+		envs = append(envs, name)
+	}
+	sort.Strings(envs)
+
+	// Freshness check happens up front so fresh environments never hit the cluster.
+	var pending []string
+	var fresh []profileEnvResult
+	for _, env := range envs {
+		if opts.Save && !opts.Force {
+			if age, ok := profileIsFresh(env); ok {
+				_, _ = fmt.Fprintf(progress, "  Skipping %q: profile written %s ago (use --force to override)\n",
+					env, age.Truncate(time.Minute))
+				fresh = append(fresh, profileEnvResult{Env: env, Skipped: true})
+				continue
+			}
+		}
+		pending = append(pending, env)
+	}
+
+	_, _ = fmt.Fprintf(progress, "Profiling %d environment(s) (concurrency %d)...\n", len(pending), opts.Concurrency)
+	results := profileEnvironments(context.Background(), pending, opts.Concurrency, opts.Timeout, fn, progress)
+
+	// With -o json stdout is a single document keyed by environment.
+	jsonDoc := opts.Output == "json" && !opts.Save
+	entries := make(map[string]profileAllJSONEntry, len(results))
+	failed := 0
+	for i := range results {
+		r := &results[i]
+		if r.Err != nil {
+			if !r.Skipped {
+				failed++
+				entries[r.Env] = profileAllJSONEntry{Error: r.Err.Error()}
+			}
+			continue
 		}
+		rendered := renderProfile(r.Raw, r.Env, opts.Output)
+		switch {
+		case opts.Save:
+			dir := resolveProfileDir()
+			if err := saveProfileRaw(r.Raw, rendered, r.Env, dir); err != nil {
+				r.Err = fmt.Errorf("saving profile: %w", err)
+				failed++
+				continue
+			}
+		case jsonDoc && !json.Valid(r.Raw):
+			entries[r.Env] = profileAllJSONEntry{Error: "cluster_profile returned invalid JSON"}
+		case jsonDoc:
+			entries[r.Env] = profileAllJSONEntry{Profile: r.Raw}
+		default:
+			_, _ = fmt.Fprintln(out, rendered)
+		}
+	}
+	if jsonDoc {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding profiles: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
 	}
-	if len(errs) == len(cfg.Clusters) {
+
+	all := make([]profileEnvResult, 0, len(fresh)+len(results))
+	all = append(all, fresh...)
+	all = append(all, results...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Env < all[j].Env })
+	// Keep stdout machine-readable with -o json: the table goes with the progress.
+	summaryOut := out
+	if opts.Output == "json" {
+		summaryOut = progress
+	}
+	writeProfileSummary(summaryOut, all)
+
+	if len(pending) > 0 && failed == len(pending) {
 		return errors.New("all environments failed to profile")
 	}
 	return nil
 }
 
+// writeProfileSummary prints a combined table of per-environment profile results.
+func writeProfileSummary(w io.Writer, results []profileEnvResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ENVIRONMENT\tK8S\tGVISOR\tCNI\tQUOTA\tSTATUS")
+	for _, r := range results {
+		k8sVersion, gvisor, cni, quota := "-", "-", "-", "-"
+		if p := r.Profile; p != nil {
+			k8sVersion = p.K8sVersion
+			gvisor = "no"
+			if p.GVisor {
+				gvisor = "yes"
+			}
+			cni = p.CNI.Name
+			if p.Quota != nil {
+				quota = fmt.Sprintf("cpu %s / mem %s", orDash(p.Quota.CPULimit), orDash(p.Quota.MemoryLimit))
+			}
+		}
+		status := "ok"
+		switch {
+		case r.Skipped && r.Err != nil:
+			status = "skipped: " + r.Err.Error()
+		case r.Skipped:
+			status = "skipped (fresh)"
+		case r.Err != nil:
+			status = "failed: " + r.Err.Error()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Env, k8sVersion, gvisor, cni, quota, status)
+	}
+	_ = tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// renderProfile renders raw profile JSON as markdown (default) or JSON. Raw JSON
+// is returned unchanged when it does not match the ClusterProfile schema.
+func renderProfile(rawJSON []byte, label, output string) string {
+	if output == "json" {
+		return string(rawJSON)
+	}
+	var profile k8s.ClusterProfile
+	if jsonErr := json.Unmarshal(rawJSON, &profile); jsonErr != nil {
+		return string(rawJSON)
+	}
+	profile.Environment = label
+	return profile.Markdown()
+}
+
 func runProfileForEnv(clusterName, output string, save, force bool, profileFn clusterProfileFn) error {
 	// Freshness check
 	if save && !force && clusterName != "" {
-		mdPath := filepath.Join(resolveProfileDir(), clusterName+".md")
-		if fi, err := os.Stat(mdPath); err == nil {
-			age := time.Since(fi.ModTime())
-			if age < profileFreshnessThreshold {
-				fmt.Printf("  Skipping %q: profile written %s ago (use --force to override)\n",
-					clusterName, age.Truncate(time.Minute))
-				return nil
-			}
+		if age, ok := profileIsFresh(clusterName); ok {
+			fmt.Printf("  Skipping %q: profile written %s ago (use --force to override)\n",
+				clusterName, age.Truncate(time.Minute))
+			return nil
 		}
 	}
 
@@ -134,7 +440,7 @@ func runProfileForEnv(clusterName, output string, save, force bool, profileFn cl
 		label = "default"
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultProfileTimeout)
 	defer cancel()
 
 	rawJSON, err := profileFn(ctx, namespace)
@@ -142,22 +448,7 @@ func runProfileForEnv(clusterName, output string, save, force bool, profileFn cl
 		return fmt.Errorf("profiling cluster: %w", err)
 	}
 
-	// Render output
-	var rendered string
-	switch output {
-	case "json":
-		rendered = string(rawJSON)
-	default:
-		// Try to unmarshal into ClusterProfile for rich markdown rendering
-		var profile k8s.ClusterProfile
-		if jsonErr := json.Unmarshal(rawJSON, &profile); jsonErr == nil {
-			profile.Environment = label
-			rendered = profile.Markdown()
-		} else {
-			// Fall back to raw JSON if schema doesn't match
-			rendered = string(rawJSON)
-		}
-	}
+	rendered := renderProfile(rawJSON, label, output)
 
 	if save {
 		dir := resolveProfileDir()
@@ -172,7 +463,8 @@ func runProfileForEnv(clusterName, output string, save, force bool, profileFn cl
 	return nil
 }
 
-// saveProfileRaw writes both markdown and JSON representations to dir.
+// saveProfileRaw writes both markdown and JSON representations to dir, plus a
+// timestamped JSON snapshot under history/<env>/ for drift reporting.
 func saveProfileRaw(rawJSON []byte, markdown, clusterName, dir string) error {
 	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec // non-sensitive directory
 		return fmt.Errorf("creating profile directory: %w", err)
@@ -188,7 +480,7 @@ func saveProfileRaw(rawJSON []byte, markdown, clusterName, dir string) error {
 		return fmt.Errorf("writing JSON profile: %w", err)
 	}
 
-	return nil
+	return saveProfileSnapshot(rawJSON, clusterName, dir)
 }
 
 // autoProfileTimeout is the maximum time allowed to profile a single environment
@@ -196,39 +488,21 @@ func saveProfileRaw(rawJSON []byte, markdown, clusterName, dir string) error {
 const autoProfileTimeout = 45 * time.Second
 
 // AutoProfileEnvironments generates profiles for all reachable environments.
-// Called from configure after writing config. Each environment is given a
-// 45s deadline; unreachable or slow clusters emit a warning and are skipped.
-// Environments without an MCP endpoint configured are silently skipped.
+// Called from configure after writing config. Environments are profiled
+// concurrently, each with a 45s deadline; unreachable or slow clusters emit a
+// warning and are skipped. Environments without an MCP endpoint are skipped.
 func AutoProfileEnvironments() {
 	cfg := LoadConfig()
 	if len(cfg.Clusters) == 0 {
 		return
 	}
-	for clusterName := range cfg.Clusters {
-		fmt.Printf("Profiling environment %q... ", clusterName)
-
-		profileFn, err := buildProfileFnForEnv(clusterName, cfg)
-		if err != nil || profileFn == nil {
-			fmt.Printf("\u26a0 skipped (MCP not configured for env %q)\n", clusterName)
-			continue
-		}
-
-		done := make(chan error, 1)
-		fn := profileFn
-		name := clusterName
-		go func() {
-			done <- runProfileForEnv(name, "markdown", true, false, fn)
-		}()
-
-		select {
-		case err := <-done:
-			if err != nil {
-				fmt.Printf("\u26a0 skipped (%s)\n", err)
-			}
-		case <-time.After(autoProfileTimeout):
-			fmt.Printf("\u26a0 skipped (timed out after %s)\n", autoProfileTimeout)
-		}
+	opts := profileAllOptions{
+		Output:      "markdown",
+		Save:        true,
+		Concurrency: defaultProfileConcurrency,
+		Timeout:     autoProfileTimeout,
 	}
+	_ = runProfileAll(cfg, mcpEnvProfileFn(cfg), opts, os.Stdout, os.Stdout)
 }
 
 // buildProfileFnForEnv builds a clusterProfileFn for the named environment.
@@ -250,7 +524,7 @@ func buildProfileFnForEnv(clusterName string, cfg TentacularConfig) (clusterProf
 		return nil, fmt.Errorf("resolving OIDC token for env %q: %w", clusterName, err)
 	}
 
-	mcpClient := mcp.NewClient(mcp.Config{Endpoint: endpoint, Token: token})
+	mcpClient := newMCPClient(cfg, mcp.Config{Endpoint: endpoint, Token: token})
 	return func(ctx context.Context, namespace string) ([]byte, error) {
 		result, err := mcpClient.ClusterProfile(ctx, namespace)
 		if err != nil {
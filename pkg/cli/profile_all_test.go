@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+)
+
+func fakeProfileJSON(version string, gvisor bool) []byte {
+	data, _ := json.Marshal(k8s.ClusterProfile{
+		K8sVersion: version,
+		GVisor:     gvisor,
+		CNI:        k8s.CNIInfo{Name: "calico"},
+		Quota:      &k8s.QuotaSummary{CPULimit: "8", MemoryLimit: "16Gi"},
+	})
+	return data
+}
+
+func profileTestConfig(envs ...string) TentacularConfig {
+	cfg := TentacularConfig{Clusters: map[string]EnvironmentConfig{}}
+	for _, e := range envs {
+		cfg.Clusters[e] = EnvironmentConfig{Namespace: e + "-ns"}
+	}
+	return cfg
+}
+
+func TestProfileEnvironments_BoundedConcurrency(t *testing.T) {
+	envs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var inFlight, maxInFlight int32
+	fn := func(ctx context.Context, env string) ([]byte, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return fakeProfileJSON("v1.31.0", true), nil
+	}
+
+	var progress bytes.Buffer
+	results := profileEnvironments(context.Background(), envs, 3, time.Second, fn, &progress)
+
+	if maxInFlight > 3 {
+		t.Errorf("expected at most 3 concurrent calls, saw %d", maxInFlight)
+	}
+	if len(results) != len(envs) {
+		t.Fatalf("expected %d results, got %d", len(envs), len(results))
+	}
+	for i, r := range results {
+		if r.Env != envs[i] {
+			t.Errorf("result %d: expected env %q, got %q", i, envs[i], r.Env)
+		}
+		if r.Err != nil || r.Profile == nil || r.Profile.K8sVersion != "v1.31.0" {
+			t.Errorf("result %d: unexpected %+v", i, r)
+		}
+	}
+	if got := strings.Count(progress.String(), "\u2713"); got != len(envs) {
+		t.Errorf("expected %d progress lines, got %d:\n%s", len(envs), got, progress.String())
+	}
+}
+
+func TestProfileEnvironments_TimeoutAndSkip(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]bool{}
+	fn := func(ctx context.Context, env string) ([]byte, error) {
+		mu.Lock()
+		calls[env] = true
+		mu.Unlock()
+		switch env {
+		case "slow":
+			time.Sleep(time.Second) // ignores ctx on purpose
+			return nil, nil
+		case "nomcp":
+			return nil, errNoMCPEndpoint
+		}
+		return fakeProfileJSON("v1.30.0", false), nil
+	}
+
+	var progress bytes.Buffer
+	results := profileEnvironments(context.Background(), []string{"fast", "nomcp", "slow"}, 3, 50*time.Millisecond, fn, &progress)
+
+	if results[0].Err != nil {
+		t.Errorf("fast: unexpected error %v", results[0].Err)
+	}
+	if !results[1].Skipped {
+		t.Errorf("nomcp: expected skipped, got %+v", results[1])
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "timed out") {
+		t.Errorf("slow: expected timeout error, got %v", results[2].Err)
+	}
+}
+
+func TestRunProfileAll_PartialFailureSummary(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := profileTestConfig("dev", "prod", "staging")
+	fn := func(ctx context.Context, env string) ([]byte, error) {
+		if env == "staging" {
+			return nil, errors.New("connection refused")
+		}
+		return fakeProfileJSON("v1.31.0", env == "prod"), nil
+	}
+
+	var out, progress bytes.Buffer
+	opts := profileAllOptions{Output: "json", Concurrency: 2, Timeout: time.Second}
+	if err := runProfileAll(cfg, fn, opts, &out, &progress); err != nil {
+		t.Fatalf("partial failure should not abort: %v", err)
+	}
+
+	var doc map[string]struct {
+		Profile *k8s.ClusterProfile `json:"profile"`
+		Error   string              `json:"error"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not a single JSON document: %v\n%s", err, out.String())
+	}
+	if len(doc) != 3 || doc["prod"].Profile == nil || !doc["prod"].Profile.GVisor || doc["dev"].Profile == nil {
+		t.Errorf("expected profiles keyed by environment, got %+v", doc)
+	}
+	if doc["staging"].Profile != nil || !strings.Contains(doc["staging"].Error, "connection refused") {
+		t.Errorf("expected staging to carry its error, got %+v", doc["staging"])
+	}
+
+	summary := progress.String()
+	for _, want := range []string{"ENVIRONMENT", "dev", "prod", "yes", "calico", "cpu 8 / mem 16Gi", "failed: connection refused"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestRunProfileAll_AllFail(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fn := func(ctx context.Context, env string) ([]byte, error) {
+		return nil, fmt.Errorf("boom %s", env)
+	}
+	var out, progress bytes.Buffer
+	err := runProfileAll(profileTestConfig("a", "b"), fn, profileAllOptions{Concurrency: 2}, &out, &progress)
+	if err == nil || !strings.Contains(err.Error(), "all environments failed") {
+		t.Fatalf("expected all-failed error, got %v", err)
+	}
+}
+
+func TestRunProfileAll_SaveSkipsFresh(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".tentacular", "envprofiles")
+	_ = os.MkdirAll(dir, 0o755)
+	_ = os.WriteFile(filepath.Join(dir, "dev.md"), []byte("# fresh"), 0o644)
+
+	var called []string
+	var mu sync.Mutex
+	fn := func(ctx context.Context, env string) ([]byte, error) {
+		mu.Lock()
+		called = append(called, env)
+		mu.Unlock()
+		return fakeProfileJSON("v1.31.0", true), nil
+	}
+
+	var out, progress bytes.Buffer
+	opts := profileAllOptions{Save: true, Concurrency: 2, Timeout: time.Second}
+	if err := runProfileAll(profileTestConfig("dev", "prod"), fn, opts, &out, &progress); err != nil {
+		t.Fatalf("runProfileAll: %v", err)
+	}
+	if len(called) != 1 || called[0] != "prod" {
+		t.Errorf("expected only prod profiled, got %v", called)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "prod.json")); err != nil {
+		t.Errorf("expected prod.json saved: %v", err)
+	}
+	if !strings.Contains(out.String(), "skipped (fresh)") {
+		t.Errorf("expected fresh skip in summary:\n%s", out.String())
+	}
+}
+
+func TestProfileNamespace(t *testing.T) {
+	cfg := profileTestConfig("prod")
+	cfg.Namespace = "global"
+	if got := profileNamespace(cfg, "prod"); got != "prod-ns" {
+		t.Errorf("expected prod-ns, got %q", got)
+	}
+	if got := profileNamespace(cfg, "other"); got != "global" {
+		t.Errorf("expected global, got %q", got)
+	}
+	if got := profileNamespace(TentacularConfig{}, ""); got != "default" {
+		t.Errorf("expected default, got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// profileStaleThreshold is the age after which a saved cluster profile is
+// reported as stale by the compatibility check.
+const profileStaleThreshold = 7 * 24 * time.Hour
+
+// activeClusterName resolves the target environment name using the cascade:
+// --cluster flag > TENTACULAR_CLUSTER env var > default_cluster config.
+func activeClusterName(cmd *cobra.Command, cfg TentacularConfig) string {
+	clusterName := flagString(cmd, "cluster")
+	if clusterName == "" {
+		clusterName = os.Getenv("TENTACULAR_CLUSTER")
+	}
+	if clusterName == "" {
+		clusterName = cfg.DefaultCluster
+	}
+	return clusterName
+}
+
+// loadSavedProfile reads the saved JSON profile for an environment from the
+// envprofiles directory. Returns (nil, nil) when no profile has been saved.
+func loadSavedProfile(clusterName string) (*k8s.ClusterProfile, error) {
+	label := clusterName
+	if label == "" {
+		label = "default"
+	}
+	path := filepath.Join(resolveProfileDir(), label+".json")
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from the profile directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading cluster profile: %w", err)
+	}
+	var profile k8s.ClusterProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("parsing cluster profile %s: %w", path, err)
+	}
+	if profile.Environment == "" {
+		profile.Environment = label
+	}
+	return &profile, nil
+}
+
+// resolveRuntimeClassForEnv is the runtime-class cascade shared by deploy and
+// the offline preflights, before any --runtime-class override: config of the
+// environment clusterName (whichever way it was selected) > global config > "gvisor".
+func resolveRuntimeClassForEnv(cfg TentacularConfig, clusterName string) string {
+	if clusterName != "" {
+		if env, ok := cfg.Clusters[clusterName]; ok {
+			return env.RuntimeClass
+		}
+	}
+	if cfg.RuntimeClass != "" {
+		return cfg.RuntimeClass
+	}
+	return "gvisor"
+}
+
+// checkProfileCompatibility loads the saved profile for clusterName and checks
+// the workflow against it. Returns (nil, nil) when no profile is saved.
+func checkProfileCompatibility(clusterName string, wf *spec.Workflow, runtimeClass, secretsSource string) ([]k8s.CompatFinding, error) {
+	profile, err := loadSavedProfile(clusterName)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	return k8s.CheckCompatibility(profile, k8s.CompatInput{
+		Workflow:      wf,
+		RuntimeClass:  runtimeClass,
+		SecretsSource: secretsSource,
+		StaleAfter:    profileStaleThreshold,
+	}), nil
+}
+
+// clusterHasKEDA reports whether the saved profile of clusterName detected
+// KEDA. Without a readable saved profile KEDA is assumed absent.
+func clusterHasKEDA(clusterName string) bool {
+	profile, err := loadSavedProfile(clusterName)
+	return err == nil && profile != nil && profile.Extensions.KEDA
+}
+
+// printCompatFindings writes findings to w, one per line, prefixed by severity.
+func printCompatFindings(w io.Writer, findings []k8s.CompatFinding) {
+	for _, f := range findings {
+		icon := "⚠"
+		if f.Severity == k8s.CompatError {
+			icon = "✗"
+		}
+		_, _ = fmt.Fprintf(w, "  %s [%s] %s\n", icon, f.Check, f.Message)
+	}
+}
@@ -0,0 +1,292 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/builder"
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+// writeSavedProfile writes a profile JSON file into the envprofiles directory under HOME.
+func writeSavedProfile(t *testing.T, home, env string, p k8s.ClusterProfile) {
+	t.Helper()
+	dir := filepath.Join(home, ".tentacular", "envprofiles")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(p)
+	if err := os.WriteFile(filepath.Join(dir, env+".json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSavedProfile_Missing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p, err := loadSavedProfile("prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != nil {
+		t.Errorf("expected nil profile when none saved, got %+v", p)
+	}
+}
+
+func TestLoadSavedProfile_DefaultLabel(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeSavedProfile(t, home, "default", k8s.ClusterProfile{K8sVersion: "v1.31.0"})
+
+	p, err := loadSavedProfile("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p == nil || p.K8sVersion != "v1.31.0" {
+		t.Fatalf("expected saved default profile, got %+v", p)
+	}
+	if p.Environment != "default" {
+		t.Errorf("expected environment label filled in, got %q", p.Environment)
+	}
+}
+
+func TestResolveRuntimeClassForEnv(t *testing.T) {
+	cfg := TentacularConfig{
+		RuntimeClass: "kata",
+		Clusters: map[string]EnvironmentConfig{
+			"kind": {},
+			"prod": {RuntimeClass: "gvisor"},
+		},
+	}
+	cases := map[string]string{"kind": "", "prod": "gvisor", "": "kata"}
+	for env, want := range cases {
+		if got := resolveRuntimeClassForEnv(cfg, env); got != want {
+			t.Errorf("env %q: expected %q, got %q", env, want, got)
+		}
+	}
+	if got := resolveRuntimeClassForEnv(TentacularConfig{}, ""); got != "gvisor" {
+		t.Errorf("expected gvisor default, got %q", got)
+	}
+}
+
+// TestResolveDeployDefaults_DefaultCluster verifies that deploy without
+// --cluster takes the runtime class and image of the default cluster, the same
+// runtime class the validate preflight checks.
+func TestResolveDeployDefaults_DefaultCluster(t *testing.T) {
+	t.Setenv("TENTACULAR_CLUSTER", "")
+	cfg := TentacularConfig{
+		RuntimeClass:   "kata",
+		DefaultCluster: "kind",
+		Clusters: map[string]EnvironmentConfig{
+			"kind": {Image: "engine:kind"},
+		},
+	}
+	cmd := withRootFlags(NewDeployCmd())
+	runtimeClass, image, err := resolveDeployDefaults(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtimeClass != "" || image != "engine:kind" {
+		t.Errorf("expected the kind environment defaults, got runtime class %q and image %q", runtimeClass, image)
+	}
+	if want := resolveRuntimeClassForEnv(cfg, activeClusterName(cmd, cfg)); runtimeClass != want {
+		t.Errorf("deploy resolved %q but the preflight checks %q", runtimeClass, want)
+	}
+
+	_ = cmd.Flags().Set("runtime-class", "gvisor")
+	if runtimeClass, _, _ = resolveDeployDefaults(cmd, cfg); runtimeClass != "gvisor" {
+		t.Errorf("expected --runtime-class to override, got %q", runtimeClass)
+	}
+	_ = cmd.PersistentFlags().Set("cluster", "missing")
+	if _, _, err := resolveDeployDefaults(cmd, cfg); err == nil {
+		t.Error("expected an unknown --cluster to be an error")
+	}
+}
+
+// TestRunValidate_ProfileIncompatible verifies that validate fails offline when the
+// saved profile for the active environment lacks gVisor.
+func TestRunValidate_ProfileIncompatible(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TENTACULAR_CLUSTER", "")
+	writeSavedProfile(t, home, "default", k8s.ClusterProfile{GeneratedAt: time.Now(), GVisor: false})
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(minimalWorkflowYAML), 0o644)
+
+	cmd := NewValidateCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{dir})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "incompatible") {
+		t.Fatalf("expected incompatibility error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "[runtime-class]") {
+		t.Errorf("expected runtime-class finding in output, got:\n%s", buf.String())
+	}
+}
+
+// TestRunValidate_ProfileJSONFindings verifies findings are included in -o json output.
+func TestRunValidate_ProfileJSONFindings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TENTACULAR_CLUSTER", "")
+	writeSavedProfile(t, home, "default", k8s.ClusterProfile{
+		GeneratedAt: time.Now().Add(-30 * 24 * time.Hour),
+		GVisor:      true,
+	})
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(minimalWorkflowYAML), 0o644)
+
+	cmd := NewValidateCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{dir, "-o", "json"})
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("stale profile should only warn, got: %v", err)
+	}
+	var result ValidateResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\nOutput: %s", err, buf.String())
+	}
+	if len(result.Compatibility) != 1 || result.Compatibility[0].Check != "profile-age" {
+		t.Errorf("expected a single profile-age finding, got %+v", result.Compatibility)
+	}
+}
+
+func TestDeploy_AutoscalingScaledObjectWhenKEDADetected(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	t.Cleanup(setupMCPEnv(t, srv.URL()))
+	home, _ := os.UserHomeDir()
+	wfYAML := `name: queue-workflow
+version: "1.0"
+triggers:
+  - type: queue
+    subject: jobs.new
+nodes:
+  handler:
+    path: ./handler.ts
+    description: "Test node"
+deployment:
+  autoscaling:
+    minReplicas: 0
+    maxReplicas: 3
+    jetstream:
+      stream: JOBS
+      monitoringEndpoint: nats.exo:8222
+`
+	for name, content := range map[string]string{"workflow.yaml": wfYAML, "handler.ts": "export default async () => ({})\n"} {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := func(kind string) map[string]any {
+		wf, _ := srv.Workflow("default", "queue-workflow")
+		for _, m := range wf.Manifests {
+			if m["kind"] == kind {
+				return m
+			}
+		}
+		return nil
+	}
+	// jetStreamStream is the stream the engine is told to consume from.
+	jetStreamStream := func() any {
+		spec, _ := manifest("Deployment")["spec"].(map[string]any)
+		tmpl, _ := spec["template"].(map[string]any)
+		podSpec, _ := tmpl["spec"].(map[string]any)
+		containers, _ := podSpec["containers"].([]any)
+		engine, _ := containers[0].(map[string]any)
+		env, _ := engine["env"].([]any)
+		for _, e := range env {
+			if v, _ := e.(map[string]any); v["name"] == builder.JetStreamStreamEnv {
+				return v["value"]
+			}
+		}
+		return nil
+	}
+
+	// Without a saved profile KEDA is assumed absent.
+	captureStdout(t, func() {
+		if err := runDeployCmd(t); err != nil {
+			t.Fatalf("deploy: %v", err)
+		}
+	})
+	if manifest("ScaledObject") != nil {
+		t.Error("expected no ScaledObject without KEDA")
+	}
+	if spec, _ := manifest("Deployment")["spec"].(map[string]any); spec["replicas"] != float64(1) {
+		t.Errorf("expected a single replica without KEDA, got %v", spec["replicas"])
+	}
+	if stream := jetStreamStream(); stream != nil {
+		t.Errorf("expected the engine on core NATS without a ScaledObject, got stream %v", stream)
+	}
+
+	writeSavedProfile(t, home, "default", k8s.ClusterProfile{Extensions: k8s.ExtensionSet{KEDA: true}})
+	out := captureStdout(t, func() {
+		if err := runDeployCmd(t); err != nil {
+			t.Fatalf("deploy: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Autoscaling: KEDA ScaledObject, 0-3 replicas") {
+		t.Errorf("expected the autoscaling reported:\n%s", out)
+	}
+	so := manifest("ScaledObject")
+	if so == nil {
+		t.Fatal("expected a ScaledObject with KEDA detected")
+	}
+	if spec, _ := so["spec"].(map[string]any); spec["minReplicaCount"] != float64(0) || spec["maxReplicaCount"] != float64(3) {
+		t.Errorf("unexpected ScaledObject spec %v", spec)
+	}
+	if spec, _ := manifest("Deployment")["spec"].(map[string]any); spec["replicas"] != nil {
+		t.Errorf("expected replicas left to the ScaledObject, got %v", spec["replicas"])
+	}
+	if stream := jetStreamStream(); stream != "JOBS" {
+		t.Errorf("expected the engine pointed at the JOBS stream, got %v", stream)
+	}
+}
+
+// TestDeploy_ProfileIncompatibleBeforeGitStatePush verifies that a workflow the
+// saved profile rejects is refused before git-state pushes anything.
+func TestDeploy_ProfileIncompatibleBeforeGitStatePush(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	t.Cleanup(setupMCPEnv(t, srv.URL()))
+	home, _ := os.UserHomeDir()
+	writeSavedProfile(t, home, "default", k8s.ClusterProfile{GeneratedAt: time.Now(), GVisor: false})
+
+	repo := setupBareAndClone(t)
+	addCommit(t, repo, "change.txt", "unpushed\n", "local change")
+	cfgYAML := "git_state:\n  enabled: true\n  repo_path: " + repo + "\n"
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte(cfgYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile("workflow.yaml", []byte(minimalWorkflowYAML), 0o644)
+
+	cmd := withRootFlags(NewDeployCmd())
+	cmd.SetArgs([]string{".", "--force", "--runtime-class", "gvisor", "--enclave", "team-a"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "incompatible") {
+		t.Fatalf("expected incompatibility error, got %v", err)
+	}
+
+	local, _ := getCurrentGitSHA(repo)
+	remote, _ := exec.Command("git", "-C", repo, "rev-parse", "@{u}").Output() //nolint:gosec // test helper
+	if strings.TrimSpace(string(remote)) == local {
+		t.Error("expected nothing pushed to the git-state remote")
+	}
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+)
+
+// profileSnapshotLayout is the timestamp format used for versioned profile
+// snapshot filenames (sortable, filesystem-safe UTC).
+const profileSnapshotLayout = "20060102T150405Z"
+
+// profileSnapshot is a single versioned profile on disk.
+type profileSnapshot struct {
+	Time time.Time
+	Path string
+}
+
+// profileHistoryDir returns the directory holding versioned snapshots for an environment:
+// <envprofiles>/history/<env>/.
+func profileHistoryDir(dir, clusterName string) string {
+	return filepath.Join(dir, "history", clusterName)
+}
+
+// saveProfileSnapshot writes a timestamped copy of rawJSON to the environment's
+// history directory. The timestamp is taken from the profile's generatedAt field,
+// falling back to the current time.
+func saveProfileSnapshot(rawJSON []byte, clusterName, dir string) error {
+	at := time.Now().UTC()
+	var stub struct {
+		GeneratedAt time.Time `json:"generatedAt"`
+	}
+	if json.Unmarshal(rawJSON, &stub) == nil && !stub.GeneratedAt.IsZero() {
+		at = stub.GeneratedAt.UTC()
+	}
+
+	histDir := profileHistoryDir(dir, clusterName)
+	if err := os.MkdirAll(histDir, 0o755); err != nil { //nolint:gosec // non-sensitive directory
+		return fmt.Errorf("creating profile history directory: %w", err)
+	}
+	path := filepath.Join(histDir, at.Format(profileSnapshotLayout)+".json")
+	if err := os.WriteFile(path, rawJSON, 0o644); err != nil { //nolint:gosec // non-sensitive profile file
+		return fmt.Errorf("writing profile snapshot: %w", err)
+	}
+	return nil
+}
+
+// listProfileSnapshots returns the versioned snapshots for an environment, oldest first.
+// Files whose names do not match the snapshot layout are ignored.
+func listProfileSnapshots(dir, clusterName string) ([]profileSnapshot, error) {
+	histDir := profileHistoryDir(dir, clusterName)
+	entries, err := os.ReadDir(histDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading profile history: %w", err)
+	}
+	var snaps []profileSnapshot
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		at, parseErr := time.Parse(profileSnapshotLayout, strings.TrimSuffix(name, ".json"))
+		if parseErr != nil {
+			continue
+		}
+		snaps = append(snaps, profileSnapshot{Time: at, Path: filepath.Join(histDir, name)})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Time.Before(snaps[j].Time) })
+	return snaps, nil
+}
+
+func readProfileSnapshot(path string) (*k8s.ClusterProfile, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from the profile history directory
+	if err != nil {
+		return nil, fmt.Errorf("reading profile snapshot: %w", err)
+	}
+	var p k8s.ClusterProfile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing profile snapshot %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// ProfileDiffStep is the drift between two consecutive profile snapshots.
+type ProfileDiffStep struct {
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	Changes []k8s.ProfileChange `json:"changes"`
+}
+
+// ProfileDiffResult is the JSON output of `tntc cluster profile diff`.
+type ProfileDiffResult struct {
+	Environment string            `json:"environment"`
+	Steps       []ProfileDiffStep `json:"steps"`
+}
+
+// NewProfileDiffCmd creates the "cluster profile diff" subcommand.
+func NewProfileDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <env>",
+		Short: "Show capability drift between saved profile snapshots",
+		Long: `Compare versioned cluster profile snapshots saved by 'tntc cluster profile --save'.
+
+Without --since, the two most recent snapshots are compared. With --since, every
+change between consecutive snapshots from that date onward is reported, starting
+from the last snapshot taken at or before the date.
+
+Reported fields: K8s version, CNI, runtime classes (including gVisor), storage
+classes and the default class, extensions, quota and limit ranges.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runProfileDiff,
+	}
+	cmd.Flags().String("since", "", "Report drift since this date (YYYY-MM-DD or RFC3339)")
+	return cmd
+}
+
+func runProfileDiff(cmd *cobra.Command, args []string) error {
+	env := args[0]
+	sinceStr, _ := cmd.Flags().GetString("since")
+
+	var since time.Time
+	if sinceStr != "" {
+		var err error
+		since, err = parseSinceDate(sinceStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err := buildProfileDiff(resolveProfileDir(), env, since)
+	if err != nil {
+		return err
+	}
+
+	if flagString(cmd, "output") == "json" {
+		data, jsonErr := json.Marshal(result)
+		if jsonErr != nil {
+			return fmt.Errorf("marshaling JSON: %w", jsonErr)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	writeProfileDiffText(cmd.OutOrStdout(), result)
+	return nil
+}
+
+// parseSinceDate accepts a bare date (YYYY-MM-DD, interpreted as UTC midnight) or RFC3339.
+func parseSinceDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: expected YYYY-MM-DD or RFC3339", s)
+}
+
+// buildProfileDiff computes drift steps for env from the snapshots in dir.
+// A zero since compares only the two most recent snapshots.
+func buildProfileDiff(dir, env string, since time.Time) (*ProfileDiffResult, error) {
+	snaps, err := listProfileSnapshots(dir, env)
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) < 2 {
+		return nil, fmt.Errorf("need at least 2 saved snapshots for %q to diff (found %d); run 'tntc cluster profile --save --force' after cluster changes", env, len(snaps))
+	}
+
+	start := len(snaps) - 2
+	if !since.IsZero() {
+		start = 0
+		for i, s := range snaps {
+			if !s.Time.After(since) {
+				start = i
+			}
+		}
+		if start == len(snaps)-1 {
+			// Latest snapshot predates --since: nothing changed in the window.
+			return &ProfileDiffResult{Environment: env, Steps: []ProfileDiffStep{}}, nil
+		}
+	}
+
+	result := &ProfileDiffResult{Environment: env, Steps: []ProfileDiffStep{}}
+	prev, err := readProfileSnapshot(snaps[start].Path)
+	if err != nil {
+		return nil, err
+	}
+	for i := start + 1; i < len(snaps); i++ {
+		next, readErr := readProfileSnapshot(snaps[i].Path)
+		if readErr != nil {
+			return nil, readErr
+		}
+		changes := k8s.DiffProfiles(prev, next)
+		if changes == nil {
+			changes = []k8s.ProfileChange{}
+		}
+		result.Steps = append(result.Steps, ProfileDiffStep{
+			From:    snaps[i-1].Time,
+			To:      snaps[i].Time,
+			Changes: changes,
+		})
+		prev = next
+	}
+	return result, nil
+}
+
+func writeProfileDiffText(w io.Writer, result *ProfileDiffResult) {
+	total := 0
+	for _, step := range result.Steps {
+		if len(step.Changes) == 0 {
+			continue
+		}
+		total += len(step.Changes)
+		_, _ = fmt.Fprintf(w, "%s → %s\n", step.From.Format(time.RFC3339), step.To.Format(time.RFC3339))
+		for _, c := range step.Changes {
+			_, _ = fmt.Fprintf(w, "  %s\n", c)
+		}
+	}
+	if total == 0 {
+		_, _ = fmt.Fprintf(w, "No capability drift for %q\n", result.Environment)
+	}
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+)
+
+func saveTestSnapshot(t *testing.T, dir, env string, p k8s.ClusterProfile) {
+	t.Helper()
+	data, _ := json.Marshal(p)
+	if err := saveProfileSnapshot(data, env, dir); err != nil {
+		t.Fatalf("saveProfileSnapshot: %v", err)
+	}
+}
+
+func TestListProfileSnapshots_Sorted(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base.Add(48 * time.Hour)})
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base})
+
+	snaps, err := listProfileSnapshots(dir, "prod")
+	if err != nil {
+		t.Fatalf("listProfileSnapshots: %v", err)
+	}
+	if len(snaps) != 2 || !snaps[0].Time.Equal(base) {
+		t.Fatalf("expected 2 snapshots oldest first, got %+v", snaps)
+	}
+}
+
+func TestBuildProfileDiff_LatestTwo(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base, K8sVersion: "v1.29.0"})
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base.Add(24 * time.Hour), K8sVersion: "v1.30.0", GVisor: true})
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base.Add(48 * time.Hour), K8sVersion: "v1.31.0"})
+
+	result, err := buildProfileDiff(dir, "prod", time.Time{})
+	if err != nil {
+		t.Fatalf("buildProfileDiff: %v", err)
+	}
+	if len(result.Steps) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(result.Steps))
+	}
+
+	var buf bytes.Buffer
+	writeProfileDiffText(&buf, result)
+	out := buf.String()
+	if !strings.Contains(out, "v1.30.0 → v1.31.0") || !strings.Contains(out, "gvisor: true → false") {
+		t.Errorf("unexpected diff output:\n%s", out)
+	}
+}
+
+func TestBuildProfileDiff_Since(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base, K8sVersion: "v1.29.0"})
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base.Add(24 * time.Hour), K8sVersion: "v1.30.0"})
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: base.Add(48 * time.Hour), K8sVersion: "v1.31.0"})
+
+	since, _ := parseSinceDate("2026-09-01")
+	result, err := buildProfileDiff(dir, "prod", since)
+	if err != nil {
+		t.Fatalf("buildProfileDiff: %v", err)
+	}
+	if len(result.Steps) != 2 {
+		t.Fatalf("expected 2 steps since 2026-09-01, got %d", len(result.Steps))
+	}
+
+	since, _ = parseSinceDate("2026-10-01")
+	result, err = buildProfileDiff(dir, "prod", since)
+	if err != nil {
+		t.Fatalf("buildProfileDiff: %v", err)
+	}
+	if len(result.Steps) != 0 {
+		t.Errorf("expected no steps after latest snapshot, got %d", len(result.Steps))
+	}
+}
+
+func TestBuildProfileDiff_NotEnoughSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	saveTestSnapshot(t, dir, "prod", k8s.ClusterProfile{GeneratedAt: time.Now()})
+	if _, err := buildProfileDiff(dir, "prod", time.Time{}); err == nil {
+		t.Fatal("expected error with a single snapshot")
+	}
+}
+
+func TestParseSinceDate_Invalid(t *testing.T) {
+	if _, err := parseSinceDate("last tuesday"); err == nil {
+		t.Fatal("expected error for invalid date")
+	}
+}
@@ -0,0 +1,491 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+func NewPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <name>",
+		Short: "Promote a deployed workflow from one environment to another",
+		Long: `Promote the revision of a workflow live in the --from environment to the
+--to environment. The engine image and code ConfigMap running in the source
+cluster are re-rendered with the target environment's namespace, runtime
+class and config_overrides; secrets are re-resolved from the workflow's
+.secrets.yaml for the target namespace. When the source cluster cannot be
+read, the image and code recorded by the source deploy are promoted instead.
+
+Gates run before anything is applied:
+  - the source workflow must not be red in tntc health (skip with --force)
+  - the target cluster profile check (violations warn with --warn)
+  - --live-test runs the workflow once in the source environment
+  - --require-clean-git refuses uncommitted changes in the workflow directory
+
+The target workflow's deploy lock is held while it is applied, like tntc
+deploy. The promotion is recorded in the target's revision history (tntc
+history).`,
+		Args: cobra.ExactArgs(1),
+		RunE: runPromote,
+	}
+	cmd.Flags().String("from", "", "Source environment (required)")
+	cmd.Flags().String("to", "", "Target environment (required)")
+	cmd.Flags().Bool("force", false, "Skip the source health gate")
+	cmd.Flags().Bool("live-test", false, "Run the workflow once in the source environment before promoting")
+	cmd.Flags().Bool("require-clean-git", false, "Refuse to promote when the workflow directory has uncommitted changes")
+	cmd.Flags().Bool("warn", false, "Audit mode: cluster profile violations produce warnings instead of failures")
+	cmd.Flags().Bool("verify", false, "Run workflow once in the target environment after promoting")
+	cmd.Flags().Bool("wait", false, "Wait for the target rollout to become ready")
+	cmd.Flags().Duration("wait-timeout", defaultWatchTimeout, "Maximum time to --wait for the rollout")
+	addInputFlag(cmd)
+	addLockFlags(cmd)
+	addResumeFlag(cmd)
+	addDirectFlags(cmd)
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	startedAt := time.Now().UTC()
+	name := args[0]
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	force, _ := cmd.Flags().GetBool("force")
+	liveTest, _ := cmd.Flags().GetBool("live-test")
+	verify, _ := cmd.Flags().GetBool("verify")
+	warnMode, _ := cmd.Flags().GetBool("warn")
+	if from == to {
+		return fmt.Errorf("--from and --to are both %q; promote needs two environments", from)
+	}
+	if isDirect(cmd) && (liveTest || verify) {
+		return errors.New("--live-test and --verify run the workflow via the MCP server and cannot be combined with --direct")
+	}
+
+	cfg := LoadConfig()
+	srcEnv, err := cfg.LoadEnvironment(from)
+	if err != nil {
+		return fmt.Errorf("loading environment %q: %w", from, err)
+	}
+	dstEnv, err := cfg.LoadEnvironment(to)
+	if err != nil {
+		return fmt.Errorf("loading environment %q: %w", to, err)
+	}
+	srcNS := environmentNamespace(cfg, srcEnv)
+	dstNS := environmentNamespace(cfg, dstEnv)
+	w := StatusWriter(cmd)
+
+	srcClient, srcMCP, err := environmentClient(cmd, from, srcEnv)
+	if err != nil {
+		return err
+	}
+	recorded, err := resolveRevisionStore(cmd.Context(), cfg, srcClient).get(from, srcNS, name, 0)
+	if err != nil {
+		return fmt.Errorf("finding the revision of %s live in %s: %w\n  hint: promote uses the revision history; deploy with tntc deploy -c %s first", name, from, err, from)
+	}
+	source, err := liveSourceRevision(cmd.Context(), srcClient, srcNS, recorded, w)
+	if err != nil {
+		return fmt.Errorf("reading %s from %s: %w", name, from, err)
+	}
+	code, err := promotedCodeConfigMap(source, dstNS, dstEnv.ConfigOverrides)
+	if err != nil {
+		return err
+	}
+	codeData, _ := code["data"].(map[string]any)
+	wfYAML, _ := codeData["workflow.yaml"].(string)
+	wf, errs := spec.Parse([]byte(wfYAML))
+	if len(errs) > 0 {
+		return fmt.Errorf("workflow spec of revision %d has %d validation error(s)", source.Revision, len(errs))
+	}
+	_, _ = fmt.Fprintf(w, "Promoting %s revision %d (%s, %s) from %s/%s to %s/%s\n",
+		name, source.Revision, orDash(source.Version), orDash(source.Image), from, srcNS, to, dstNS)
+
+	// Gates: nothing is applied to the target until all of them pass.
+	if requireClean, _ := cmd.Flags().GetBool("require-clean-git"); requireClean {
+		if err := checkWorkflowDirClean(source.WorkflowDir); err != nil {
+			return fmt.Errorf("promotion gate: %w", err)
+		}
+	}
+
+	if !force {
+		if err := checkSourceHealth(cmd.Context(), srcClient, cfg, srcNS, name, w); err != nil {
+			return fmt.Errorf("promotion gate: %w (use --force to skip)", err)
+		}
+	}
+	if liveTest {
+		input, err := loadRunInput(cmd, source.WorkflowDir, name)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Running live test in %s...\n", from)
+		if err := runPromoteCheck(cmd.Context(), srcMCP, srcNS, name, input); err != nil {
+			return fmt.Errorf("promotion gate: live test in %s: %w", from, err)
+		}
+		_, _ = fmt.Fprintln(w, "  Live test passed")
+	}
+
+	runtimeClass := resolveRuntimeClassForEnv(cfg, to)
+	findings, compatErr := checkProfileCompatibility(to, wf, runtimeClass, dstEnv.SecretsSource)
+	if compatErr != nil {
+		_, _ = fmt.Fprintf(w, "WARNING: skipping cluster profile check: %s\n", compatErr)
+	}
+	if len(findings) > 0 {
+		_, _ = fmt.Fprintf(w, "Cluster profile compatibility (%s):\n", to)
+		printCompatFindings(w, findings)
+		if k8s.HasCompatErrors(findings) && !warnMode {
+			return fmt.Errorf("promotion gate: workflow is incompatible with the saved %s cluster profile (use --warn for audit mode)", to)
+		}
+	}
+
+	// Render: the target's manifests built from the promoted spec, with the
+	// source image and code.
+	deployer := resolveDeployerIdentity(to)
+	manifests, err := renderPromotion(source, wf, code, InternalDeployOptions{
+		Namespace:    dstNS,
+		Image:        source.Image,
+		RuntimeClass: runtimeClass,
+		StatusOut:    w,
+		Deployer:     &deployer,
+		KEDA:         clusterHasKEDA(to),
+	})
+	if err != nil {
+		return err
+	}
+
+	dstClient, dstMCP, err := environmentClient(cmd, to, dstEnv)
+	if err != nil {
+		return err
+	}
+	release, err := newDeployLocker(cmd, to).lockCluster(cmd.Context(), dstClient, dstNS, name)
+	defer release()
+	if err != nil {
+		return err
+	}
+	resume, _ := cmd.Flags().GetBool("resume")
+	if err := checkNotSuspended(cmd.Context(), dstClient, dstNS, name, resume, w); err != nil {
+		return fmt.Errorf("promoting to %s: %w", to, err)
+	}
+	applied, err := dstClient.WfApply(cmd.Context(), dstNS, name, manifests)
+	if err != nil {
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("applying to %s: %w\n  hint: %s", to, err, hint)
+		}
+		return fmt.Errorf("applying to %s: %w", to, err)
+	}
+	for _, a := range applied.Applied {
+		_, _ = fmt.Fprintf(w, "  applied %s\n", a)
+	}
+
+	rev := newDeployRevision(&DeployResult{WorkflowName: name, Namespace: dstNS, Manifests: manifests}, to, source.WorkflowDir,
+		GitMeta{SHA: source.GitSHA, Branch: source.GitBranch, Repo: source.GitRepo})
+	rev.PromotedFrom = from + "/" + strconv.Itoa(source.Revision)
+	recordRevision(cmd, dstClient, rev)
+
+	if wait, _ := cmd.Flags().GetBool("wait"); wait {
+		timeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		if _, err := watchRollout(cmd.Context(), dstClient, dstNS, name, watchPollInterval, timeout, w); err != nil {
+			return fmt.Errorf("promotion of %s to %s: %w", name, to, err)
+		}
+	}
+	if verify {
+		input, err := loadRunInput(cmd, source.WorkflowDir, name)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, "Verifying promotion...")
+		if err := runPromoteCheck(cmd.Context(), dstMCP, dstNS, name, input); err != nil {
+			return fmt.Errorf("verification in %s: %w", to, err)
+		}
+		_, _ = fmt.Fprintln(w, "  Verification passed")
+	}
+
+	return EmitResult(cmd, CommandResult{
+		Version: "1",
+		Command: "promote",
+		Status:  "pass",
+		Summary: fmt.Sprintf("promoted %s revision %d from %s to %s (%s)", name, source.Revision, from, to, dstNS),
+		Hints:   []string{},
+		Timing: TimingInfo{
+			StartedAt:  startedAt.Format(time.RFC3339),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		},
+	}, cmd.OutOrStdout())
+}
+
+// environmentNamespace mirrors the namespace cascade for a named
+// environment: env config > global config > "default".
+func environmentNamespace(cfg TentacularConfig, env *EnvironmentConfig) string {
+	if env.Namespace != "" {
+		return env.Namespace
+	}
+	if cfg.Namespace != "" {
+		return cfg.Namespace
+	}
+	return "default"
+}
+
+// environmentClient returns the transport for a named environment: its MCP
+// server, or with --direct its kubeconfig context. The *mcp.Client is nil
+// with --direct.
+func environmentClient(cmd *cobra.Command, envName string, env *EnvironmentConfig) (mcp.WorkflowClient, *mcp.Client, error) {
+	if isDirect(cmd) {
+		client, err := newDirectClient(env.Context)
+		return client, nil, err
+	}
+	client, err := buildMCPClientForEnv(envName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client, nil
+}
+
+// liveSourceRevision returns the recorded source revision with its image and
+// code ConfigMap replaced by what is running in the source cluster, so a
+// change applied there since the recorded deploy is what gets promoted. The
+// recorded revision still supplies the workflow directory and git
+// provenance. When the cluster cannot be read the recorded revision is
+// promoted, with a warning.
+func liveSourceRevision(ctx context.Context, client mcp.WorkflowClient, namespace string, recorded *deployRevision, w io.Writer) (*deployRevision, error) {
+	live, err := liveManifests(ctx, client, namespace, recorded.Workflow)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "WARNING: cannot read the source cluster (%s); promoting recorded revision %d\n", err, recorded.Revision)
+		return recorded, nil
+	}
+	var deployment, code map[string]any
+	for _, m := range live {
+		switch kind, name := manifestKindName(m); {
+		case kind == "Deployment" && name == recorded.Workflow:
+			deployment = m
+		case kind == "ConfigMap" && name == recorded.Workflow+"-code":
+			code = m
+		}
+	}
+	if deployment == nil || code == nil {
+		return nil, fmt.Errorf("%s has no live Deployment and %s-code ConfigMap in %s", recorded.Workflow, recorded.Workflow, namespace)
+	}
+	source := *recorded
+	source.Image, source.Version = deploymentImageAndVersion(deployment)
+	source.Manifests = []map[string]any{liveConfigMap(code)}
+	if source.Image != recorded.Image {
+		_, _ = fmt.Fprintf(w, "WARNING: %s runs %s, not %s recorded by revision %d; promoting the live image\n",
+			recorded.Workflow, orDash(source.Image), orDash(recorded.Image), recorded.Revision)
+	}
+	return &source, nil
+}
+
+// liveConfigMap strips a live ConfigMap down to what is applied: its name,
+// labels, annotations and data. Server-managed metadata (uid,
+// resourceVersion, managedFields, ...) must not be sent to another cluster.
+func liveConfigMap(m map[string]any) map[string]any {
+	meta, _ := m["metadata"].(map[string]any)
+	newMeta := map[string]any{"name": meta["name"]}
+	for _, k := range []string{"labels", "annotations"} {
+		if v, ok := meta[k]; ok {
+			newMeta[k] = v
+		}
+	}
+	return map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   newMeta,
+		"data":       m["data"],
+	}
+}
+
+// checkSourceHealth fails when the source workflow is red, by the server's
+// status or by the tntc health thresholds; amber only warns.
+func checkSourceHealth(ctx context.Context, client mcp.WorkflowClient, cfg TentacularConfig, namespace, name string, w io.Writer) error {
+	result, err := client.WfHealth(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("checking source health: %w", err)
+	}
+	if result.Status == healthRed {
+		return fmt.Errorf("source workflow is red (%s)", orDash(result.Reason))
+	}
+	report := newHealthReport(result)
+	report.classify(defaultHealthThresholds.withConfig(cfg.Health))
+	switch report.Status {
+	case healthRed:
+		return fmt.Errorf("source workflow is red (%s)", strings.Join(report.Reasons, "; "))
+	case healthAmber:
+		_, _ = fmt.Fprintf(w, "WARNING: source workflow is amber (%s)\n", strings.Join(report.Reasons, "; "))
+	}
+	return nil
+}
+
+// runPromoteCheck runs the workflow once and fails unless it succeeds.
+func runPromoteCheck(ctx context.Context, client *mcp.Client, namespace, name string, input json.RawMessage) error {
+	result, err := client.WfRun(ctx, namespace, name, input, 120)
+	if err != nil {
+		return fmt.Errorf("workflow run failed: %w", err)
+	}
+	var execResult map[string]any
+	if json.Unmarshal(result.Output, &execResult) == nil {
+		if success, ok := execResult["success"].(bool); ok && !success {
+			return errors.New("workflow returned success=false")
+		}
+	}
+	return nil
+}
+
+// checkWorkflowDirClean refuses a workflow directory with uncommitted
+// changes, or one that is not in a git repository.
+func checkWorkflowDirClean(dir string) error {
+	if dir == "" {
+		return errors.New("the source revision records no workflow directory to check")
+	}
+	cmd := exec.CommandContext(context.Background(), "git", "-C", dir, "status", "--porcelain", "--", ".") //nolint:gosec // dir is the recorded workflow directory
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("checking git status of %s: %w", dir, err)
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		return fmt.Errorf("%s has uncommitted changes; commit before promoting", dir)
+	}
+	return nil
+}
+
+// promotedCodeConfigMap returns a copy of the source revision's code
+// ConfigMap in namespace, with overrides merged into the config section of
+// its workflow.yaml.
+func promotedCodeConfigMap(rev *deployRevision, namespace string, overrides map[string]any) (map[string]any, error) {
+	for _, m := range rev.Manifests {
+		meta, _ := m["metadata"].(map[string]any)
+		if m["kind"] != "ConfigMap" || meta["name"] != rev.Workflow+"-code" {
+			continue
+		}
+		data, _ := m["data"].(map[string]any)
+		content, _ := data["workflow.yaml"].(string)
+		if content == "" {
+			break
+		}
+		content, err := overrideWorkflowConfig(content, overrides)
+		if err != nil {
+			return nil, fmt.Errorf("applying config_overrides: %w", err)
+		}
+		newData := make(map[string]any, len(data))
+		for k, v := range data {
+			newData[k] = v
+		}
+		newData["workflow.yaml"] = content
+		newMeta := make(map[string]any, len(meta))
+		for k, v := range meta {
+			newMeta[k] = v
+		}
+		newMeta["namespace"] = namespace
+		cm := make(map[string]any, len(m))
+		for k, v := range m {
+			cm[k] = v
+		}
+		cm["metadata"] = newMeta
+		cm["data"] = newData
+		return cm, nil
+	}
+	return nil, fmt.Errorf("revision %d of %s has no %s-code ConfigMap to promote", rev.Revision, rev.Workflow, rev.Workflow)
+}
+
+// overrideWorkflowConfig merges overrides into the config section of a
+// workflow.yaml, keeping the rest of the document as written.
+func overrideWorkflowConfig(content string, overrides map[string]any) (string, error) {
+	if len(overrides) == 0 {
+		return content, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", fmt.Errorf("parsing workflow.yaml: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", errors.New("workflow.yaml is not a mapping")
+	}
+	root := doc.Content[0]
+	config := mappingValue(root, "config")
+	if config == nil || config.Kind != yaml.MappingNode {
+		config = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setMappingValue(root, "config", config)
+	}
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var v yaml.Node
+		if err := v.Encode(overrides[k]); err != nil {
+			return "", fmt.Errorf("encoding override %q: %w", k, err)
+		}
+		setMappingValue(config, k, &v)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("encoding workflow.yaml: %w", err)
+	}
+	_ = enc.Close()
+	return buf.String(), nil
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// renderPromotion builds the target's manifests from the promoted spec and
+// swaps in the source's code ConfigMap. The Secret is re-resolved from the
+// workflow directory for the target namespace by buildManifests.
+func renderPromotion(source *deployRevision, wf *spec.Workflow, code map[string]any, opts InternalDeployOptions) ([]map[string]any, error) {
+	built, err := buildManifests(source.WorkflowDir, wf, opts)
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s for %s: %w", wf.Name, opts.Namespace, err)
+	}
+	manifests := make([]map[string]any, 0, len(built))
+	for _, m := range built {
+		if m.Kind == "ConfigMap" && m.Name == wf.Name+"-code" {
+			manifests = append(manifests, code)
+			continue
+		}
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(m.Content), &obj); err != nil {
+			return nil, fmt.Errorf("serializing manifest %s/%s: %w", m.Kind, m.Name, err)
+		}
+		manifests = append(manifests, obj)
+	}
+	if source.GitSHA != "" {
+		injectGitAnnotations(manifests, GitMeta{SHA: source.GitSHA, Branch: source.GitBranch, Repo: source.GitRepo})
+	}
+	if opts.Deployer != nil {
+		injectDeployerAnnotations(manifests, *opts.Deployer, time.Now())
+	}
+	return manifests, nil
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+const promoteConfigYAML = `clusters:
+  dev:
+    namespace: dev
+  prod:
+    namespace: prod
+    runtime_class: gvisor
+    config_overrides:
+      region: eu
+`
+
+func writePromoteConfig(t *testing.T) {
+	t.Helper()
+	home, _ := os.UserHomeDir()
+	if err := os.WriteFile(filepath.Join(home, ".tentacular", "config.yaml"), []byte(promoteConfigYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromote_ReRendersSourceRevisionForTarget(t *testing.T) {
+	srv := deployRunFixture(t)
+	writePromoteConfig(t)
+	if err := runDeployCmd(t, "-c", "dev", "--image", "engine:1.0"); err != nil {
+		t.Fatalf("deploy to dev: %v", err)
+	}
+	// Local edits after the dev deploy must not reach prod.
+	if err := os.WriteFile("workflow.yaml", []byte(strings.Replace(inputSchemaWorkflowYAML, `version: "1.0"`, `version: "2.0"`, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod"); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+
+	wf, ok := srv.Workflow("prod", "test-workflow")
+	if !ok {
+		t.Fatal("expected workflow in prod")
+	}
+	if wf.Image != "engine:1.0" || wf.Version != "1.0" {
+		t.Errorf("expected the dev image and version, got %s %s", wf.Image, wf.Version)
+	}
+	for _, m := range wf.Manifests {
+		meta, _ := m["metadata"].(map[string]any)
+		if meta["namespace"] != "prod" {
+			t.Errorf("expected %s/%s in prod, got %v", m["kind"], meta["name"], meta["namespace"])
+		}
+		switch {
+		case m["kind"] == "ConfigMap" && meta["name"] == "test-workflow-code":
+			data, _ := m["data"].(map[string]any)
+			code, _ := data["workflow.yaml"].(string)
+			if !strings.Contains(code, `version: "1.0"`) || !strings.Contains(code, "config:\n  region: eu") {
+				t.Errorf("expected dev code with prod overrides, got:\n%s", code)
+			}
+		case m["kind"] == "Deployment":
+			spec, _ := m["spec"].(map[string]any)
+			tmpl, _ := spec["template"].(map[string]any)
+			podSpec, _ := tmpl["spec"].(map[string]any)
+			if podSpec["runtimeClassName"] != "gvisor" {
+				t.Errorf("expected prod runtime class, got %v", podSpec["runtimeClassName"])
+			}
+		}
+	}
+
+	out, _ := runHistoryCmd(t, NewHistoryCmd, "test-workflow", "-c", "prod")
+	if !strings.Contains(out, "promote:dev/1") || !strings.Contains(out, "engine:1.0") {
+		t.Errorf("expected promotion recorded in prod history:\n%s", out)
+	}
+}
+
+func TestPromote_UsesLiveSourceImageAndCode(t *testing.T) {
+	srv := deployRunFixture(t)
+	writePromoteConfig(t)
+	if err := runDeployCmd(t, "-c", "dev", "--image", "engine:1.0"); err != nil {
+		t.Fatalf("deploy to dev: %v", err)
+	}
+	// A hotfix applied to dev outside tntc deploy is what runs there now.
+	dev, _ := srv.Workflow("dev", "test-workflow")
+	var hotfix []map[string]any
+	for _, m := range dev.Manifests {
+		m = normalizeManifest(m)
+		switch kind, name := manifestKindName(m); {
+		case kind == "Deployment":
+			spec, _ := m["spec"].(map[string]any)
+			tmpl, _ := spec["template"].(map[string]any)
+			podSpec, _ := tmpl["spec"].(map[string]any)
+			for _, c := range podSpec["containers"].([]any) {
+				c.(map[string]any)["image"] = "engine:1.0-hotfix"
+			}
+		case kind == "ConfigMap" && name == "test-workflow-code":
+			data, _ := m["data"].(map[string]any)
+			data["workflow.yaml"] = "# hotfix\n" + data["workflow.yaml"].(string)
+		}
+		hotfix = append(hotfix, m)
+	}
+	if _, err := srv.Client().WfApply(t.Context(), "dev", "test-workflow", hotfix); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		_, err = runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod")
+	})
+	if err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	if !strings.Contains(out, "promoting the live image") {
+		t.Errorf("expected a warning that the live image differs from the recorded one:\n%s", out)
+	}
+	wf, _ := srv.Workflow("prod", "test-workflow")
+	if wf.Image != "engine:1.0-hotfix" {
+		t.Errorf("expected the live dev image, got %s", wf.Image)
+	}
+	for _, m := range wf.Manifests {
+		if kind, name := manifestKindName(m); kind == "ConfigMap" && name == "test-workflow-code" {
+			meta, _ := m["metadata"].(map[string]any)
+			data, _ := m["data"].(map[string]any)
+			if code, _ := data["workflow.yaml"].(string); !strings.Contains(code, "# hotfix") || meta["namespace"] != "prod" {
+				t.Errorf("expected the live dev code in prod, got %v:\n%s", meta["namespace"], code)
+			}
+		}
+	}
+
+	// When dev cannot be read, the recorded revision is promoted.
+	srv.Inject("wf_describe", mcptest.Fault{Message: "dev API unavailable"})
+	out = captureStdout(t, func() {
+		_, err = runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod")
+	})
+	if err != nil {
+		t.Fatalf("promote with the source unreadable: %v", err)
+	}
+	if !strings.Contains(out, "promoting recorded revision 1") {
+		t.Errorf("expected the fallback to be reported:\n%s", out)
+	}
+	if wf, _ := srv.Workflow("prod", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected the recorded image, got %s", wf.Image)
+	}
+}
+
+func TestPromote_Gates(t *testing.T) {
+	srv := deployRunFixture(t)
+	writePromoteConfig(t)
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod"); err == nil || !strings.Contains(err.Error(), "tntc deploy -c dev") {
+		t.Errorf("expected missing source revision error, got %v", err)
+	}
+	if err := runDeployCmd(t, "-c", "dev", "--image", "engine:1.0"); err != nil {
+		t.Fatalf("deploy to dev: %v", err)
+	}
+	if err := srv.SetTelemetry("dev", "test-workflow", mcp.TelemetrySnapshot{RecentEvents: telemetryRun(1000, "summarize"), LastRunFailed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod"); err == nil || !strings.Contains(err.Error(), "source workflow is red") {
+		t.Errorf("expected health gate failure, got %v", err)
+	}
+	if _, ok := srv.Workflow("prod", "test-workflow"); ok {
+		t.Error("expected nothing applied to prod when a gate fails")
+	}
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod", "--require-clean-git"); err == nil || !strings.Contains(err.Error(), "checking git status") {
+		t.Errorf("expected clean-git gate to reject a directory outside git, got %v", err)
+	}
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "prod", "--force"); err != nil {
+		t.Errorf("expected --force to skip the health gate: %v", err)
+	}
+	if _, err := runHistoryCmd(t, NewPromoteCmd, "test-workflow", "--from", "dev", "--to", "dev"); err == nil {
+		t.Error("expected error promoting an environment to itself")
+	}
+}
+
+func TestOverrideWorkflowConfig(t *testing.T) {
+	in := "name: wf # the workflow\nconfig:\n  timeout: 30s\n  region: us\nnodes: {}\n"
+	out, err := overrideWorkflowConfig(in, map[string]any{"region": "eu", "retries": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name: wf # the workflow\nconfig:\n  timeout: 30s\n  region: eu\n  retries: 3\nnodes: {}\n"
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if same, _ := overrideWorkflowConfig(in, nil); same != in {
+		t.Error("expected workflow.yaml untouched without overrides")
+	}
+}
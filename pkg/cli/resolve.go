@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -111,11 +112,11 @@ func resolveMCPClient(cmd *cobra.Command) (*mcp.Client, error) {
 		return nil, fmt.Errorf("OIDC token expired. Run `tntc login` to re-authenticate (detail: %w)", err)
 	}
 	if oidcToken != "" {
-		return mcp.NewClient(mcp.Config{Endpoint: endpoint, Token: oidcToken}), nil
+		return newMCPClient(cfg, mcp.Config{Endpoint: endpoint, Token: oidcToken}), nil
 	}
 
 	// No OIDC token available — connect without auth (server may reject)
-	return mcp.NewClient(mcp.Config{Endpoint: endpoint}), nil
+	return newMCPClient(cfg, mcp.Config{Endpoint: endpoint}), nil
 }
 
 // resolveEndpoint determines the MCP endpoint from env config, global config, or env vars.
@@ -245,11 +246,50 @@ func buildMCPClientForEnv(clusterName string) (*mcp.Client, error) {
 		return nil, fmt.Errorf("OIDC token expired. Run `tntc login` to re-authenticate (detail: %w)", err)
 	}
 	if oidcToken != "" {
-		return mcp.NewClient(mcp.Config{Endpoint: endpoint, Token: oidcToken}), nil
+		return newMCPClient(cfg, mcp.Config{Endpoint: endpoint, Token: oidcToken}), nil
 	}
 
 	// No OIDC token available — connect without auth (server may reject)
-	return mcp.NewClient(mcp.Config{Endpoint: endpoint}), nil
+	return newMCPClient(cfg, mcp.Config{Endpoint: endpoint}), nil
+}
+
+// mcpClients records every MCP client created by this process so that
+// EmitResult can report aggregate call metrics in -o json output.
+var mcpClients struct {
+	list []*mcp.Client
+	mu   sync.Mutex
+}
+
+// newMCPClient creates an MCP client with the retry policy from config and
+// records it for metrics reporting.
+func newMCPClient(cfg TentacularConfig, mcpCfg mcp.Config) *mcp.Client {
+	r := cfg.MCP.Retry
+	mcpCfg.Retry = mcp.RetryPolicy{
+		MaxAttempts: r.MaxAttempts,
+		BaseDelay:   r.BaseDelay,
+		MaxDelay:    r.MaxDelay,
+		Budget:      r.Budget,
+	}
+	client := mcp.NewClient(mcpCfg)
+	mcpClients.mu.Lock()
+	mcpClients.list = append(mcpClients.list, client)
+	mcpClients.mu.Unlock()
+	return client
+}
+
+// mcpCallMetrics aggregates tool call metrics across all MCP clients created
+// so far. Returns nil when no tool calls were made.
+func mcpCallMetrics() *mcp.CallMetrics {
+	mcpClients.mu.Lock()
+	defer mcpClients.mu.Unlock()
+	var total mcp.CallMetrics
+	for _, c := range mcpClients.list {
+		total.Add(c.Metrics())
+	}
+	if total.Calls == 0 {
+		return nil
+	}
+	return &total
 }
 
 // mcpErrorHint returns a user-friendly hint for common MCP errors.
@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
 )
 
 // newTestCmd creates a minimal cobra command for resolve tests.
@@ -442,3 +444,49 @@ func TestResolveOIDCToken_EnvVarNoExpClaim(t *testing.T) {
 		t.Error("expected token to be returned when no exp claim present")
 	}
 }
+
+// TestEmitResult_ReportsMCPCallMetrics verifies that -o json output carries
+// MCP call metrics from clients created through newMCPClient, and that the
+// retry policy is read from the mcp.retry config block.
+func TestEmitResult_ReportsMCPCallMetrics(t *testing.T) {
+	mcpClients.mu.Lock()
+	saved := mcpClients.list
+	mcpClients.list = nil
+	mcpClients.mu.Unlock()
+	t.Cleanup(func() {
+		mcpClients.mu.Lock()
+		mcpClients.list = saved
+		mcpClients.mu.Unlock()
+	})
+
+	var cfg TentacularConfig
+	if err := yaml.Unmarshal([]byte("mcp:\n  retry:\n    max_attempts: 1\n    base_delay: 10ms\n    budget: 2s\n"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MCP.Retry.MaxAttempts != 1 || cfg.MCP.Retry.BaseDelay != 10*time.Millisecond || cfg.MCP.Retry.Budget != 2*time.Second {
+		t.Fatalf("unexpected retry config: %+v", cfg.MCP.Retry)
+	}
+
+	srv := mcptest.NewServer(t)
+	client := newMCPClient(cfg, mcp.Config{Endpoint: srv.URL()})
+	defer func() { _ = client.Close() }()
+	if _, err := client.WfList(t.Context(), "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTestCmd()
+	cmd.Flags().StringP("output", "o", "json", "")
+	var buf strings.Builder
+	if err := EmitResult(cmd, CommandResult{Command: "list", Status: "pass"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		MCP *mcp.CallMetrics `json:"mcp"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &parsed); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if parsed.MCP == nil || parsed.MCP.Calls != 1 || parsed.MCP.Tools["wf_list"].Attempts != 1 {
+		t.Errorf("expected wf_list metrics in JSON output, got %s", buf.String())
+	}
+}
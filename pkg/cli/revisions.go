@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// revisionHistoryLimit is the number of revisions kept per workflow.
+const revisionHistoryLimit = 50
+
+// deployRevision is one applied set of manifests, recorded by every
+// `tntc deploy`, `tntc rollback` and `tntc promote`. Secrets are never stored: HasSecrets
+// records that the deploy provisioned one, to be re-resolved from
+// WorkflowDir on rollback.
+type deployRevision struct {
+	DeployedAt   time.Time        `json:"deployedAt"`
+	Workflow     string           `json:"workflow"`
+	Namespace    string           `json:"namespace"`
+	Cluster      string           `json:"cluster,omitempty"`
+	Image        string           `json:"image,omitempty"`
+	Version      string           `json:"version,omitempty"`
+	GitSHA       string           `json:"gitSha,omitempty"`
+	GitBranch    string           `json:"gitBranch,omitempty"`
+	GitRepo      string           `json:"gitRepo,omitempty"`
+	WorkflowDir  string           `json:"workflowDir,omitempty"`
+	PromotedFrom string           `json:"promotedFrom,omitempty"` // <cluster>/<revision> of the promoted source
+	Manifests    []map[string]any `json:"manifests"`
+	Revision     int              `json:"revision"`
+	RollbackOf   int              `json:"rollbackOf,omitempty"`
+	HasSecrets   bool             `json:"hasSecrets,omitempty"`
+}
+
+// revisionStore holds the revisions of workflows on one cluster. With
+// git-state enabled, revisions are committed to the git-state repo under
+// revisions/ so the team shares one history. Otherwise they are kept in the
+// cluster, in the <workflow>-revisions ConfigMap of the workflow namespace;
+// ~/.tentacular/revisions/ is only used when the transport cannot keep them
+// (an MCP server without wf_revisions).
+type revisionStore struct {
+	ctx     context.Context
+	keeper  mcp.RevisionKeeper // set when revisions live in the cluster
+	root    string             // <root>/<cluster>/<namespace>/<workflow>/<NNNN>.json
+	gitRepo string             // set when root lives in the git-state repo
+}
+
+// resolveRevisionStore picks the store for the configured environment;
+// client is the transport to the workflow's cluster (nil with git-state).
+func resolveRevisionStore(ctx context.Context, cfg TentacularConfig, client mcp.WorkflowClient) revisionStore {
+	if gitStateRevisions(cfg) {
+		return revisionStore{root: filepath.Join(cfg.GitState.RepoPath, "revisions"), gitRepo: cfg.GitState.RepoPath}
+	}
+	store := revisionStore{ctx: ctx, root: filepath.Join(".tentacular", "revisions")} // last resort
+	if keeper, ok := client.(mcp.RevisionKeeper); ok {
+		store.keeper = keeper
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		store.root = filepath.Join(home, ".tentacular", "revisions")
+	}
+	return store
+}
+
+// gitStateRevisions reports whether revisions are kept in the git-state repo,
+// so commands can skip connecting to the cluster for them.
+func gitStateRevisions(cfg TentacularConfig) bool {
+	return cfg.GitState.Enabled && cfg.GitState.RepoPath != ""
+}
+
+func (s revisionStore) dir(cluster, namespace, workflow string) string {
+	if cluster == "" {
+		cluster = "default"
+	}
+	return filepath.Join(s.root, filepath.Base(cluster), filepath.Base(namespace), filepath.Base(workflow))
+}
+
+// list returns the workflow's revisions, newest first.
+func (s revisionStore) list(cluster, namespace, workflow string) ([]deployRevision, error) {
+	if s.keeper != nil {
+		result, err := s.keeper.WfRevisions(s.ctx, namespace, workflow)
+		switch {
+		case err == nil:
+			revs := make([]deployRevision, 0, len(result.Revisions))
+			for _, stored := range result.Revisions {
+				var rev deployRevision
+				if json.Unmarshal(stored.Data, &rev) == nil {
+					rev.Revision = stored.Revision
+					revs = append(revs, rev)
+				}
+			}
+			return revs, nil
+		case !mcp.IsServerTooOld(err):
+			return nil, fmt.Errorf("reading revision history: %w", err)
+		}
+	}
+	dir := s.dir(cluster, namespace, workflow)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading revision history: %w", err)
+	}
+	var revs []deployRevision
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name())) //nolint:gosec // path within the revision directory
+		if err != nil {
+			continue
+		}
+		var rev deployRevision
+		if json.Unmarshal(data, &rev) == nil {
+			revs = append(revs, rev)
+		}
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Revision > revs[j].Revision })
+	return revs, nil
+}
+
+// get returns revision n, or the latest when n is 0.
+func (s revisionStore) get(cluster, namespace, workflow string, n int) (*deployRevision, error) {
+	revs, err := s.list(cluster, namespace, workflow)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revs {
+		if n == 0 || revs[i].Revision == n {
+			return &revs[i], nil
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("no revisions recorded for %s in %s", workflow, namespace)
+	}
+	return nil, fmt.Errorf("revision %d of %s not found", n, workflow)
+}
+
+// save numbers rev after the newest stored revision, writes it and prunes
+// the history to revisionHistoryLimit (and, in the cluster, to what fits in
+// the ConfigMap). In the git-state repo the change is committed (it is
+// pushed with the next deploy).
+func (s revisionStore) save(rev *deployRevision) error {
+	if s.keeper != nil {
+		rev.Revision = 0 // numbered by the keeper
+		data, err := json.Marshal(rev)
+		if err != nil {
+			return fmt.Errorf("encoding revision: %w", err)
+		}
+		result, err := s.keeper.WfRecordRevision(s.ctx, mcp.WfRecordRevisionParams{
+			Namespace: rev.Namespace,
+			Name:      rev.Workflow,
+			Data:      data,
+			Limit:     revisionHistoryLimit,
+		})
+		if err == nil {
+			rev.Revision = result.Revision
+			return nil
+		}
+		if !mcp.IsServerTooOld(err) {
+			return fmt.Errorf("recording revision: %w", err)
+		}
+	}
+	revs, err := s.list(rev.Cluster, rev.Namespace, rev.Workflow)
+	if err != nil {
+		return err
+	}
+	rev.Revision = 1
+	if len(revs) > 0 {
+		rev.Revision = revs[0].Revision + 1
+	}
+
+	dir := s.dir(rev.Cluster, rev.Namespace, rev.Workflow)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating revision directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rev, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding revision: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, revisionFileName(rev.Revision)), data, 0o600); err != nil {
+		return fmt.Errorf("writing revision: %w", err)
+	}
+	for _, old := range revs[min(len(revs), revisionHistoryLimit-1):] {
+		_ = os.Remove(filepath.Join(dir, revisionFileName(old.Revision)))
+	}
+
+	if s.gitRepo == "" {
+		return nil
+	}
+	msg := fmt.Sprintf("tntc: record revision %d of %s/%s", rev.Revision, rev.Namespace, rev.Workflow)
+	for _, args := range [][]string{
+		{"add", "-A", "--", dir},
+		{"commit", "-q", "-m", msg, "--", dir},
+	} {
+		cmd := exec.CommandContext(context.Background(), "git", append([]string{"-C", s.gitRepo}, args...)...) //nolint:gosec // repo path is config-controlled
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("committing revision to git-state repo: %w\n%s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+func revisionFileName(n int) string {
+	return fmt.Sprintf("%04d.json", n)
+}
+
+// newDeployRevision builds a revision from the manifests just applied,
+// dropping the Secret and reading image and version from the Deployment.
+func newDeployRevision(result *DeployResult, cluster, workflowDir string, meta GitMeta) deployRevision {
+	rev := deployRevision{
+		DeployedAt:  time.Now().UTC(),
+		Workflow:    result.WorkflowName,
+		Namespace:   result.Namespace,
+		Cluster:     cluster,
+		GitSHA:      meta.SHA,
+		GitBranch:   meta.Branch,
+		GitRepo:     meta.Repo,
+		WorkflowDir: workflowDir,
+	}
+	for _, m := range result.Manifests {
+		switch m["kind"] {
+		case "Secret":
+			rev.HasSecrets = true
+			continue
+		case "Deployment":
+			rev.Image, rev.Version = deploymentImageAndVersion(m)
+		}
+		rev.Manifests = append(rev.Manifests, m)
+	}
+	return rev
+}
+
+// deploymentImageAndVersion reads the engine image and the
+// tentacular.io/version annotation from a Deployment manifest.
+func deploymentImageAndVersion(m map[string]any) (image, version string) {
+	if meta, ok := m["metadata"].(map[string]any); ok {
+		if ann, ok := meta["annotations"].(map[string]any); ok {
+			version, _ = ann["tentacular.io/version"].(string)
+		}
+	}
+	spec, _ := m["spec"].(map[string]any)
+	tmpl, _ := spec["template"].(map[string]any)
+	podSpec, _ := tmpl["spec"].(map[string]any)
+	containers, _ := podSpec["containers"].([]any)
+	for _, c := range containers {
+		container, _ := c.(map[string]any)
+		if img, _ := container["image"].(string); img != "" && (image == "" || container["name"] == "engine") {
+			image = img
+		}
+	}
+	return image, version
+}
+
+// recordRevision stores a deploy made through client in the revision
+// history. Failures only warn: history must never fail the deploy that
+// already happened.
+func recordRevision(cmd *cobra.Command, client mcp.WorkflowClient, rev deployRevision) {
+	if err := resolveRevisionStore(cmd.Context(), LoadConfig(), client).save(&rev); err != nil {
+		_, _ = fmt.Fprintf(StatusWriter(cmd), "Warning: recording revision history: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(StatusWriter(cmd), "  Recorded revision %d\n", rev.Revision)
+}
+
+// shortSHA abbreviates a git SHA for tables.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func writeRevisionsText(w io.Writer, revs []deployRevision) {
+	_, _ = fmt.Fprintf(w, "%-5s %-21s %-24s %-8s %-10s %s\n", "REV", "DEPLOYED", "VERSION", "GIT", "SOURCE", "IMAGE")
+	for i, rev := range revs {
+		source := "deploy"
+		switch {
+		case rev.RollbackOf > 0:
+			source = "rollback:" + strconv.Itoa(rev.RollbackOf)
+		case rev.PromotedFrom != "":
+			source = "promote:" + rev.PromotedFrom
+		}
+		marker := strconv.Itoa(rev.Revision)
+		if i == 0 {
+			marker += "*"
+		}
+		_, _ = fmt.Fprintf(w, "%-5s %-21s %-24s %-8s %-10s %s\n",
+			marker, rev.DeployedAt.Format(time.RFC3339), orDash(rev.Version), orDash(shortSHA(rev.GitSHA)), source, orDash(rev.Image))
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func runDeployCmd(t *testing.T, args ...string) error {
+	t.Helper()
+	cmd := withRootFlags(NewDeployCmd())
+	cmd.SetArgs(append([]string{".", "--force", "--runtime-class", ""}, args...))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd.ExecuteContext(context.Background())
+}
+
+func runHistoryCmd(t *testing.T, newCmd func() *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	c := withRootFlags(newCmd())
+	c.SetArgs(args)
+	var out bytes.Buffer
+	c.SetOut(&out)
+	err := c.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestRevisionStore_SaveNumbersAndPrunes(t *testing.T) {
+	store := revisionStore{root: t.TempDir()}
+	for range revisionHistoryLimit + 2 {
+		rev := deployRevision{Workflow: "wf", Namespace: "ns", Cluster: "prod"}
+		if err := store.save(&rev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	revs, err := store.list("prod", "ns", "wf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != revisionHistoryLimit || revs[0].Revision != revisionHistoryLimit+2 || revs[len(revs)-1].Revision != 3 {
+		t.Errorf("expected revisions 52..3, got %d revisions from %d to %d", len(revs), revs[0].Revision, revs[len(revs)-1].Revision)
+	}
+	if other, _ := store.list("dev", "ns", "wf"); len(other) != 0 {
+		t.Errorf("expected history to be per cluster, got %d revisions", len(other))
+	}
+	if _, err := store.get("prod", "ns", "wf", 1); err == nil {
+		t.Error("expected pruned revision to be missing")
+	}
+}
+
+func TestNewDeployRevision_DropsSecret(t *testing.T) {
+	result := &DeployResult{WorkflowName: "wf", Namespace: "ns", Manifests: []map[string]any{
+		{"kind": "ConfigMap", "metadata": map[string]any{"name": "wf-code"}},
+		{"kind": "Secret", "metadata": map[string]any{"name": "wf-secrets"}},
+		{"kind": "Deployment", "metadata": map[string]any{
+			"name":        "wf",
+			"annotations": map[string]any{"tentacular.io/version": "1.2+abc1234"},
+		}, "spec": map[string]any{"template": map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "engine", "image": "engine:1.2"},
+		}}}}},
+	}}
+
+	rev := newDeployRevision(result, "prod", "/src/wf", GitMeta{SHA: "abc1234def", Branch: "main"})
+	if !rev.HasSecrets || len(rev.Manifests) != 2 {
+		t.Errorf("expected Secret dropped and flagged, got %d manifests (hasSecrets=%v)", len(rev.Manifests), rev.HasSecrets)
+	}
+	if rev.Image != "engine:1.2" || rev.Version != "1.2+abc1234" || rev.GitSHA != "abc1234def" {
+		t.Errorf("unexpected revision: %+v", rev)
+	}
+}
+
+func TestRevisionStore_GitStateCommits(t *testing.T) {
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+		{"commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	store := resolveRevisionStore(context.Background(), TentacularConfig{GitState: GitStateConfig{Enabled: true, RepoPath: repo}}, nil)
+	if err := store.save(&deployRevision{Workflow: "wf", Namespace: "ns"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	out, _ := exec.Command("git", "-C", repo, "log", "-1", "--format=%s").Output()
+	if strings.TrimSpace(string(out)) != "tntc: record revision 1 of ns/wf" {
+		t.Errorf("expected revision commit, got %q", out)
+	}
+	if status, _ := exec.Command("git", "-C", repo, "status", "--porcelain").Output(); len(status) != 0 {
+		t.Errorf("expected clean repo, got %s", status)
+	}
+}
+
+func TestHistoryAndRollback(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := runDeployCmd(t, "--image", "engine:1.0"); err != nil {
+		t.Fatalf("first deploy: %v", err)
+	}
+	if err := runDeployCmd(t, "--image", "engine:2.0"); err != nil {
+		t.Fatalf("second deploy: %v", err)
+	}
+
+	out, err := runHistoryCmd(t, NewHistoryCmd, "test-workflow")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "2*") || !strings.Contains(lines[1], "engine:2.0") || !strings.Contains(lines[2], "engine:1.0") {
+		t.Fatalf("unexpected history:\n%s", out)
+	}
+
+	// History lives in the cluster, so another machine sees the same revisions.
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".tentacular", "revisions")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no local revision files, got %v", err)
+	}
+	t.Setenv("HOME", t.TempDir())
+	if other, _ := runHistoryCmd(t, NewHistoryCmd, "test-workflow"); other != out {
+		t.Errorf("expected the same history from another home directory:\n%s", other)
+	}
+
+	if _, err := runHistoryCmd(t, NewRollbackCmd, "test-workflow"); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" {
+		t.Errorf("expected engine:1.0 live after rollback, got %s", wf.Image)
+	}
+	out, _ = runHistoryCmd(t, NewHistoryCmd, "test-workflow")
+	if !strings.Contains(out, "rollback:1") || !strings.HasPrefix(strings.Split(out, "\n")[1], "3*") {
+		t.Errorf("expected rollback recorded as revision 3:\n%s", out)
+	}
+
+	if _, err := runHistoryCmd(t, NewRollbackCmd, "test-workflow", "--to", "9"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
+
+func TestRollbackManifests_ReResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	rev := &deployRevision{Workflow: "wf", Namespace: "ns", Revision: 4, HasSecrets: true, WorkflowDir: dir,
+		Manifests: []map[string]any{{"kind": "ConfigMap"}}}
+
+	var warn bytes.Buffer
+	manifests, err := rollbackManifests(rev, &warn)
+	if err != nil || len(manifests) != 1 || !strings.Contains(warn.String(), "keeping the deployed wf-secrets") {
+		t.Fatalf("expected missing source to keep the live Secret, got %d manifests, %v, %q", len(manifests), err, warn.String())
+	}
+
+	if err := os.WriteFile(dir+"/.secrets.yaml", []byte("api_key: plain\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rollbackManifests(rev, &warn); err == nil {
+		t.Error("expected invalid secrets source to fail the rollback")
+	}
+}
@@ -1,45 +1,234 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
 func NewRunCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run <name>",
 		Short: "Trigger a deployed workflow",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runRun,
+		Long: `Trigger a deployed workflow and print its result.
+
+--input passes a JSON payload (- reads stdin). When the current directory holds
+the workflow's workflow.yaml and it declares input.schema, the payload is
+validated before the run starts.
+
+--async returns an execution ID immediately; block on it with
+"tntc run wait <execution-id>".`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRun,
 	}
 	cmd.Flags().Duration("timeout", 120*time.Second, "Maximum time to wait for result")
 	cmd.Flags().Bool("no-wait", false, "Deprecated: MCP server handles readiness internally")
+	cmd.Flags().Bool("async", false, "Start the run and return its execution ID without waiting")
+	addInputFlag(cmd)
+	cmd.AddCommand(newRunWaitCmd())
+	return cmd
+}
+
+func newRunWaitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait <execution-id>",
+		Short: "Wait for an asynchronous run to finish",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRunWait,
+	}
+	cmd.Flags().Duration("timeout", 10*time.Minute, "Maximum time to wait for the execution to finish")
 	return cmd
 }
 
+// runWaitPoll is the longest wait_seconds requested per wf_run_status call,
+// so a slow execution is polled rather than held in a single long request.
+// The MCP client further caps it below its request timeout.
+const runWaitPoll = 30 * time.Second
+
+// runWaitInterval is the minimum time between wf_run_status polls, so a
+// server that ignores wait_seconds is not polled in a tight loop.
+var runWaitInterval = 2 * time.Second
+
+// runExecution is the Execution payload of the run CommandResult.
+type runExecution struct {
+	ExecutionID string           `json:"executionId,omitempty"`
+	Workflow    string           `json:"workflow"`
+	Namespace   string           `json:"namespace"`
+	State       string           `json:"state,omitempty"`
+	Pod         string           `json:"pod,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	Output      json.RawMessage  `json:"output,omitempty"`
+	Nodes       []mcp.NodeTiming `json:"nodes,omitempty"`
+	DurationMs  int64            `json:"durationMs"`
+}
+
 func runRun(cmd *cobra.Command, args []string) error {
+	startedAt := time.Now().UTC()
 	name := args[0]
 	namespace := resolveNamespace(cmd, ".")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	async, _ := cmd.Flags().GetBool("async")
+
+	input, err := loadRunInput(cmd, ".", name)
+	if err != nil {
+		return err
+	}
 
 	mcpClient, err := requireMCPClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(os.Stderr, "Running workflow %s in %s...\n", name, namespace)
+	w := StatusWriter(cmd)
+	if async {
+		_, _ = fmt.Fprintf(w, "Starting workflow %s in %s...\n", name, namespace)
+		result, runErr := mcpClient.WfRunAsync(cmd.Context(), namespace, name, input)
+		if runErr != nil {
+			return runError(runErr)
+		}
+		recordRun(cmd, runTriggerRun, result, nil, namespace, name, input, startedAt)
+		return emitRunResult(cmd, "pass",
+			fmt.Sprintf("started execution %s of %s", result.ExecutionID, name),
+			[]string{"wait for it with: tntc run wait " + result.ExecutionID},
+			runExecutionFrom(result), startedAt)
+	}
+
+	_, _ = fmt.Fprintf(w, "Running workflow %s in %s...\n", name, namespace)
+	result, err := mcpClient.WfRun(cmd.Context(), namespace, name, input, int(timeout.Seconds()))
+	recordRun(cmd, runTriggerRun, result, err, namespace, name, input, startedAt)
+	if err != nil {
+		return runError(err)
+	}
+	return emitFinishedRun(cmd, result, startedAt)
+}
 
-	result, err := mcpClient.WfRun(cmd.Context(), namespace, name, nil, int(timeout.Seconds()))
+func runRunWait(cmd *cobra.Command, args []string) error {
+	startedAt := time.Now().UTC()
+	executionID := args[0]
+	namespace := resolveNamespace(cmd, ".")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	mcpClient, err := requireMCPClient(cmd)
 	if err != nil {
-		if hint := mcpErrorHint(err); hint != "" {
-			return fmt.Errorf("running workflow: %w\n  hint: %s", err, hint)
+		return err
+	}
+
+	_, _ = fmt.Fprintf(StatusWriter(cmd), "Waiting for execution %s in %s...\n", executionID, namespace)
+	ctx := cmd.Context()
+	deadline := startedAt.Add(timeout)
+	for {
+		polledAt := time.Now()
+		wait := min(time.Until(deadline), runWaitPoll)
+		result, err := mcpClient.WfRunStatus(ctx, namespace, executionID, max(int(wait.Seconds()), 1))
+		if err != nil {
+			return runError(err)
+		}
+		if !result.Running() {
+			recordRun(cmd, runTriggerRun, result, nil, namespace, result.Name, nil, startedAt)
+			return emitFinishedRun(cmd, result, startedAt)
 		}
-		return fmt.Errorf("running workflow: %w", err)
+		if time.Now().After(deadline) {
+			return emitRunResult(cmd, "fail",
+				fmt.Sprintf("execution %s still running after %s", executionID, timeout),
+				[]string{"keep waiting with: tntc run wait " + executionID + " --timeout 30m"},
+				runExecutionFrom(result), startedAt)
+		}
+		if pause := min(runWaitInterval-time.Since(polledAt), time.Until(deadline)); pause > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pause):
+			}
+		}
+	}
+}
+
+// runError wraps a wf_run/wf_run_status failure with an MCP hint when one applies.
+func runError(err error) error {
+	if hint := mcpErrorHint(err); hint != "" {
+		return fmt.Errorf("running workflow: %w\n  hint: %s", err, hint)
 	}
+	return fmt.Errorf("running workflow: %w", err)
+}
 
-	_, _ = fmt.Fprint(os.Stdout, string(result.Output))
+// emitFinishedRun reports a completed execution. A run fails when the server
+// reports it failed or the workflow output carries success=false.
+func emitFinishedRun(cmd *cobra.Command, result *mcp.WfRunResult, startedAt time.Time) error {
+	status := "pass"
+	summary := fmt.Sprintf("workflow %s completed in %dms", result.Name, result.DurationMs)
+	var hints []string
+	if ok, reason := runOutcome(result); !ok {
+		status = "fail"
+		summary = fmt.Sprintf("workflow %s %s", result.Name, reason)
+		hints = append(hints, "check logs with: tntc logs "+result.Name)
+	}
+	return emitRunResult(cmd, status, summary, hints, runExecutionFrom(result), startedAt)
+}
+
+func runExecutionFrom(result *mcp.WfRunResult) runExecution {
+	return runExecution{
+		ExecutionID: result.ExecutionID,
+		Workflow:    result.Name,
+		Namespace:   result.Namespace,
+		State:       result.Status,
+		Pod:         result.PodName,
+		Error:       result.Error,
+		Output:      result.Output,
+		Nodes:       result.Nodes,
+		DurationMs:  result.DurationMs,
+	}
+}
+
+// emitRunResult outputs the run result in the appropriate format. In text
+// mode the node timings and workflow output are printed before the summary.
+func emitRunResult(cmd *cobra.Command, status, summary string, hints []string, exec runExecution, startedAt time.Time) error {
+	result := CommandResult{
+		Version:   "1",
+		Command:   "run",
+		Status:    status,
+		Summary:   summary,
+		Hints:     append([]string{}, hints...),
+		Execution: exec,
+		Timing: TimingInfo{
+			StartedAt:  startedAt.Format(time.RFC3339),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		},
+	}
+
+	if format, _ := cmd.Flags().GetString("output"); format != "json" {
+		printRunDetails(os.Stdout, exec)
+	}
+	if err := EmitResult(cmd, result, os.Stdout); err != nil {
+		return err
+	}
+	if status == "fail" {
+		return fmt.Errorf("run failed: %s", summary)
+	}
 	return nil
 }
+
+func printRunDetails(w io.Writer, exec runExecution) {
+	if len(exec.Nodes) > 0 {
+		_, _ = fmt.Fprintln(w, "Nodes:")
+		for _, n := range exec.Nodes {
+			state := n.Status
+			if state == "" {
+				state = "-"
+			}
+			_, _ = fmt.Fprintf(w, "  %-24s %-10s %6dms", n.Name, state, n.DurationMs)
+			if n.Error != "" {
+				_, _ = fmt.Fprintf(w, "  %s", n.Error)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+	if len(exec.Output) > 0 && string(exec.Output) != "null" {
+		_, _ = fmt.Fprintln(w, string(exec.Output))
+	}
+}
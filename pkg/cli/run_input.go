@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// addInputFlag adds the --input flag shared by run, test --live and deploy.
+func addInputFlag(cmd *cobra.Command) {
+	cmd.Flags().String("input", "", "JSON input payload file for the workflow run (- reads stdin)")
+}
+
+// loadRunInput reads the --input payload and validates it against the input
+// schema declared in workflowDir/workflow.yaml. When name is non-empty the
+// schema is only used if the local workflow has that name, so running a
+// different deployed workflow from inside a workflow directory is not
+// validated against the wrong schema. Returns nil when --input is not set.
+func loadRunInput(cmd *cobra.Command, workflowDir, name string) (json.RawMessage, error) {
+	path, _ := cmd.Flags().GetString("input")
+	if path == "" {
+		return nil, nil
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path) //nolint:gosec // user-specified input file
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading --input %s: %w", path, err)
+	}
+	return parseRunInput(data, "--input "+path, workflowDir, name)
+}
+
+// loadInputFixture reads a JSON input fixture file and validates it like
+// --input. label names the fixture's source in errors.
+func loadInputFixture(path, label, workflowDir string) (json.RawMessage, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-configured fixture file
+	if err != nil {
+		return nil, fmt.Errorf("reading %s %s: %w", label, path, err)
+	}
+	return parseRunInput(data, label+" "+path, workflowDir, "")
+}
+
+// parseRunInput checks that data is JSON matching the workflow input schema.
+func parseRunInput(data []byte, source, workflowDir, name string) (json.RawMessage, error) {
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", source)
+	}
+	input := json.RawMessage(strings.TrimSpace(string(data)))
+
+	schema, err := workflowInputSchema(workflowDir, name)
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		if err := validateRunInput(schema, input); err != nil {
+			return nil, err
+		}
+	}
+	return input, nil
+}
+
+// workflowInputSchema returns the input.schema declared in
+// workflowDir/workflow.yaml, or nil when there is no readable workflow, it
+// declares no schema, or its name differs from name.
+func workflowInputSchema(workflowDir, name string) (map[string]any, error) {
+	data, err := os.ReadFile(filepath.Join(workflowDir, "workflow.yaml")) //nolint:gosec // path derived from workflow directory
+	if err != nil {
+		return nil, nil
+	}
+	wf, errs := spec.Parse(data)
+	if wf == nil || len(errs) > 0 {
+		return nil, nil
+	}
+	if name != "" && wf.Name != name {
+		return nil, nil
+	}
+	if wf.Input == nil {
+		return nil, nil
+	}
+	return wf.Input.Schema, nil
+}
+
+// validateRunInput checks input against a JSON Schema given as a decoded
+// YAML/JSON object.
+func validateRunInput(schema map[string]any, input json.RawMessage) error {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("encoding input schema: %w", err)
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("parsing input schema: %w", err)
+	}
+	resolved, err := s.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("resolving input schema: %w", err)
+	}
+	var instance any
+	if err := json.Unmarshal(input, &instance); err != nil {
+		return fmt.Errorf("decoding input: %w", err)
+	}
+	if err := resolved.Validate(instance); err != nil {
+		return errors.New("input does not match the workflow input schema: " + err.Error())
+	}
+	return nil
+}
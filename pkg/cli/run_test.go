@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+const inputSchemaWorkflowYAML = minimalWorkflowYAML + `input:
+  schema:
+    type: object
+    required: [url]
+    properties:
+      url:
+        type: string
+`
+
+// deployRunFixture deploys test-workflow to a fake MCP server and leaves the
+// test in a working directory holding its workflow.yaml (with an input schema).
+func deployRunFixture(t *testing.T) *mcptest.Server {
+	t.Helper()
+	srv := mcptest.NewServer(t)
+	cleanup := setupMCPEnv(t, srv.URL())
+	t.Cleanup(cleanup)
+
+	files := map[string]string{
+		"workflow.yaml": inputSchemaWorkflowYAML,
+		"handler.ts":    "export default async () => ({})\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var status bytes.Buffer
+	if _, err := deployWorkflow(".", InternalDeployOptions{Namespace: "default", Image: "engine:1.0", StatusOut: &status}, srv.Client()); err != nil {
+		t.Fatalf("deploying fixture: %v", err)
+	}
+	return srv
+}
+
+func runRunCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := withRootFlags(NewRunCmd())
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	var err error
+	out := captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	return out, err
+}
+
+func TestRun_InputFromFile(t *testing.T) {
+	srv := deployRunFixture(t)
+	inputPath := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(inputPath, []byte(`{"url":"https://example.com"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runRunCmd(t, "", "test-workflow", "--input", inputPath); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	wf, _ := srv.Workflow("default", "test-workflow")
+	if string(wf.LastInput) != `{"url":"https://example.com"}` {
+		t.Errorf("expected input passed to wf_run, got %s", wf.LastInput)
+	}
+}
+
+func TestRun_InputFromStdin(t *testing.T) {
+	srv := deployRunFixture(t)
+
+	if _, err := runRunCmd(t, `{"url":"https://stdin.example"}`, "test-workflow", "--input", "-"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	wf, _ := srv.Workflow("default", "test-workflow")
+	if string(wf.LastInput) != `{"url":"https://stdin.example"}` {
+		t.Errorf("expected stdin input passed to wf_run, got %s", wf.LastInput)
+	}
+}
+
+func TestRun_InputFailsSchemaValidation(t *testing.T) {
+	srv := deployRunFixture(t)
+
+	_, err := runRunCmd(t, `{"other":1}`, "test-workflow", "--input", "-")
+	if err == nil || !strings.Contains(err.Error(), "input does not match the workflow input schema") {
+		t.Fatalf("expected schema validation error, got %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Runs != 0 {
+		t.Errorf("expected no run after validation failure, got %d", wf.Runs)
+	}
+}
+
+func TestRun_InvalidJSONInput(t *testing.T) {
+	deployRunFixture(t)
+
+	_, err := runRunCmd(t, `{not json`, "test-workflow", "--input", "-")
+	if err == nil || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Fatalf("expected invalid JSON error, got %v", err)
+	}
+}
+
+func TestRun_SchemaIgnoredForOtherWorkflow(t *testing.T) {
+	if schema, err := workflowInputSchema(t.TempDir(), "other"); schema != nil || err != nil {
+		t.Errorf("expected no schema without workflow.yaml, got %v, %v", schema, err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(inputSchemaWorkflowYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if schema, _ := workflowInputSchema(dir, "other"); schema != nil {
+		t.Errorf("expected schema ignored for a different workflow name, got %v", schema)
+	}
+	if schema, _ := workflowInputSchema(dir, "test-workflow"); schema == nil {
+		t.Error("expected schema for matching workflow name")
+	}
+}
+
+func TestRun_AsyncThenWait(t *testing.T) {
+	srv := deployRunFixture(t)
+	nodes := []mcp.NodeTiming{{Name: "handler", Status: "succeeded", DurationMs: 42}}
+	if err := srv.SetRunNodes("default", "test-workflow", nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRunCmd(t, "", "test-workflow", "--async", "-o", "json")
+	if err != nil {
+		t.Fatalf("run --async: %v", err)
+	}
+	var started struct {
+		Status    string       `json:"status"`
+		Execution runExecution `json:"execution"`
+	}
+	if err := json.Unmarshal([]byte(out), &started); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	id := started.Execution.ExecutionID
+	if id == "" || started.Execution.State != mcp.RunStatusRunning {
+		t.Fatalf("expected running execution with ID, got %+v", started.Execution)
+	}
+
+	out, err = runRunCmd(t, "", "wait", id, "-o", "json")
+	if err != nil {
+		t.Fatalf("run wait: %v", err)
+	}
+	var finished struct {
+		Command   string       `json:"command"`
+		Status    string       `json:"status"`
+		Execution runExecution `json:"execution"`
+	}
+	if err := json.Unmarshal([]byte(out), &finished); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if finished.Command != "run" || finished.Status != "pass" {
+		t.Errorf("expected passing run result, got %+v", finished)
+	}
+	if finished.Execution.ExecutionID != id || finished.Execution.State != mcp.RunStatusSucceeded {
+		t.Errorf("unexpected execution: %+v", finished.Execution)
+	}
+	if len(finished.Execution.Nodes) != 1 || finished.Execution.Nodes[0].DurationMs != 42 {
+		t.Errorf("expected node timings, got %+v", finished.Execution.Nodes)
+	}
+}
+
+// startAsyncRun starts test-workflow with --async and returns its execution ID.
+func startAsyncRun(t *testing.T) string {
+	t.Helper()
+	out, err := runRunCmd(t, "", "test-workflow", "--async", "-o", "json")
+	if err != nil {
+		t.Fatalf("run --async: %v", err)
+	}
+	var started struct {
+		Execution runExecution `json:"execution"`
+	}
+	if err := json.Unmarshal([]byte(out), &started); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	return started.Execution.ExecutionID
+}
+
+func TestRun_WaitLongPollsSlowExecution(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := srv.SetRunDuration("default", "test-workflow", 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	id := startAsyncRun(t)
+
+	if _, err := runRunCmd(t, "", "wait", id); err != nil {
+		t.Fatalf("run wait: %v", err)
+	}
+	if n := srv.Calls("wf_run_status"); n != 1 {
+		t.Errorf("expected a single long poll, got %d wf_run_status calls", n)
+	}
+}
+
+func TestRun_WaitPausesWhenServerIgnoresWait(t *testing.T) {
+	srv := deployRunFixture(t)
+	orig := runWaitInterval
+	runWaitInterval = 100 * time.Millisecond
+	t.Cleanup(func() { runWaitInterval = orig })
+	srv.IgnoreRunWait(true)
+	if err := srv.SetRunDuration("default", "test-workflow", 500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	id := startAsyncRun(t)
+
+	if _, err := runRunCmd(t, "", "wait", id); err != nil {
+		t.Fatalf("run wait: %v", err)
+	}
+	if n := srv.Calls("wf_run_status"); n < 2 || n > 8 {
+		t.Errorf("expected polls paced by runWaitInterval, got %d wf_run_status calls", n)
+	}
+}
+
+func TestRun_AsyncFailsBeforeRunningWithoutRunStatus(t *testing.T) {
+	srv := deployRunFixture(t)
+	srv.RemoveTools("wf_run_status")
+
+	_, err := runRunCmd(t, "", "test-workflow", "--async")
+	if err == nil || !strings.Contains(err.Error(), "server too old") {
+		t.Fatalf("expected server too old error, got %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Runs != 0 {
+		t.Errorf("expected no run on a server without async support, got %d", wf.Runs)
+	}
+}
+
+func TestRun_WaitUnknownExecution(t *testing.T) {
+	deployRunFixture(t)
+
+	if _, err := runRunCmd(t, "", "wait", "exec-missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestRun_TextShowsNodeTimings(t *testing.T) {
+	srv := deployRunFixture(t)
+	nodes := []mcp.NodeTiming{{Name: "handler", Status: "succeeded", DurationMs: 7}}
+	if err := srv.SetRunNodes("default", "test-workflow", nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRunCmd(t, "", "test-workflow")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(out, "Nodes:") || !strings.Contains(out, "handler") || !strings.Contains(out, "7ms") {
+		t.Errorf("expected node timings in text output, got %q", out)
+	}
+}
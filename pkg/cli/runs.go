@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRunsCmd creates the "runs" command group for execution history.
+func NewRunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Inspect the history of workflow runs",
+		Long: `Inspect past workflow executions.
+
+Every run started by tntc (run, test --live and the deploy gates) is recorded
+under ~/.tentacular/runs/ with its cluster, enclave, input hash, output,
+result and duration. When the MCP server supports the wf_runs tool, its
+execution history is listed alongside the local records.`,
+	}
+	cmd.AddCommand(newRunsListCmd())
+	cmd.AddCommand(newRunsShowCmd())
+	cmd.AddCommand(newRunsDiffCmd())
+	return cmd
+}
+
+func newRunsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list <name>",
+		Short: "List recent runs of a workflow",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRunsList,
+	}
+	cmd.Flags().Int("limit", 20, "Maximum number of runs to list (0 for all)")
+	cmd.Flags().String("source", "all", "Where to read runs from: local|server|all")
+	return cmd
+}
+
+func newRunsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a recorded run",
+		Long: `Show a recorded run by run ID or execution ID.
+
+Execution IDs not found in the local history are looked up on the MCP server.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runRunsShow,
+	}
+}
+
+func newRunsDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <id1> <id2>",
+		Short: "Compare the results of two runs",
+		Long: `Compare two runs: result, cluster, enclave, input hash and every field of
+the workflow output. Useful to check a workflow still behaves the same after a
+deploy.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runRunsDiff,
+	}
+}
+
+func runRunsList(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	limit, _ := cmd.Flags().GetInt("limit")
+	source, _ := cmd.Flags().GetString("source")
+	if source != runSourceLocal && source != runSourceServer && source != "all" {
+		return fmt.Errorf("invalid --source %q: expected local, server or all", source)
+	}
+
+	var records []runRecord
+	if source != runSourceServer {
+		local, err := listRunRecords(resolveRunsDir(), name)
+		if err != nil {
+			return err
+		}
+		records = local
+	}
+	if source != runSourceLocal {
+		server, err := serverRuns(cmd, name, limit)
+		switch {
+		case err != nil && source == runSourceServer:
+			return err
+		case err != nil:
+			_, _ = fmt.Fprintf(StatusWriter(cmd), "Warning: server run history unavailable: %v\n", err)
+		}
+		records = mergeRunRecords(records, server)
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	if flagString(cmd, "output") == "json" {
+		if records == nil {
+			records = []runRecord{}
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	writeRunsTable(cmd.OutOrStdout(), name, records)
+	return nil
+}
+
+// errNoServerRunHistory is returned when the MCP server lacks wf_runs.
+var errNoServerRunHistory = errors.New("the MCP server does not provide run history (wf_runs); upgrade tentacular-mcp")
+
+// serverRuns lists executions recorded by the MCP server. It returns nothing
+// without error when no MCP server is configured.
+func serverRuns(cmd *cobra.Command, name string, limit int) ([]runRecord, error) {
+	client, err := resolveMCPClient(cmd)
+	if err != nil || client == nil {
+		return nil, err
+	}
+	caps, err := client.Capabilities(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	if !caps.HasTool("wf_runs") {
+		return nil, errNoServerRunHistory
+	}
+	results, err := client.WfRuns(cmd.Context(), resolveNamespace(cmd, "."), name, limit)
+	if err != nil {
+		return nil, err
+	}
+	cluster := activeClusterName(cmd, LoadConfig())
+	records := make([]runRecord, len(results))
+	for i := range results {
+		records[i] = runRecordFromServer(&results[i], cluster)
+	}
+	return records, nil
+}
+
+// mergeRunRecords adds server executions that have no local record and sorts
+// the result newest first.
+func mergeRunRecords(local, server []runRecord) []runRecord {
+	seen := make(map[string]bool, len(local))
+	for _, rec := range local {
+		if rec.ExecutionID != "" {
+			seen[rec.Namespace+"/"+rec.ExecutionID] = true
+		}
+	}
+	merged := local
+	for _, rec := range server {
+		if !seen[rec.Namespace+"/"+rec.ExecutionID] {
+			merged = append(merged, rec)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].StartedAt.After(merged[j].StartedAt) })
+	return merged
+}
+
+func writeRunsTable(w io.Writer, name string, records []runRecord) {
+	if len(records) == 0 {
+		_, _ = fmt.Fprintf(w, "No runs recorded for %s\n", name)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%-28s %-20s %-12s %-16s %-13s %-8s %9s  %-16s %s\n",
+		"RUN ID", "STARTED", "CLUSTER", "NAMESPACE", "TRIGGER", "RESULT", "DURATION", "INPUT", "SOURCE")
+	for i := range records {
+		rec := &records[i]
+		_, _ = fmt.Fprintf(w, "%-28s %-20s %-12s %-16s %-13s %-8s %7dms  %-16s %s\n",
+			rec.ID, formatRunTime(rec.StartedAt), orDash(rec.Cluster), rec.Namespace, orDash(rec.Trigger),
+			runResultLabel(rec), rec.DurationMs, orDash(strings.TrimPrefix(rec.InputHash, "sha256:")), rec.Source)
+	}
+}
+
+func runRunsShow(cmd *cobra.Command, args []string) error {
+	rec, err := lookupRun(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if flagString(cmd, "output") == "json" {
+		data, err := json.MarshalIndent(rec, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	writeRunRecordText(cmd.OutOrStdout(), rec)
+	return nil
+}
+
+// lookupRun finds a run in the local history, falling back to the MCP
+// server's wf_run_status for execution IDs not recorded locally.
+func lookupRun(cmd *cobra.Command, id string) (*runRecord, error) {
+	rec, err := findRunRecord(resolveRunsDir(), id)
+	if err != nil || rec != nil {
+		return rec, err
+	}
+	if client, clientErr := resolveMCPClient(cmd); clientErr == nil && client != nil {
+		if caps, capErr := client.Capabilities(cmd.Context()); capErr == nil && caps.HasTool("wf_run_status") {
+			result, statusErr := client.WfRunStatus(cmd.Context(), resolveNamespace(cmd, "."), id, 0)
+			if statusErr == nil {
+				server := runRecordFromServer(result, activeClusterName(cmd, LoadConfig()))
+				return &server, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("run %s not found in %s or on the MCP server; list runs with: tntc runs list <name>", id, resolveRunsDir())
+}
+
+// runDiffChange is a single difference between two runs.
+type runDiffChange struct {
+	Field  string `json:"field"`  // e.g. "result", "inputHash", "output.items[0].title"
+	Change string `json:"change"` // "added" | "removed" | "changed"
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// String renders the change as a single diff-style line.
+func (c runDiffChange) String() string {
+	switch c.Change {
+	case "added":
+		return fmt.Sprintf("+ %s: %s", c.Field, c.New)
+	case "removed":
+		return fmt.Sprintf("- %s: %s", c.Field, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s → %s", c.Field, orDash(c.Old), orDash(c.New))
+	}
+}
+
+// runsDiffResult is the JSON output of `tntc runs diff`.
+type runsDiffResult struct {
+	From    *runRecord      `json:"from"`
+	To      *runRecord      `json:"to"`
+	Changes []runDiffChange `json:"changes"`
+}
+
+func runRunsDiff(cmd *cobra.Command, args []string) error {
+	from, err := lookupRun(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	to, err := lookupRun(cmd, args[1])
+	if err != nil {
+		return err
+	}
+	result := runsDiffResult{From: from, To: to, Changes: diffRuns(from, to)}
+
+	if flagString(cmd, "output") == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+	writeRunsDiffText(cmd.OutOrStdout(), &result)
+	return nil
+}
+
+// diffRuns compares run metadata and every leaf of the workflow output.
+// Durations always differ and are reported separately.
+func diffRuns(from, to *runRecord) []runDiffChange {
+	changes := []runDiffChange{}
+	fields := []struct{ name, old, new string }{
+		{"workflow", from.Workflow, to.Workflow},
+		{"cluster", from.Cluster, to.Cluster},
+		{"namespace", from.Namespace, to.Namespace},
+		{"result", runResultLabel(from), runResultLabel(to)},
+		{"error", from.Error, to.Error},
+		{"inputHash", from.InputHash, to.InputHash},
+	}
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, runDiffChange{Field: f.name, Change: "changed", Old: f.old, New: f.new})
+		}
+	}
+
+	oldOut, newOut := flattenRunOutput(from.Output), flattenRunOutput(to.Output)
+	paths := make([]string, 0, len(oldOut)+len(newOut))
+	for p := range oldOut {
+		paths = append(paths, p)
+	}
+	for p := range newOut {
+		if _, ok := oldOut[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		oldV, inOld := oldOut[p]
+		newV, inNew := newOut[p]
+		switch {
+		case !inOld:
+			changes = append(changes, runDiffChange{Field: p, Change: "added", New: newV})
+		case !inNew:
+			changes = append(changes, runDiffChange{Field: p, Change: "removed", Old: oldV})
+		case oldV != newV:
+			changes = append(changes, runDiffChange{Field: p, Change: "changed", Old: oldV, New: newV})
+		}
+	}
+	return changes
+}
+
+// flattenRunOutput maps every leaf of a JSON output to its path, e.g.
+// "output.items[0].title". Output that is not JSON is a single leaf.
+func flattenRunOutput(output json.RawMessage) map[string]string {
+	leaves := map[string]string{}
+	if len(output) == 0 || string(output) == "null" {
+		return leaves
+	}
+	var v any
+	if err := json.Unmarshal(output, &v); err != nil {
+		leaves["output"] = string(output)
+		return leaves
+	}
+	flattenJSON("output", v, leaves)
+	return leaves
+}
+
+func flattenJSON(path string, v any, leaves map[string]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			leaves[path] = "{}"
+		}
+		for k, child := range val {
+			flattenJSON(path+"."+k, child, leaves)
+		}
+	case []any:
+		if len(val) == 0 {
+			leaves[path] = "[]"
+		}
+		for i, child := range val {
+			flattenJSON(path+"["+strconv.Itoa(i)+"]", child, leaves)
+		}
+	default:
+		data, _ := json.Marshal(val)
+		leaves[path] = string(data)
+	}
+}
+
+func writeRunsDiffText(w io.Writer, result *runsDiffResult) {
+	_, _ = fmt.Fprintf(w, "--- %s (%s, %s)\n", result.From.ID, result.From.Workflow, formatRunTime(result.From.StartedAt))
+	_, _ = fmt.Fprintf(w, "+++ %s (%s, %s)\n", result.To.ID, result.To.Workflow, formatRunTime(result.To.StartedAt))
+	for _, c := range result.Changes {
+		_, _ = fmt.Fprintln(w, c)
+	}
+	if len(result.Changes) == 0 {
+		_, _ = fmt.Fprintln(w, "No differences in result, input or output")
+	}
+	_, _ = fmt.Fprintf(w, "Duration: %dms → %dms\n", result.From.DurationMs, result.To.DurationMs)
+}
@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// runRecordLayout prefixes run record IDs so they sort chronologically.
+const runRecordLayout = "20060102T150405Z"
+
+// runHistoryLimit is the number of records kept per workflow; older ones are
+// pruned when a new run is recorded.
+const runHistoryLimit = 100
+
+// Run triggers recorded in runRecord.Trigger.
+const (
+	runTriggerRun          = "run"
+	runTriggerTest         = "test"
+	runTriggerDeployGate   = "deploy-gate"
+	runTriggerDeployVerify = "deploy-verify"
+)
+
+// Run record sources reported by `tntc runs list`.
+const (
+	runSourceLocal  = "local"
+	runSourceServer = "server"
+)
+
+// runRecord is a single workflow execution stored under ~/.tentacular/runs/.
+type runRecord struct {
+	ID          string           `json:"id"`
+	ExecutionID string           `json:"executionId,omitempty"`
+	Workflow    string           `json:"workflow"`
+	Namespace   string           `json:"namespace"`
+	Cluster     string           `json:"cluster,omitempty"`
+	Trigger     string           `json:"trigger,omitempty"`
+	Source      string           `json:"source,omitempty"`
+	StartedAt   time.Time        `json:"startedAt"`
+	DurationMs  int64            `json:"durationMs"`
+	Success     bool             `json:"success"`
+	State       string           `json:"state,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	InputHash   string           `json:"inputHash,omitempty"`
+	Output      json.RawMessage  `json:"output,omitempty"`
+	Nodes       []mcp.NodeTiming `json:"nodes,omitempty"`
+}
+
+// resolveRunsDir returns the run history directory under ~/.tentacular/.
+func resolveRunsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".tentacular", "runs") // last resort
+	}
+	return filepath.Join(home, ".tentacular", "runs")
+}
+
+// newRunRecordID returns a sortable, collision-resistant record ID.
+func newRunRecordID(at time.Time) string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return at.UTC().Format(runRecordLayout) + "-" + hex.EncodeToString(b[:])
+}
+
+// inputHash returns a short content hash of a run input so runs can be
+// compared without storing the payload. Empty input hashes to "".
+func inputHash(input json.RawMessage) string {
+	if len(input) == 0 {
+		return ""
+	}
+	var compact bytes.Buffer
+	data := []byte(input)
+	if json.Compact(&compact, data) == nil {
+		data = compact.Bytes()
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
+
+// runOutcome reports whether a finished execution succeeded: the server did
+// not mark it failed and the workflow output does not carry success=false.
+func runOutcome(result *mcp.WfRunResult) (ok bool, reason string) {
+	if result.Status == mcp.RunStatusFailed {
+		reason = "failed"
+		if result.Error != "" {
+			reason += ": " + result.Error
+		}
+		return false, reason
+	}
+	var output map[string]any
+	if json.Unmarshal(result.Output, &output) == nil {
+		if success, isBool := output["success"].(bool); isBool && !success {
+			return false, "returned success=false"
+		}
+	}
+	return true, ""
+}
+
+// newRunRecord builds a record from a wf_run/wf_run_status result, or from the
+// error returned instead of one.
+func newRunRecord(result *mcp.WfRunResult, runErr error, namespace, name string, input json.RawMessage, startedAt time.Time) runRecord {
+	rec := runRecord{
+		ID:        newRunRecordID(startedAt),
+		Workflow:  name,
+		Namespace: namespace,
+		StartedAt: startedAt.UTC(),
+		InputHash: inputHash(input),
+	}
+	if runErr != nil {
+		rec.State = mcp.RunStatusFailed
+		rec.Error = runErr.Error()
+		rec.DurationMs = time.Since(startedAt).Milliseconds()
+		return rec
+	}
+	rec.ExecutionID = result.ExecutionID
+	rec.State = result.Status
+	rec.Error = result.Error
+	rec.Output = result.Output
+	rec.Nodes = result.Nodes
+	rec.DurationMs = result.DurationMs
+	if !result.Running() {
+		rec.Success, _ = runOutcome(result)
+		if rec.State == "" {
+			rec.State = mcp.RunStatusSucceeded
+			if !rec.Success {
+				rec.State = mcp.RunStatusFailed
+			}
+		}
+	}
+	return rec
+}
+
+// recordRun stores a run in the local history. Failures only warn: history
+// is a convenience and must never fail the command that ran the workflow.
+// A record for the same execution (started with --async) is updated in place.
+func recordRun(cmd *cobra.Command, trigger string, result *mcp.WfRunResult, runErr error, namespace, name string, input json.RawMessage, startedAt time.Time) {
+	rec := newRunRecord(result, runErr, namespace, name, input, startedAt)
+	rec.Trigger = trigger
+	rec.Cluster = activeClusterName(cmd, LoadConfig())
+
+	dir := resolveRunsDir()
+	if rec.ExecutionID != "" {
+		if prev, err := findRunByExecution(dir, rec.ExecutionID, rec.Namespace, rec.Cluster); err == nil && prev != nil {
+			rec.ID = prev.ID
+			rec.Trigger = prev.Trigger
+			rec.StartedAt = prev.StartedAt
+			if rec.InputHash == "" {
+				rec.InputHash = prev.InputHash
+			}
+		}
+	}
+	if err := saveRunRecord(dir, rec); err != nil {
+		_, _ = fmt.Fprintf(StatusWriter(cmd), "Warning: recording run history: %v\n", err)
+	}
+}
+
+// saveRunRecord writes rec to dir/<id>.json and prunes the workflow's
+// history to runHistoryLimit records. Records may hold workflow output, so
+// they are readable by the owner only.
+func saveRunRecord(dir string, rec runRecord) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating run history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, rec.ID+".json"), data, 0o600); err != nil {
+		return fmt.Errorf("writing run record: %w", err)
+	}
+
+	records, err := listRunRecords(dir, rec.Workflow)
+	if err != nil {
+		return err
+	}
+	for _, old := range records[min(len(records), runHistoryLimit):] {
+		_ = os.Remove(filepath.Join(dir, old.ID+".json"))
+	}
+	return nil
+}
+
+// listRunRecords returns the stored records for workflow (all workflows when
+// empty), newest first. Unreadable files are skipped.
+func listRunRecords(dir, workflow string) ([]runRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading run history: %w", err)
+	}
+	var records []runRecord
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		rec, readErr := readRunRecord(filepath.Join(dir, e.Name()))
+		if readErr != nil || (workflow != "" && rec.Workflow != workflow) {
+			continue
+		}
+		records = append(records, *rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].StartedAt.Equal(records[j].StartedAt) {
+			return records[i].StartedAt.After(records[j].StartedAt)
+		}
+		return records[i].ID > records[j].ID
+	})
+	return records, nil
+}
+
+func readRunRecord(path string) (*runRecord, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path within the run history directory
+	if err != nil {
+		return nil, err
+	}
+	var rec runRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	// Records are stored indented; restore the output as the engine returned it.
+	var compact bytes.Buffer
+	if len(rec.Output) > 0 && json.Compact(&compact, rec.Output) == nil {
+		rec.Output = compact.Bytes()
+	}
+	rec.Source = runSourceLocal
+	return &rec, nil
+}
+
+// findRunRecord looks up a record by record ID or execution ID. An execution
+// ID shared by several records (e.g. on different clusters) is ambiguous.
+// Returns nil when nothing matches.
+func findRunRecord(dir, id string) (*runRecord, error) {
+	if rec, err := readRunRecord(filepath.Join(dir, filepath.Base(id)+".json")); err == nil {
+		return rec, nil
+	}
+	records, err := listRunRecords(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	var matches []runRecord
+	for _, rec := range records {
+		if rec.ExecutionID == id {
+			matches = append(matches, rec)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return nil, fmt.Errorf("execution ID %s matches several runs; use a run ID: %s", id, strings.Join(ids, ", "))
+}
+
+// findRunByExecution returns the record for an execution on a given
+// namespace and cluster, or nil.
+func findRunByExecution(dir, executionID, namespace, cluster string) (*runRecord, error) {
+	records, err := listRunRecords(dir, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		if rec.ExecutionID == executionID && rec.Namespace == namespace && rec.Cluster == cluster {
+			return &rec, nil
+		}
+	}
+	return nil, nil
+}
+
+// runRecordFromServer converts an execution reported by the MCP server.
+func runRecordFromServer(result *mcp.WfRunResult, cluster string) runRecord {
+	rec := newRunRecord(result, nil, result.Namespace, result.Name, nil, time.Time{})
+	rec.ID = result.ExecutionID
+	rec.Cluster = cluster
+	rec.Source = runSourceServer
+	rec.StartedAt = time.Time{}
+	if t, err := time.Parse(time.RFC3339, result.StartedAt); err == nil {
+		rec.StartedAt = t.UTC()
+	}
+	return rec
+}
+
+// writeRunRecordText prints a single run in detail.
+func writeRunRecordText(w io.Writer, rec *runRecord) {
+	_, _ = fmt.Fprintf(w, "Run:         %s\n", rec.ID)
+	if rec.ExecutionID != "" && rec.ExecutionID != rec.ID {
+		_, _ = fmt.Fprintf(w, "Execution:   %s\n", rec.ExecutionID)
+	}
+	_, _ = fmt.Fprintf(w, "Workflow:    %s\n", rec.Workflow)
+	_, _ = fmt.Fprintf(w, "Namespace:   %s\n", rec.Namespace)
+	_, _ = fmt.Fprintf(w, "Cluster:     %s\n", orDash(rec.Cluster))
+	_, _ = fmt.Fprintf(w, "Trigger:     %s\n", orDash(rec.Trigger))
+	_, _ = fmt.Fprintf(w, "Started:     %s\n", formatRunTime(rec.StartedAt))
+	_, _ = fmt.Fprintf(w, "Duration:    %dms\n", rec.DurationMs)
+	_, _ = fmt.Fprintf(w, "Result:      %s\n", runResultLabel(rec))
+	if rec.Error != "" {
+		_, _ = fmt.Fprintf(w, "Error:       %s\n", rec.Error)
+	}
+	_, _ = fmt.Fprintf(w, "Input hash:  %s\n", orDash(rec.InputHash))
+	printRunDetails(w, runExecution{Nodes: rec.Nodes, Output: rec.Output})
+}
+
+func runResultLabel(rec *runRecord) string {
+	switch {
+	case rec.State == mcp.RunStatusRunning:
+		return "running"
+	case rec.Success:
+		return "success"
+	default:
+		return "failed"
+	}
+}
+
+func formatRunTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
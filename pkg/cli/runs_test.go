@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func runRunsCmd(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := withRootFlags(NewRunsCmd())
+	cmd.SetArgs(args)
+	var err error
+	out := captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	if err != nil {
+		t.Fatalf("runs %v: %v", args, err)
+	}
+	return out
+}
+
+func TestRunRecordStore_ListNewestFirstAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := range runHistoryLimit + 2 {
+		at := base.Add(time.Duration(i) * time.Second)
+		rec := runRecord{ID: newRunRecordID(at), Workflow: "wf", Namespace: "default", StartedAt: at}
+		if err := saveRunRecord(dir, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := saveRunRecord(dir, runRecord{ID: newRunRecordID(base), Workflow: "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := listRunRecords(dir, "wf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != runHistoryLimit {
+		t.Fatalf("expected history pruned to %d, got %d", runHistoryLimit, len(records))
+	}
+	if !strings.HasPrefix(records[0].ID, "20260102T030546Z") {
+		t.Errorf("expected newest record first, got %s", records[0].ID)
+	}
+	if other, _ := listRunRecords(dir, "other"); len(other) != 1 || other[0].Source != runSourceLocal {
+		t.Errorf("expected other workflow untouched, got %+v", other)
+	}
+}
+
+func TestInputHash_IgnoresWhitespace(t *testing.T) {
+	a := inputHash(json.RawMessage(`{"a": 1, "b": [1, 2]}`))
+	b := inputHash(json.RawMessage(`{"a":1,"b":[1,2]}`))
+	if a == "" || a != b {
+		t.Errorf("expected equal hashes, got %q and %q", a, b)
+	}
+	if inputHash(nil) != "" {
+		t.Error("expected empty hash for no input")
+	}
+}
+
+func TestDiffRuns_ReportsOutputAndMetadataChanges(t *testing.T) {
+	from := &runRecord{Workflow: "wf", Namespace: "dev", Success: true, InputHash: "sha256:aaa",
+		Output: json.RawMessage(`{"success":true,"items":[{"title":"a"}],"gone":1}`)}
+	to := &runRecord{Workflow: "wf", Namespace: "dev", Success: false, InputHash: "sha256:aaa",
+		Output: json.RawMessage(`{"success":false,"items":[{"title":"b"}],"new":"x"}`)}
+
+	var lines []string
+	for _, c := range diffRuns(from, to) {
+		lines = append(lines, c.String())
+	}
+	want := []string{
+		"~ result: success → failed",
+		"- output.gone: 1",
+		"~ output.items[0].title: \"a\" → \"b\"",
+		"+ output.new: \"x\"",
+		"~ output.success: true → false",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if len(diffRuns(from, from)) != 0 {
+		t.Error("expected no changes comparing a run with itself")
+	}
+}
+
+func TestRuns_RunIsRecordedAndListed(t *testing.T) {
+	deployRunFixture(t)
+	if _, err := runRunCmd(t, `{"url":"https://example.com"}`, "test-workflow", "--input", "-"); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	out := runRunsCmd(t, "list", "test-workflow", "-o", "json")
+	var records []runRecord
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	// The server reports the same execution; it must not be listed twice.
+	if len(records) != 1 {
+		t.Fatalf("expected 1 run, got %+v", records)
+	}
+	rec := records[0]
+	if rec.Source != runSourceLocal || rec.Trigger != runTriggerRun || !rec.Success {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if rec.InputHash != inputHash(json.RawMessage(`{"url":"https://example.com"}`)) || rec.ExecutionID == "" {
+		t.Errorf("expected input hash and execution ID recorded, got %+v", rec)
+	}
+}
+
+func TestRuns_ServerOnlyExecutionsListed(t *testing.T) {
+	srv := deployRunFixture(t)
+	if _, err := srv.Client().WfRun(context.Background(), "default", "test-workflow", nil, 30); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runRunsCmd(t, "list", "test-workflow", "--source", "server")
+	if !strings.Contains(out, "exec-0001") || !strings.Contains(out, runSourceServer) {
+		t.Errorf("expected server execution listed, got %q", out)
+	}
+	if out := runRunsCmd(t, "list", "test-workflow", "--source", "local"); !strings.Contains(out, "No runs recorded") {
+		t.Errorf("expected no local runs, got %q", out)
+	}
+}
+
+func TestRuns_AsyncRecordCompletedByWait(t *testing.T) {
+	deployRunFixture(t)
+	if _, err := runRunCmd(t, `{"url":"https://example.com"}`, "test-workflow", "--input", "-", "--async"); err != nil {
+		t.Fatalf("run --async: %v", err)
+	}
+	records, _ := listRunRecords(resolveRunsDir(), "test-workflow")
+	if len(records) != 1 || records[0].State != mcp.RunStatusRunning {
+		t.Fatalf("expected one running record, got %+v", records)
+	}
+
+	if _, err := runRunCmd(t, "", "wait", records[0].ExecutionID); err != nil {
+		t.Fatalf("run wait: %v", err)
+	}
+	after, _ := listRunRecords(resolveRunsDir(), "test-workflow")
+	if len(after) != 1 || after[0].ID != records[0].ID {
+		t.Fatalf("expected the async record updated in place, got %+v", after)
+	}
+	if !after[0].Success || after[0].InputHash != records[0].InputHash {
+		t.Errorf("expected finished record to keep its input hash, got %+v", after[0])
+	}
+}
+
+func TestRuns_ShowAndDiff(t *testing.T) {
+	srv := deployRunFixture(t)
+	if _, err := runRunCmd(t, "", "test-workflow"); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetRunOutput("default", "test-workflow", json.RawMessage(`{"success":true,"count":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runRunCmd(t, "", "test-workflow"); err != nil {
+		t.Fatal(err)
+	}
+	records, _ := listRunRecords(resolveRunsDir(), "test-workflow")
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	out := runRunsCmd(t, "show", records[0].ExecutionID)
+	if !strings.Contains(out, "Run:         "+records[0].ID) || !strings.Contains(out, `"count":2`) {
+		t.Errorf("unexpected show output: %q", out)
+	}
+
+	out = runRunsCmd(t, "diff", records[1].ID, records[0].ID)
+	if !strings.Contains(out, "+ output.count: 2") {
+		t.Errorf("expected output change in diff, got %q", out)
+	}
+}
+
+func TestRuns_ShowFallsBackToServer(t *testing.T) {
+	srv := deployRunFixture(t)
+	result, err := srv.Client().WfRun(context.Background(), "default", "test-workflow", nil, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := runRunsCmd(t, "show", result.ExecutionID, "-o", "json")
+	var rec runRecord
+	if err := json.Unmarshal([]byte(out), &rec); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if rec.Source != runSourceServer || rec.ExecutionID != result.ExecutionID {
+		t.Errorf("expected server record, got %+v", rec)
+	}
+
+	cmd := withRootFlags(NewRunsCmd())
+	cmd.SetArgs([]string{"show", "exec-9999"})
+	if err := cmd.ExecuteContext(context.Background()); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
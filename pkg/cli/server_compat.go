@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// serverCompatTimeout bounds the capability probe so reporting commands stay
+// responsive when the server is slow or unreachable.
+const serverCompatTimeout = 10 * time.Second
+
+// serverCompatibility negotiates with the MCP server and compares its
+// advertised tools and version against what this CLI needs.
+func serverCompatibility(cmd *cobra.Command, client *mcp.Client) (*mcp.Compatibility, error) {
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, serverCompatTimeout)
+	defer cancel()
+
+	caps, err := client.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	compat := mcp.CheckCompatibility(caps)
+	return &compat, nil
+}
+
+// printServerCompat writes a human-readable compatibility summary.
+func printServerCompat(w io.Writer, compat *mcp.Compatibility) {
+	name := compat.ServerName
+	if name == "" {
+		name = "MCP server"
+	}
+	version := compat.ServerVersion
+	if version == "" {
+		version = "unknown version"
+	}
+	state := "compatible"
+	if !compat.Compatible {
+		state = "INCOMPATIBLE"
+	}
+	_, _ = fmt.Fprintf(w, "MCP Server:  %s %s (%s, %d tools, needs >= %s)\n",
+		name, version, state, compat.ToolCount, compat.MinServerVersion)
+	for _, p := range compat.Problems {
+		_, _ = fmt.Fprintf(w, "  ✗ %s\n", p)
+	}
+	if len(compat.UnavailableTools) > 0 {
+		unavailable := make([]string, 0, len(compat.UnavailableTools))
+		for _, tool := range compat.UnavailableTools {
+			if feature := mcp.ToolFeature(tool); feature != "" {
+				tool += " (" + feature + ")"
+			}
+			unavailable = append(unavailable, tool)
+		}
+		_, _ = fmt.Fprintf(w, "  ⚠ features unavailable on this server: %s\n", strings.Join(unavailable, ", "))
+	}
+}
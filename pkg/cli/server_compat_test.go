@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp/mcptest"
+)
+
+func TestClusterCheck_FailsOnServerTooOld(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	srv.RemoveTools("wf_logs")
+	cleanup := setupMCPEnv(t, srv.URL())
+	defer cleanup()
+
+	cmd := withRootFlags(NewClusterCmd())
+	cmd.SetArgs([]string{"check"})
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	var err error
+	out := captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	if err == nil || !strings.Contains(err.Error(), "server too old") || !strings.Contains(err.Error(), "wf_logs") {
+		t.Fatalf("expected server too old error naming wf_logs, got %v", err)
+	}
+	if !strings.Contains(out, "INCOMPATIBLE") {
+		t.Errorf("expected compatibility report, got:\n%s", out)
+	}
+	if srv.Calls("enclave_preflight") != 0 {
+		t.Error("preflight should not run against an incompatible server")
+	}
+}
+
+func TestClusterCheck_ReportsServerInJSON(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	cleanup := setupMCPEnv(t, srv.URL())
+	defer cleanup()
+
+	cmd := withRootFlags(NewClusterCmd())
+	cmd.SetArgs([]string{"check", "-o", "json"})
+	var err error
+	out := captureStdout(t, func() { err = cmd.ExecuteContext(context.Background()) })
+	if err != nil {
+		t.Fatalf("cluster check: %v", err)
+	}
+	var parsed struct {
+		Server struct {
+			ServerName string `json:"server_name"`
+			Compatible bool   `json:"compatible"`
+		} `json:"server"`
+		AllPass bool `json:"allPass"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !parsed.AllPass || !parsed.Server.Compatible || parsed.Server.ServerName != "tentacular-mcp-fake" {
+		t.Errorf("unexpected cluster check JSON: %s", out)
+	}
+}
+
+func TestWhoami_ReportsServerCompatibility(t *testing.T) {
+	srv := mcptest.NewServer(t)
+	srv.RemoveTools("wf_run")
+	cleanup := setupWhoamiEnv(t, &OIDCTokenStore{
+		AccessToken: buildTestJWT(map[string]any{"sub": "u", "email": "a@example.com", "exp": float64(time.Now().Add(time.Hour).Unix())}),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	defer cleanup()
+	t.Setenv("TNTC_MCP_ENDPOINT", srv.URL())
+
+	cmd := NewWhoamiCmd()
+	cmd.PersistentFlags().StringP("cluster", "c", "", "Target cluster")
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format")
+	_ = cmd.PersistentFlags().Set("output", "json")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var result whoamiResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if result.Server == nil || result.Server.Compatible || len(result.Server.MissingTools) != 1 || result.Server.MissingTools[0] != "wf_run" {
+		t.Errorf("expected server report missing wf_run, got %+v (error %q)", result.Server, result.ServerErr)
+	}
+}
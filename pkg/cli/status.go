@@ -3,18 +3,31 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
 )
 
 func NewStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status <name>",
 		Short: "Check deployment status",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runStatus,
+		Long: `Check deployment status.
+
+With --watch the status is re-queried every --interval and replica, pod phase
+and event changes are printed as they happen. The command exits successfully
+once the rollout is ready, and fails if a pod reports CrashLoopBackOff,
+ImagePullBackOff or a similar error, or when --timeout elapses.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runStatus,
 	}
 	cmd.Flags().Bool("detail", false, "Show detailed status including pods, events, and resource limits")
+	cmd.Flags().BoolP("watch", "w", false, "Watch the rollout until it is ready or fails")
+	cmd.Flags().Duration("interval", watchPollInterval, "Polling interval for --watch")
+	cmd.Flags().Duration("timeout", defaultWatchTimeout, "Maximum time to --watch (0 for no limit)")
+	addDirectFlags(cmd)
 	return cmd
 }
 
@@ -24,17 +37,19 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	output, _ := cmd.Flags().GetString("output")
 	detail, _ := cmd.Flags().GetBool("detail")
 
-	mcpClient, err := requireMCPClient(cmd)
+	client, err := resolveWorkflowClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	status, err := mcpClient.WfStatus(cmd.Context(), namespace, name, detail)
+	watch, _ := cmd.Flags().GetBool("watch")
+	if watch {
+		return watchStatus(cmd, client, namespace, name, output)
+	}
+
+	status, err := client.WfStatus(cmd.Context(), namespace, name, detail)
 	if err != nil {
-		if hint := mcpErrorHint(err); hint != "" {
-			return fmt.Errorf("getting status: %w\n  hint: %s", err, hint)
-		}
-		return fmt.Errorf("getting status: %w", err)
+		return statusError(err)
 	}
 
 	if output == "json" {
@@ -46,36 +61,72 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Text output
+	writeStatusText(cmd.OutOrStdout(), status, detail)
+	return nil
+}
+
+// watchStatus follows the rollout, printing transitions (to stderr with
+// -o json), then prints the final status.
+func watchStatus(cmd *cobra.Command, client mcp.WorkflowClient, namespace, name, output string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	w := cmd.OutOrStdout()
+	if output == "json" {
+		w = StatusWriter(cmd)
+	}
+
+	status, watchErr := watchRollout(cmd.Context(), client, namespace, name, interval, timeout, w)
+	if status == nil {
+		return statusError(watchErr)
+	}
+	if output == "json" {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	} else {
+		_, _ = fmt.Fprintln(w)
+		writeStatusText(w, status, false)
+	}
+	return watchErr
+}
+
+func statusError(err error) error {
+	if hint := mcpErrorHint(err); hint != "" {
+		return fmt.Errorf("getting status: %w\n  hint: %s", err, hint)
+	}
+	return fmt.Errorf("getting status: %w", err)
+}
+
+func writeStatusText(w io.Writer, status *mcp.WfStatusResult, detail bool) {
 	readyStr := "not ready"
 	if status.Ready {
 		readyStr = "ready"
 	}
-	fmt.Printf("Name:      %s\n", status.Name)
-	fmt.Printf("Namespace: %s\n", status.Namespace)
+	_, _ = fmt.Fprintf(w, "Name:      %s\n", status.Name)
+	_, _ = fmt.Fprintf(w, "Namespace: %s\n", status.Namespace)
 	if status.Version != "" {
-		fmt.Printf("Version:   %s\n", status.Version)
+		_, _ = fmt.Fprintf(w, "Version:   %s\n", status.Version)
 	}
-	fmt.Printf("Status:    %s\n", readyStr)
-	fmt.Printf("Replicas:  %d/%d\n", status.Available, status.Replicas)
+	_, _ = fmt.Fprintf(w, "Status:    %s\n", readyStr)
+	_, _ = fmt.Fprintf(w, "Replicas:  %d/%d\n", status.Available, status.Replicas)
 
 	if detail && len(status.Pods) > 0 {
-		fmt.Println("\nPods:")
+		_, _ = fmt.Fprintln(w, "\nPods:")
 		for _, pod := range status.Pods {
 			podReady := "not ready"
 			if pod.Ready {
 				podReady = "ready"
 			}
-			fmt.Printf("  %-40s %-12s %s\n", pod.Name, pod.Phase, podReady)
+			_, _ = fmt.Fprintf(w, "  %-40s %-12s %s\n", pod.Name, pod.Phase, podReady)
 		}
 	}
 
 	if detail && len(status.Events) > 0 {
-		fmt.Println("\nEvents:")
+		_, _ = fmt.Fprintln(w, "\nEvents:")
 		for _, evt := range status.Events {
-			fmt.Printf("  [%s] %s: %s (x%d)\n", evt.Type, evt.Reason, evt.Message, evt.Count)
+			_, _ = fmt.Fprintf(w, "  [%s] %s: %s (x%d)\n", evt.Type, evt.Reason, evt.Message, evt.Count)
 		}
 	}
-
-	return nil
 }
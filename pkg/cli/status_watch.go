@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// watchPollInterval is the default interval between status polls for
+// `status --watch` and `deploy --wait`.
+var watchPollInterval = 2 * time.Second
+
+// defaultWatchTimeout bounds how long a rollout is watched.
+const defaultWatchTimeout = 5 * time.Minute
+
+// rolloutFailureReasons are container states a rollout will not recover from
+// without a new deploy.
+var rolloutFailureReasons = []string{
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+}
+
+// errRolloutTimeout is returned when a watched rollout is not ready in time.
+var errRolloutTimeout = errors.New("timed out waiting for rollout")
+
+// watchRollout polls wf_status until the workflow's rollout completes, a pod
+// reports a failure reason, or timeout elapses. Replica, pod and event
+// changes are printed to w as they are observed. The last status seen is
+// returned with any error.
+func watchRollout(ctx context.Context, client mcp.WorkflowClient, namespace, name string, interval, timeout time.Duration, w io.Writer) (*mcp.WfStatusResult, error) {
+	if interval <= 0 {
+		interval = watchPollInterval
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var prev *mcp.WfStatusResult
+	for {
+		status, err := client.WfStatus(ctx, namespace, name, true)
+		if ctx.Err() != nil {
+			return prev, watchContextError(ctx, timeout)
+		}
+		if err != nil {
+			return prev, fmt.Errorf("getting status: %w", err)
+		}
+		writeStatusTransitions(w, prev, status, time.Now())
+		prev = status
+
+		if pod, reason := failedPod(status); reason != "" {
+			return status, fmt.Errorf("rollout failed: pod %s is in %s", pod, reason)
+		}
+		if status.RolloutComplete() {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return prev, watchContextError(ctx, timeout)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func watchContextError(ctx context.Context, timeout time.Duration) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", errRolloutTimeout, timeout)
+	}
+	return ctx.Err()
+}
+
+// failedPod returns the first pod whose container reason is a rollout failure.
+func failedPod(status *mcp.WfStatusResult) (pod, reason string) {
+	for _, p := range status.Pods {
+		if slices.Contains(rolloutFailureReasons, p.Reason) {
+			return p.Name, p.Reason
+		}
+	}
+	return "", ""
+}
+
+// writeStatusTransitions prints what changed between two status snapshots;
+// with no previous snapshot the current state is printed in full.
+func writeStatusTransitions(w io.Writer, prev, cur *mcp.WfStatusResult, at time.Time) {
+	stamp := at.Format("15:04:05")
+	line := func(format string, args ...any) {
+		_, _ = fmt.Fprintf(w, "%s  "+format+"\n", append([]any{stamp}, args...)...)
+	}
+
+	if prev == nil || prev.Available != cur.Available || prev.Replicas != cur.Replicas || prev.Updated != cur.Updated {
+		progress := fmt.Sprintf("replicas %d/%d available", cur.Available, cur.Replicas)
+		if cur.Generation > 0 {
+			progress += fmt.Sprintf(", %d updated", cur.Updated)
+		}
+		line("%s", progress)
+	}
+
+	prevPods := map[string]mcp.PodInfo{}
+	if prev != nil {
+		for _, p := range prev.Pods {
+			prevPods[p.Name] = p
+		}
+	}
+	for _, p := range cur.Pods {
+		old, seen := prevPods[p.Name]
+		delete(prevPods, p.Name)
+		if seen && old == p {
+			continue
+		}
+		state := podState(p)
+		if seen && podState(old) != state {
+			line("pod %s: %s -> %s", p.Name, podState(old), state)
+		} else if !seen {
+			line("pod %s: %s", p.Name, state)
+		}
+	}
+	if prev != nil {
+		for _, p := range prev.Pods {
+			if _, gone := prevPods[p.Name]; gone {
+				line("pod %s: deleted", p.Name)
+			}
+		}
+	}
+
+	prevEvents := map[mcp.EventInfo]int32{}
+	if prev != nil {
+		for _, e := range prev.Events {
+			prevEvents[eventKey(e)] = e.Count
+		}
+	}
+	for _, e := range cur.Events {
+		if count, seen := prevEvents[eventKey(e)]; seen && count >= e.Count {
+			continue
+		}
+		line("event [%s] %s: %s (x%d)", e.Type, e.Reason, e.Message, e.Count)
+	}
+
+	if prev != nil && !prev.Ready && cur.Ready {
+		line("workflow ready")
+	}
+}
+
+// podState summarises a pod as phase, readiness, reason and restarts.
+func podState(p mcp.PodInfo) string {
+	state := p.Phase
+	if p.Ready {
+		state += ", ready"
+	} else {
+		state += ", not ready"
+	}
+	if p.Reason != "" {
+		state += " (" + p.Reason + ")"
+	}
+	if p.Restarts > 0 {
+		state += fmt.Sprintf(", %d restarts", p.Restarts)
+	}
+	return state
+}
+
+// eventKey identifies an event across polls regardless of its count.
+func eventKey(e mcp.EventInfo) mcp.EventInfo {
+	e.Count = 0
+	return e
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func runStatusWatch(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := withRootFlags(NewStatusCmd())
+	cmd.SetArgs(append([]string{"test-workflow", "--watch", "--interval", "10ms"}, args...))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestStatusWatch_ExitsWhenReady(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := srv.SetReady("default", "test-workflow", false); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = srv.SetReady("default", "test-workflow", true)
+	}()
+
+	out, err := runStatusWatch(t, "--timeout", "5s")
+	if err != nil {
+		t.Fatalf("status --watch: %v\n%s", err, out)
+	}
+	for _, want := range []string{"replicas 0/1 available", "Pending, not ready", "Pending, not ready -> Running, ready", "replicas 1/1 available", "workflow ready", "Status:    ready"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatusWatch_FailsOnCrashLoop(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := srv.SetPodReason("default", "test-workflow", "CrashLoopBackOff"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runStatusWatch(t)
+	if err == nil || !strings.Contains(err.Error(), "CrashLoopBackOff") {
+		t.Fatalf("expected crash loop failure, got %v", err)
+	}
+	if !strings.Contains(out, "(CrashLoopBackOff), 1 restarts") {
+		t.Errorf("expected pod reason in output:\n%s", out)
+	}
+}
+
+func TestStatusWatch_Timeout(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := srv.SetReady("default", "test-workflow", false); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := runStatusWatch(t, "--timeout", "50ms")
+	if !errors.Is(err, errRolloutTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
+
+func TestWriteStatusTransitions_OnlyChanges(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	prev := &mcp.WfStatusResult{
+		Replicas: 1,
+		Pods:     []mcp.PodInfo{{Name: "wf-old", Phase: "Running", Ready: true}},
+		Events:   []mcp.EventInfo{{Type: "Normal", Reason: "Pulled", Message: "pulled", Count: 1}},
+	}
+	cur := &mcp.WfStatusResult{
+		Replicas: 1,
+		Pods:     []mcp.PodInfo{{Name: "wf-new", Phase: "Pending"}},
+		Events: []mcp.EventInfo{
+			{Type: "Normal", Reason: "Pulled", Message: "pulled", Count: 1},
+			{Type: "Warning", Reason: "BackOff", Message: "back-off", Count: 2},
+		},
+	}
+
+	var buf bytes.Buffer
+	writeStatusTransitions(&buf, prev, cur, at)
+	want := "12:00:00  pod wf-new: Pending, not ready\n" +
+		"12:00:00  pod wf-old: deleted\n" +
+		"12:00:00  event [Warning] BackOff: back-off (x2)\n"
+	if buf.String() != want {
+		t.Errorf("unexpected transitions:\n%s", buf.String())
+	}
+}
+
+func TestRolloutComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		status mcp.WfStatusResult
+		want   bool
+	}{
+		{"not ready", mcp.WfStatusResult{Replicas: 1}, false},
+		{"ready without progress fields", mcp.WfStatusResult{Replicas: 1, Available: 1, Ready: true}, true},
+		{"generation not observed", mcp.WfStatusResult{Replicas: 1, Available: 1, Ready: true, Updated: 1, Generation: 3, ObservedGeneration: 2}, false},
+		{"old replicas remain", mcp.WfStatusResult{Replicas: 2, Available: 2, Ready: true, Updated: 1, Generation: 3, ObservedGeneration: 3}, false},
+		{"complete", mcp.WfStatusResult{Replicas: 2, Available: 2, Ready: true, Updated: 2, Generation: 3, ObservedGeneration: 3}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.RolloutComplete(); got != tt.want {
+				t.Errorf("RolloutComplete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
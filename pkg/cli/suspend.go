@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func NewSuspendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suspend <name>",
+		Short: "Scale a workflow to zero and pause its cron triggers",
+		Long: `Temporarily disable a deployed workflow without undeploying it: its
+Deployment is scaled to zero, its cron schedule is set aside so the scheduler
+stops firing runs, and it is annotated as suspended (shown by tntc list).
+Config, secrets and revision history are kept. tntc resume restores the
+previous replica count and the cron schedule.
+
+tntc deploy, rollback and promote refuse to apply over a suspended workflow,
+which would scale it back up; pass --resume to resume it first.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSuspend(cmd, args[0], true)
+		},
+	}
+	addDirectFlags(cmd)
+	return cmd
+}
+
+func NewResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume <name>",
+		Short: "Resume a suspended workflow",
+		Long: `Restore the replica count a workflow had when it was suspended (at least
+one), restore its cron schedule and remove the suspended annotations.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSuspend(cmd, args[0], false)
+		},
+	}
+	addDirectFlags(cmd)
+	return cmd
+}
+
+func runSuspend(cmd *cobra.Command, name string, suspend bool) error {
+	startedAt := time.Now().UTC()
+	namespace := resolveNamespace(cmd, ".")
+	command, verb := "resume", "resuming"
+	if suspend {
+		command, verb = "suspend", "suspending"
+	}
+
+	client, err := resolveWorkflowClient(cmd)
+	if err != nil {
+		return err
+	}
+	suspender, ok := client.(mcp.Suspender)
+	if !ok {
+		return fmt.Errorf("%s is not supported by this transport", command)
+	}
+
+	var result *mcp.WfSuspendResult
+	if suspend {
+		cfg := LoadConfig()
+		result, err = suspender.WfSuspend(cmd.Context(), namespace, name, resolveDeployerIdentity(activeClusterName(cmd, cfg)).String())
+	} else {
+		result, err = suspender.WfResume(cmd.Context(), namespace, name)
+	}
+	if err != nil {
+		if mcp.IsServerTooOld(err) {
+			return fmt.Errorf("%s %s: %w\n  hint: upgrade tentacular-mcp, or use --direct", verb, name, err)
+		}
+		if hint := mcpErrorHint(err); hint != "" {
+			return fmt.Errorf("%s %s: %w\n  hint: %s", verb, name, err, hint)
+		}
+		return fmt.Errorf("%s %s: %w", verb, name, err)
+	}
+	return EmitResult(cmd, CommandResult{
+		Version:   "1",
+		Command:   command,
+		Status:    "pass",
+		Summary:   suspendSummary(result, namespace),
+		Hints:     []string{},
+		Execution: result,
+		Timing: TimingInfo{
+			StartedAt:  startedAt.Format(time.RFC3339),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		},
+	}, cmd.OutOrStdout())
+}
+
+func suspendSummary(r *mcp.WfSuspendResult, namespace string) string {
+	var summary string
+	switch {
+	case r.Suspended && !r.Changed:
+		summary = fmt.Sprintf("%s in %s is already suspended", r.Name, namespace)
+	case r.Suspended:
+		summary = fmt.Sprintf("suspended %s in %s (scaled to 0)", r.Name, namespace)
+	case !r.Changed:
+		summary = fmt.Sprintf("%s in %s is not suspended", r.Name, namespace)
+	default:
+		summary = fmt.Sprintf("resumed %s in %s (%d replica(s))", r.Name, namespace, r.Replicas)
+	}
+	if r.CronSchedule != "" {
+		state := "restored"
+		if r.Suspended {
+			state = "paused"
+		}
+		summary += fmt.Sprintf("; %s cron schedule %s", state, r.CronSchedule)
+	}
+	return summary
+}
+
+// addResumeFlag adds --resume to a command that applies a workflow.
+func addResumeFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("resume", false, "Resume the workflow first if it is suspended (applying over a suspended workflow is refused otherwise)")
+}
+
+// checkNotSuspended refuses to apply over a suspended workflow: the applied
+// Deployment would scale it back up and restore its cron schedule while it
+// is still annotated as suspended. With resume the workflow is
+// resumed first. A failed lookup only warns; the apply reports a cluster
+// that cannot be reached.
+func checkNotSuspended(ctx context.Context, client mcp.WorkflowClient, namespace, name string, resume bool, w io.Writer) error {
+	items, err := client.WfList(ctx, namespace)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "WARNING: cannot check whether %s is suspended: %s\n", name, err)
+		return nil
+	}
+	for _, item := range items {
+		if item.Name != name || !item.Suspended {
+			continue
+		}
+		if !resume {
+			return fmt.Errorf("%s in %s is suspended (by %s since %s); run tntc resume %s first, or pass --resume",
+				name, namespace, orDash(item.SuspendedBy), orDash(item.SuspendedAt), name)
+		}
+		suspender, ok := client.(mcp.Suspender)
+		if !ok {
+			return fmt.Errorf("%s in %s is suspended and this transport cannot resume it", name, namespace)
+		}
+		result, err := suspender.WfResume(ctx, namespace, name)
+		if err != nil {
+			return fmt.Errorf("resuming %s: %w", name, err)
+		}
+		_, _ = fmt.Fprintln(w, suspendSummary(result, namespace))
+	}
+	return nil
+}
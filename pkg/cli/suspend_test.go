@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func TestSuspendAndResume(t *testing.T) {
+	srv := deployRunFixture(t)
+
+	out, err := runHistoryCmd(t, NewSuspendCmd, "test-workflow")
+	if err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+	if !strings.Contains(out, "suspended test-workflow in default (scaled to 0)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	wf, _ := srv.Workflow("default", "test-workflow")
+	if wf.Replicas != 0 || wf.Annotations[mcp.AnnotationSuspended] != "true" || wf.Annotations[mcp.AnnotationSuspendedBy] == "" {
+		t.Errorf("expected the workflow scaled to zero and annotated, got replicas %d, annotations %v", wf.Replicas, wf.Annotations)
+	}
+	list := captureStdout(t, func() {
+		if _, err := runHistoryCmd(t, NewListCmd); err != nil {
+			t.Errorf("list: %v", err)
+		}
+	})
+	if !strings.Contains(list, "suspended") {
+		t.Errorf("expected list to show the workflow suspended:\n%s", list)
+	}
+	if out, _ := runHistoryCmd(t, NewSuspendCmd, "test-workflow"); !strings.Contains(out, "already suspended") {
+		t.Errorf("expected a second suspend to be a no-op:\n%s", out)
+	}
+
+	out, err = runHistoryCmd(t, NewResumeCmd, "test-workflow")
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if !strings.Contains(out, "resumed test-workflow in default (1 replica(s))") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Replicas != 1 || wf.Annotations[mcp.AnnotationSuspended] != "" {
+		t.Errorf("expected the workflow resumed, got replicas %d, annotations %v", wf.Replicas, wf.Annotations)
+	}
+
+	srv.RemoveTools("wf_suspend")
+	if _, err := runHistoryCmd(t, NewSuspendCmd, "test-workflow"); err == nil || !strings.Contains(err.Error(), "upgrade tentacular-mcp") {
+		t.Errorf("expected an upgrade hint from an older server, got %v", err)
+	}
+}
+
+func TestDeploy_RefusesSuspendedWorkflow(t *testing.T) {
+	srv := deployRunFixture(t)
+	if err := runDeployCmd(t, "--image", "engine:1.0"); err != nil {
+		t.Fatalf("first deploy: %v", err)
+	}
+	if _, err := runHistoryCmd(t, NewSuspendCmd, "test-workflow"); err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+
+	if err := runDeployCmd(t, "--image", "engine:2.0"); err == nil || !strings.Contains(err.Error(), "is suspended") || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("expected deploy refused while suspended, got %v", err)
+	}
+	if _, err := runHistoryCmd(t, NewRollbackCmd, "test-workflow", "--to", "1"); err == nil || !strings.Contains(err.Error(), "is suspended") {
+		t.Errorf("expected rollback refused while suspended, got %v", err)
+	}
+	if wf, _ := srv.Workflow("default", "test-workflow"); wf.Image != "engine:1.0" || wf.Replicas != 0 {
+		t.Errorf("expected the workflow left suspended, got image %s, replicas %d", wf.Image, wf.Replicas)
+	}
+
+	if err := runDeployCmd(t, "--image", "engine:2.0", "--resume"); err != nil {
+		t.Fatalf("deploy --resume: %v", err)
+	}
+	wf, _ := srv.Workflow("default", "test-workflow")
+	if wf.Image != "engine:2.0" || wf.Replicas != 1 || wf.Annotations[mcp.AnnotationSuspended] != "" {
+		t.Errorf("expected the workflow resumed and deployed, got image %s, replicas %d, annotations %v", wf.Image, wf.Replicas, wf.Annotations)
+	}
+}
+
+func TestSuspendSummary_CronSchedule(t *testing.T) {
+	r := &mcp.WfSuspendResult{Name: "wf", Suspended: true, Changed: true, CronSchedule: "0 * * * *"}
+	if got := suspendSummary(r, "prod"); got != "suspended wf in prod (scaled to 0); paused cron schedule 0 * * * *" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	r = &mcp.WfSuspendResult{Name: "wf", Changed: true, Replicas: 2, CronSchedule: "0 * * * *"}
+	if got := suspendSummary(r, "prod"); got != "resumed wf in prod (2 replica(s)); restored cron schedule 0 * * * *" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
@@ -23,6 +23,7 @@ func NewTestCmd() *cobra.Command {
 	cmd.Flags().Bool("live", false, "Deploy and test against a real cluster")
 	cmd.Flags().Bool("keep", false, "Keep deployment after live test (do not clean up)")
 	cmd.Flags().Duration("timeout", 120*time.Second, "Timeout for live test (deploy + run)")
+	addInputFlag(cmd)
 	cmd.Flags().StringP("output", "o", "", "Output format (json)")
 	cmd.Flags().Bool("warn", false, "Audit mode: contract violations produce warnings instead of failures")
 	return cmd
@@ -31,6 +31,11 @@ func runLiveTest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no workflow.yaml found in %s", absDir)
 	}
 
+	input, err := loadRunInput(cmd, absDir, "")
+	if err != nil {
+		return err
+	}
+
 	clusterName := flagString(cmd, "cluster")
 	keep, _ := cmd.Flags().GetBool("keep")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
@@ -82,7 +87,7 @@ func runLiveTest(cmd *cobra.Command, args []string) error {
 	if !keep {
 		defer func() {
 			_, _ = fmt.Fprintf(w, "Cleaning up %s from %s...\n", deployResult.WorkflowName, deployResult.Namespace)
-			removeResult, delErr := deployResult.MCPClient.WfRemove(context.Background(), deployResult.Namespace, deployResult.WorkflowName)
+			removeResult, delErr := deployResult.Client.WfRemove(context.Background(), deployResult.Namespace, deployResult.WorkflowName)
 			if delErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: cleanup failed: %v\n", delErr)
 			} else {
@@ -95,7 +100,9 @@ func runLiveTest(cmd *cobra.Command, args []string) error {
 
 	// Trigger workflow run (MCP server handles readiness wait internally)
 	_, _ = fmt.Fprintf(w, "Running workflow %s (timeout: %s)...\n", deployResult.WorkflowName, timeout)
-	runResult, err := deployResult.MCPClient.WfRun(cmd.Context(), deployResult.Namespace, deployResult.WorkflowName, nil, int(timeout.Seconds()))
+	runStartedAt := time.Now()
+	runResult, err := mcpClient.WfRun(cmd.Context(), deployResult.Namespace, deployResult.WorkflowName, input, int(timeout.Seconds()))
+	recordRun(cmd, runTriggerTest, runResult, err, deployResult.Namespace, deployResult.WorkflowName, input, runStartedAt)
 	if err != nil {
 		return emitLiveResult(cmd, "fail", "workflow run failed: "+err.Error(), nil, startedAt)
 	}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around a change.
+const diffContextLines = 3
+
+// maxLCSCells bounds the line-diff table; larger changes are shown as one
+// replacement hunk.
+const maxLCSCells = 4_000_000
+
+type lineOp struct {
+	text string
+	kind byte // ' ', '-' or '+'
+}
+
+// unifiedDiff returns a unified diff from a to b with file headers, and the
+// number of added and removed lines. It returns "" when a and b are equal.
+func unifiedDiff(aName, bName, a, b string) (diff string, added, removed int) {
+	if a == b {
+		return "", 0, 0
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	// Line numbers (1-based) in a and b at which each op starts.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	aPos[0], bPos[0] = 1, 1
+	var changes []int
+	for i, op := range ops {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		switch op.kind {
+		case ' ':
+			aPos[i+1]++
+			bPos[i+1]++
+		case '-':
+			aPos[i+1]++
+			removed++
+			changes = append(changes, i)
+		case '+':
+			bPos[i+1]++
+			added++
+			changes = append(changes, i)
+		}
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+	for i := 0; i < len(changes); {
+		// Group changes whose context would overlap into one hunk.
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContextLines+1 {
+			j++
+		}
+		start := max(0, changes[i]-diffContextLines)
+		end := min(len(ops), changes[j]+diffContextLines+1)
+		aCount, bCount := aPos[end]-aPos[start], bPos[end]-bPos[start]
+		aStart, bStart := aPos[start], bPos[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		_, _ = fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		i = j + 1
+	}
+	return sb.String(), added, removed
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line edit script from a to b: common prefix and
+// suffix are trimmed, the middle is aligned by longest common subsequence.
+func diffLines(a, b []string) []lineOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		ops = append(ops, lineOp{l, ' '})
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma)*len(mb) > maxLCSCells {
+		for _, l := range ma {
+			ops = append(ops, lineOp{l, '-'})
+		}
+		for _, l := range mb {
+			ops = append(ops, lineOp{l, '+'})
+		}
+	} else {
+		ops = append(ops, lcsOps(ma, mb)...)
+	}
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, lineOp{l, ' '})
+	}
+	return ops
+}
+
+func lcsOps(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	ops := make([]lineOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{a[i], ' '})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{a[i], '-'})
+			i++
+		default:
+			ops = append(ops, lineOp{b[j], '+'})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, lineOp{a[i], '-'})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, lineOp{b[j], '+'})
+	}
+	return ops
+}
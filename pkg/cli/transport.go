@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// newDirectClient builds the client-go transport for --direct. Tests replace
+// it to inject a DirectClient backed by a fake clientset.
+var newDirectClient = func(kubeContext string) (mcp.WorkflowClient, error) {
+	client, err := k8s.NewDirectClient(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// addDirectFlags registers --direct and --context on a workflow command.
+func addDirectFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("direct", false, "Bypass the MCP server and talk to the cluster via kubeconfig (bootstrap/disconnected clusters)")
+	cmd.Flags().String("context", "", "Kubeconfig context for --direct (default: the environment's context, then current-context)")
+}
+
+// isDirect reports whether --direct was requested on cmd.
+func isDirect(cmd *cobra.Command) bool {
+	return flagString(cmd, "direct") == "true"
+}
+
+// resolveKubeContext determines the kubeconfig context for --direct using the
+// cascade: --context flag > active environment's context > "" (current-context).
+func resolveKubeContext(cmd *cobra.Command) string {
+	if kubeContext := flagString(cmd, "context"); kubeContext != "" {
+		return kubeContext
+	}
+	if env, err := ResolveEnvironment(flagString(cmd, "cluster")); err == nil {
+		return env.Context
+	}
+	return ""
+}
+
+// resolveWorkflowClient returns the transport for workflow lifecycle
+// commands: a client-go DirectClient with --direct, otherwise the MCP client.
+func resolveWorkflowClient(cmd *cobra.Command) (mcp.WorkflowClient, error) {
+	if isDirect(cmd) {
+		return newDirectClient(resolveKubeContext(cmd))
+	}
+	client, err := requireMCPClient(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
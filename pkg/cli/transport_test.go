@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// useFakeDirectClient swaps the --direct transport for a DirectClient backed by
+// a fake clientset seeded with objs. The returned pointer receives the
+// kubeconfig context the command asked for.
+func useFakeDirectClient(t *testing.T, objs ...runtime.Object) (*fake.Clientset, *string) {
+	t.Helper()
+	cs := fake.NewClientset(objs...)
+	var gotContext string
+	orig := newDirectClient
+	newDirectClient = func(kubeContext string) (mcp.WorkflowClient, error) {
+		gotContext = kubeContext
+		return k8s.NewDirectClientFromClientset(cs), nil
+	}
+	t.Cleanup(func() { newDirectClient = orig })
+	return cs, &gotContext
+}
+
+func withRootFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.PersistentFlags().StringP("cluster", "c", "", "Target cluster")
+	cmd.PersistentFlags().StringP("output", "o", "", "Output format")
+	cmd.SetContext(context.Background())
+	return cmd
+}
+
+func TestResolveKubeContext_Cascade(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TENTACULAR_CLUSTER", "")
+	userDir := filepath.Join(home, ".tentacular")
+	_ = os.MkdirAll(userDir, 0o755)
+	_ = os.WriteFile(filepath.Join(userDir, "config.yaml"), []byte("clusters:\n  prod:\n    context: prod-admin\n"), 0o644)
+
+	cmd := withRootFlags(NewStatusCmd())
+	_ = cmd.PersistentFlags().Set("cluster", "prod")
+	if got := resolveKubeContext(cmd); got != "prod-admin" {
+		t.Errorf("expected environment context, got %q", got)
+	}
+	_ = cmd.Flags().Set("context", "kind-bootstrap")
+	if got := resolveKubeContext(cmd); got != "kind-bootstrap" {
+		t.Errorf("expected --context to win, got %q", got)
+	}
+}
+
+func TestResolveWorkflowClient_DirectSkipsMCP(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("TNTC_MCP_ENDPOINT", "")
+	useFakeDirectClient(t)
+
+	cmd := withRootFlags(NewListCmd())
+	if _, err := resolveWorkflowClient(cmd); err == nil {
+		t.Fatal("expected MCP-not-configured error without --direct")
+	}
+	_ = cmd.Flags().Set("direct", "true")
+	client, err := resolveWorkflowClient(cmd)
+	if err != nil {
+		t.Fatalf("--direct should not need MCP: %v", err)
+	}
+	if _, ok := client.(*k8s.DirectClient); !ok {
+		t.Errorf("expected *k8s.DirectClient, got %T", client)
+	}
+}
+
+func TestStatusCmd_Direct(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	replicas := int32(1)
+	_, gotContext := useFakeDirectClient(t, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "default",
+			Labels: map[string]string{"app.kubernetes.io/version": "1.0"}},
+		Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+	})
+
+	cmd := withRootFlags(NewStatusCmd())
+	_ = cmd.Flags().Set("direct", "true")
+	_ = cmd.Flags().Set("context", "kind-dev")
+	var err error
+	out := captureStdout(t, func() { err = cmd.RunE(cmd, []string{"my-app"}) })
+	if err != nil {
+		t.Fatalf("runStatus --direct: %v", err)
+	}
+	if !strings.Contains(out, "Name:      my-app") || !strings.Contains(out, "1/1") {
+		t.Errorf("unexpected status output:\n%s", out)
+	}
+	if *gotContext != "kind-dev" {
+		t.Errorf("expected kubeconfig context kind-dev, got %q", *gotContext)
+	}
+}
+
+func TestUndeployCmd_Direct(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cs, _ := useFakeDirectClient(t, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "default",
+			Labels: map[string]string{"app.kubernetes.io/name": "my-app"}},
+	})
+
+	cmd := withRootFlags(NewUndeployCmd())
+	_ = cmd.Flags().Set("direct", "true")
+	_ = cmd.Flags().Set("yes", "true")
+	var err error
+	out := captureStdout(t, func() { err = cmd.RunE(cmd, []string{"my-app"}) })
+	if err != nil {
+		t.Fatalf("runUndeploy --direct: %v", err)
+	}
+	if !strings.Contains(out, "deleted Deployment/my-app") {
+		t.Errorf("expected deleted deployment in output, got:\n%s", out)
+	}
+	deps, _ := cs.AppsV1().Deployments("default").List(context.Background(), metav1.ListOptions{})
+	if len(deps.Items) != 0 {
+		t.Errorf("expected deployment removed, found %d", len(deps.Items))
+	}
+}
+
+func TestDeployWorkflow_Direct(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cs, _ := useFakeDirectClient(t)
+	client, _ := newDirectClient("")
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "workflow.yaml"), []byte(minimalWorkflowYAML), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "handler.ts"), []byte("export default async () => ({})\n"), 0o644)
+
+	var status bytes.Buffer
+	result, err := deployWorkflow(dir, InternalDeployOptions{
+		Namespace: "bootstrap",
+		Image:     "engine:latest",
+		Context:   "kind-bootstrap",
+		StatusOut: &status,
+	}, client)
+	if err != nil {
+		t.Fatalf("deployWorkflow --direct: %v", err)
+	}
+	if result.Client != client {
+		t.Error("expected result to carry the transport used for apply")
+	}
+	if !strings.Contains(status.String(), "(direct, kubeconfig context kind-bootstrap)") {
+		t.Errorf("expected direct transport in progress output, got:\n%s", status.String())
+	}
+	if _, err := cs.AppsV1().Deployments("bootstrap").Get(context.Background(), "test-workflow", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected Deployment applied via clientset: %v", err)
+	}
+	if _, err := cs.CoreV1().ConfigMaps("bootstrap").Get(context.Background(), "test-workflow-code", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected code ConfigMap applied via clientset: %v", err)
+	}
+}
@@ -21,6 +21,7 @@ func NewUndeployCmd() *cobra.Command {
 	}
 	cmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
 	cmd.Flags().Bool("force", false, "Skip exoskeleton cleanup confirmation")
+	addDirectFlags(cmd)
 	return cmd
 }
 
@@ -35,13 +36,17 @@ func runUndeployWith(cmd *cobra.Command, args []string, stdin io.Reader) error {
 	yes, _ := cmd.Flags().GetBool("yes")
 	force, _ := cmd.Flags().GetBool("force")
 
-	mcpClient, err := requireMCPClient(cmd)
+	client, err := resolveWorkflowClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Check exoskeleton status before confirming removal.
-	exoWarning := checkExoskeletonCleanup(cmd, mcpClient, namespace, name)
+	// Check exoskeleton status before confirming removal. Exoskeleton services
+	// are provisioned by the MCP server, so there is nothing to check with --direct.
+	exoWarning := ""
+	if mcpClient, ok := client.(*mcp.Client); ok {
+		exoWarning = checkExoskeletonCleanup(cmd, mcpClient, namespace, name)
+	}
 
 	if !yes {
 		fmt.Printf("Remove workflow %s from namespace %s? [y/N] ", name, namespace)
@@ -67,7 +72,7 @@ func runUndeployWith(cmd *cobra.Command, args []string, stdin io.Reader) error {
 		}
 	}
 
-	result, err := mcpClient.WfRemove(cmd.Context(), namespace, name)
+	result, err := client.WfRemove(cmd.Context(), namespace, name)
 	if err != nil {
 		if hint := mcpErrorHint(err); hint != "" {
 			return fmt.Errorf("removing workflow: %w\n  hint: %s", err, hint)
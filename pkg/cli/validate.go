@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/randybias/tentacular/pkg/k8s"
 	"github.com/randybias/tentacular/pkg/spec"
 )
 
@@ -49,9 +51,27 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	outputFormat, _ := cmd.Flags().GetString("output")
 	out := cmd.OutOrStdout()
 
+	// Offline compatibility check against the saved cluster profile (if any).
+	cfg := LoadConfig()
+	clusterName := activeClusterName(cmd, cfg)
+	secretsSource := ""
+	if env, ok := cfg.Clusters[clusterName]; ok {
+		secretsSource = env.SecretsSource
+	}
+	findings, compatErr := checkProfileCompatibility(clusterName, wf, resolveRuntimeClassForEnv(cfg, clusterName), secretsSource)
+	if compatErr != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: skipping cluster profile check: %s\n", compatErr)
+	}
+
 	// JSON output mode
 	if outputFormat == "json" {
-		return outputValidateJSON(wf, out)
+		if err := outputValidateJSON(wf, findings, out); err != nil {
+			return err
+		}
+		if k8s.HasCompatErrors(findings) {
+			return errors.New("workflow is incompatible with the saved cluster profile")
+		}
+		return nil
 	}
 
 	// Text output mode
@@ -103,21 +123,34 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		_, _ = fmt.Fprintf(out, "\n")
 	}
 
+	if len(findings) > 0 {
+		label := clusterName
+		if label == "" {
+			label = "default"
+		}
+		_, _ = fmt.Fprintf(out, "Cluster profile compatibility (%s):\n", label)
+		printCompatFindings(out, findings)
+		if k8s.HasCompatErrors(findings) {
+			return errors.New("workflow is incompatible with the saved cluster profile")
+		}
+	}
+
 	_, _ = fmt.Fprintf(out, "✓ %s is valid\n", specPath)
 	return nil
 }
 
 // ValidateResult is the JSON output structure for validate command.
 type ValidateResult struct {
-	Workflow     string            `json:"workflow"`
-	Version      string            `json:"version"`
-	Secrets      []string          `json:"secrets,omitempty"`
-	EgressRules  []EgressRuleJSON  `json:"egressRules,omitempty"`
-	IngressRules []IngressRuleJSON `json:"ingressRules,omitempty"`
-	Nodes        int               `json:"nodes"`
-	Edges        int               `json:"edges"`
-	Triggers     int               `json:"triggers"`
-	HasContract  bool              `json:"hasContract"`
+	Workflow      string              `json:"workflow"`
+	Version       string              `json:"version"`
+	Secrets       []string            `json:"secrets,omitempty"`
+	EgressRules   []EgressRuleJSON    `json:"egressRules,omitempty"`
+	IngressRules  []IngressRuleJSON   `json:"ingressRules,omitempty"`
+	Compatibility []k8s.CompatFinding `json:"compatibility,omitempty"`
+	Nodes         int                 `json:"nodes"`
+	Edges         int                 `json:"edges"`
+	Triggers      int                 `json:"triggers"`
+	HasContract   bool                `json:"hasContract"`
 }
 
 // EgressRuleJSON is the JSON representation of an egress rule.
@@ -134,15 +167,17 @@ type IngressRuleJSON struct {
 	Port       int               `json:"port"`
 }
 
-// outputValidateJSON outputs validation results in JSON format.
-func outputValidateJSON(wf *spec.Workflow, out io.Writer) error {
+// outputValidateJSON outputs validation results in JSON format, including any
+// cluster profile compatibility findings.
+func outputValidateJSON(wf *spec.Workflow, findings []k8s.CompatFinding, out io.Writer) error {
 	result := ValidateResult{
-		Workflow:    wf.Name,
-		Version:     wf.Version,
-		Nodes:       len(wf.Nodes),
-		Edges:       len(wf.Edges),
-		Triggers:    len(wf.Triggers),
-		HasContract: wf.Contract != nil,
+		Workflow:      wf.Name,
+		Version:       wf.Version,
+		Nodes:         len(wf.Nodes),
+		Edges:         len(wf.Edges),
+		Triggers:      len(wf.Triggers),
+		HasContract:   wf.Contract != nil,
+		Compatibility: findings,
 	}
 
 	if wf.Contract != nil {
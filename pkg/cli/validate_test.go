@@ -91,7 +91,7 @@ func TestOutputValidateJSONWithoutContract(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := outputValidateJSON(wf, &buf); err != nil {
+	if err := outputValidateJSON(wf, nil, &buf); err != nil {
 		t.Fatalf("outputValidateJSON: %v", err)
 	}
 
@@ -128,7 +128,7 @@ func TestOutputValidateJSONWithContract(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := outputValidateJSON(wf, &buf); err != nil {
+	if err := outputValidateJSON(wf, nil, &buf); err != nil {
 		t.Fatalf("outputValidateJSON: %v", err)
 	}
 
@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/randybias/tentacular/pkg/builder"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// Compatibility finding severities.
+const (
+	CompatError   = "error"
+	CompatWarning = "warning"
+)
+
+// SecretsSourceExternalSecrets is the EnvironmentConfig secrets_source value that
+// selects the External Secrets Operator as the secret provider.
+const SecretsSourceExternalSecrets = "external-secrets"
+
+// CompatFinding is a single problem detected when checking a workflow against
+// a saved ClusterProfile.
+type CompatFinding struct {
+	Severity string `json:"severity"` // "error" | "warning"
+	Check    string `json:"check"`    // runtime-class | egress | quota | autoscaling | secrets-source | profile-age
+	Message  string `json:"message"`
+}
+
+// CompatInput holds the deploy-time choices that are checked against a profile.
+type CompatInput struct {
+	Workflow      *spec.Workflow
+	RuntimeClass  string        // resolved runtimeClassName ("" when disabled)
+	SecretsSource string        // environment secrets_source setting
+	StaleAfter    time.Duration // warn when the profile is older than this (0 disables)
+}
+
+// CheckCompatibility compares a workflow and its resolved deploy options against a
+// saved ClusterProfile. It runs entirely offline and never contacts the cluster.
+// Findings are returned in a stable order: staleness first, then per-check results.
+func CheckCompatibility(p *ClusterProfile, in CompatInput) []CompatFinding {
+	var findings []CompatFinding
+	if p == nil {
+		return nil
+	}
+
+	if in.StaleAfter > 0 && !p.GeneratedAt.IsZero() {
+		if age := time.Since(p.GeneratedAt); age > in.StaleAfter {
+			findings = append(findings, CompatFinding{
+				Severity: CompatWarning,
+				Check:    "profile-age",
+				Message: fmt.Sprintf("cluster profile for %q is %s old; results may not reflect the cluster (refresh with: tntc cluster profile --save --force)",
+					p.Environment, age.Truncate(time.Hour)),
+			})
+		}
+	}
+
+	if f, ok := checkRuntimeClass(p, in.RuntimeClass); ok {
+		findings = append(findings, f)
+	}
+	if in.Workflow != nil {
+		if f, ok := checkEgress(p, in.Workflow); ok {
+			findings = append(findings, f)
+		}
+		findings = append(findings, checkQuota(p, in.Workflow)...)
+		if f, ok := checkAutoscaling(p, in.Workflow); ok {
+			findings = append(findings, f)
+		}
+	}
+	if f, ok := checkSecretsSource(p, in.SecretsSource); ok {
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// HasCompatErrors reports whether any finding has error severity.
+func HasCompatErrors(findings []CompatFinding) bool {
+	for _, f := range findings {
+		if f.Severity == CompatError {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRuntimeClass(p *ClusterProfile, runtimeClass string) (CompatFinding, bool) {
+	if runtimeClass == "" {
+		return CompatFinding{}, false
+	}
+	if runtimeClass == "gvisor" && !p.GVisor {
+		return CompatFinding{
+			Severity: CompatError,
+			Check:    "runtime-class",
+			Message:  "runtimeClassName: gvisor requested but gVisor is not available in the cluster (use --runtime-class \"\" or set runtime_class in the environment config)",
+		}, true
+	}
+	// Only flag non-gvisor classes when the profile actually lists RuntimeClasses;
+	// an empty list may mean the server could not read them.
+	if runtimeClass != "gvisor" && len(p.RuntimeClasses) > 0 {
+		for _, rc := range p.RuntimeClasses {
+			if rc.Name == runtimeClass {
+				return CompatFinding{}, false
+			}
+		}
+		return CompatFinding{
+			Severity: CompatError,
+			Check:    "runtime-class",
+			Message:  fmt.Sprintf("runtimeClassName: %s requested but no such RuntimeClass exists in the cluster", runtimeClass),
+		}, true
+	}
+	return CompatFinding{}, false
+}
+
+func checkEgress(p *ClusterProfile, wf *spec.Workflow) (CompatFinding, bool) {
+	// A NetworkPolicy is only generated for workflows with a contract.
+	if wf.Contract == nil || p.CNI.EgressSupported {
+		return CompatFinding{}, false
+	}
+	rules := spec.DeriveEgressRules(wf.Contract)
+	msg := fmt.Sprintf("workflow declares %d egress rule(s) but CNI %q does not enforce NetworkPolicy egress; outbound traffic will not be restricted", len(rules), p.CNI.Name)
+	if p.CNI.Name == "unknown" || p.CNI.Name == "" {
+		msg = fmt.Sprintf("workflow declares %d egress rule(s) but the CNI plugin could not be detected; egress enforcement is unverified", len(rules))
+	}
+	return CompatFinding{Severity: CompatWarning, Check: "egress", Message: msg}, true
+}
+
+// checkQuota flags workflows whose own resource footprint exceeds the namespace
+// ResourceQuota. Usage by other workloads is not known offline, so this only
+// catches deploys that can never fit.
+func checkQuota(p *ClusterProfile, wf *spec.Workflow) []CompatFinding {
+	if p.Quota == nil {
+		return nil
+	}
+	total := WorkflowResources(wf)
+
+	var findings []CompatFinding
+	compare := func(label, want string, have resource.Quantity) {
+		if want == "" {
+			return
+		}
+		limit, err := resource.ParseQuantity(want)
+		if err != nil {
+			return
+		}
+		if have.Cmp(limit) > 0 {
+			findings = append(findings, CompatFinding{
+				Severity: CompatError,
+				Check:    "quota",
+				Message: fmt.Sprintf("workflow %s %s exceeds namespace %q quota of %s",
+					label, have.String(), p.Namespace, limit.String()),
+			})
+		}
+	}
+	compare("CPU request", p.Quota.CPURequest, total.CPURequest)
+	compare("CPU limit", p.Quota.CPULimit, total.CPULimit)
+	compare("memory request", p.Quota.MemoryRequest, total.MemoryRequest)
+	compare("memory limit", p.Quota.MemoryLimit, total.MemoryLimit)
+	return findings
+}
+
+// checkAutoscaling flags deployment.autoscaling on a cluster without KEDA,
+// where no ScaledObject is generated and the workflow runs one replica.
+func checkAutoscaling(p *ClusterProfile, wf *spec.Workflow) (CompatFinding, bool) {
+	if wf.Deployment.Autoscaling == nil || p.Extensions.KEDA {
+		return CompatFinding{}, false
+	}
+	return CompatFinding{
+		Severity: CompatWarning,
+		Check:    "autoscaling",
+		Message:  "workflow declares deployment.autoscaling but KEDA is not installed in the cluster; it will run a single replica",
+	}, true
+}
+
+func checkSecretsSource(p *ClusterProfile, source string) (CompatFinding, bool) {
+	if source != SecretsSourceExternalSecrets || p.Extensions.ExternalSecrets {
+		return CompatFinding{}, false
+	}
+	return CompatFinding{
+		Severity: CompatError,
+		Check:    "secrets-source",
+		Message:  "secrets_source: external-secrets is configured but the External Secrets Operator is not installed in the cluster",
+	}, true
+}
+
+// ResourceTotals is the summed resource footprint of a workflow pod.
+type ResourceTotals struct {
+	CPURequest    resource.Quantity
+	CPULimit      resource.Quantity
+	MemoryRequest resource.Quantity
+	MemoryLimit   resource.Quantity
+}
+
+// WorkflowResources sums the engine container and sidecar resources of a workflow pod.
+// Unparseable sidecar quantities are ignored (spec validation reports them).
+func WorkflowResources(wf *spec.Workflow) ResourceTotals {
+	var t ResourceTotals
+	add := func(dst *resource.Quantity, v string) {
+		if v == "" {
+			return
+		}
+		if q, err := resource.ParseQuantity(v); err == nil {
+			dst.Add(q)
+		}
+	}
+	addSpec := func(r spec.ResourceSpec) {
+		add(&t.CPURequest, r.Requests.CPU)
+		add(&t.CPULimit, r.Limits.CPU)
+		add(&t.MemoryRequest, r.Requests.Memory)
+		add(&t.MemoryLimit, r.Limits.Memory)
+	}
+	addSpec(builder.EngineResources)
+	for _, sc := range wf.Sidecars {
+		if sc.Resources != nil {
+			addSpec(*sc.Resources)
+		}
+	}
+	return t
+}
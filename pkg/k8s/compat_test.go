@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+func compatProfile() *ClusterProfile {
+	return &ClusterProfile{
+		GeneratedAt:    time.Now(),
+		Environment:    "prod",
+		Namespace:      "prod",
+		GVisor:         true,
+		RuntimeClasses: []RuntimeClassInfo{{Name: "gvisor", Handler: "runsc"}},
+		CNI:            CNIInfo{Name: "calico", NetworkPolicySupported: true, EgressSupported: true},
+	}
+}
+
+func findCheck(findings []CompatFinding, check string) *CompatFinding {
+	for i := range findings {
+		if findings[i].Check == check {
+			return &findings[i]
+		}
+	}
+	return nil
+}
+
+func TestCheckCompatibility_Clean(t *testing.T) {
+	wf := &spec.Workflow{Name: "wf", Contract: &spec.Contract{}}
+	findings := CheckCompatibility(compatProfile(), CompatInput{Workflow: wf, RuntimeClass: "gvisor"})
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+func TestCheckCompatibility_NilProfile(t *testing.T) {
+	if findings := CheckCompatibility(nil, CompatInput{RuntimeClass: "gvisor"}); findings != nil {
+		t.Errorf("expected nil findings for nil profile, got %v", findings)
+	}
+}
+
+func TestCheckCompatibility_GVisorMissing(t *testing.T) {
+	p := compatProfile()
+	p.GVisor = false
+	p.RuntimeClasses = nil
+	findings := CheckCompatibility(p, CompatInput{RuntimeClass: "gvisor"})
+	f := findCheck(findings, "runtime-class")
+	if f == nil || f.Severity != CompatError {
+		t.Fatalf("expected runtime-class error, got %v", findings)
+	}
+	if !HasCompatErrors(findings) {
+		t.Error("expected HasCompatErrors = true")
+	}
+}
+
+func TestCheckCompatibility_RuntimeClassDisabled(t *testing.T) {
+	p := compatProfile()
+	p.GVisor = false
+	if f := findCheck(CheckCompatibility(p, CompatInput{RuntimeClass: ""}), "runtime-class"); f != nil {
+		t.Errorf("expected no runtime-class finding when disabled, got %v", f)
+	}
+}
+
+func TestCheckCompatibility_UnknownRuntimeClass(t *testing.T) {
+	findings := CheckCompatibility(compatProfile(), CompatInput{RuntimeClass: "kata"})
+	f := findCheck(findings, "runtime-class")
+	if f == nil || !strings.Contains(f.Message, "kata") {
+		t.Fatalf("expected runtime-class finding for kata, got %v", findings)
+	}
+}
+
+func TestCheckCompatibility_EgressUnsupported(t *testing.T) {
+	p := compatProfile()
+	p.CNI = CNIInfo{Name: "flannel"}
+	wf := &spec.Workflow{Contract: &spec.Contract{Dependencies: map[string]spec.Dependency{
+		"api": {Protocol: "https", Host: "api.example.com"},
+	}}}
+	findings := CheckCompatibility(p, CompatInput{Workflow: wf})
+	f := findCheck(findings, "egress")
+	if f == nil || f.Severity != CompatWarning {
+		t.Fatalf("expected egress warning, got %v", findings)
+	}
+	if !strings.Contains(f.Message, "flannel") {
+		t.Errorf("expected CNI name in message, got %q", f.Message)
+	}
+	if HasCompatErrors(findings) {
+		t.Error("egress finding should not be an error")
+	}
+}
+
+func TestCheckCompatibility_EgressNoContract(t *testing.T) {
+	p := compatProfile()
+	p.CNI = CNIInfo{Name: "flannel"}
+	findings := CheckCompatibility(p, CompatInput{Workflow: &spec.Workflow{}})
+	if f := findCheck(findings, "egress"); f != nil {
+		t.Errorf("expected no egress finding without contract, got %v", f)
+	}
+}
+
+func TestCheckCompatibility_QuotaExceeded(t *testing.T) {
+	p := compatProfile()
+	p.Quota = &QuotaSummary{CPULimit: "1", MemoryLimit: "512Mi", MemoryRequest: "1Gi"}
+	wf := &spec.Workflow{Sidecars: []spec.SidecarSpec{{
+		Name: "ffmpeg",
+		Resources: &spec.ResourceSpec{
+			Limits: spec.ResourceValues{CPU: "1", Memory: "128Mi"},
+		},
+	}}}
+	findings := CheckCompatibility(p, CompatInput{Workflow: wf})
+	var quota []CompatFinding
+	for _, f := range findings {
+		if f.Check == "quota" {
+			quota = append(quota, f)
+		}
+	}
+	// engine 500m + sidecar 1 = 1500m > 1 CPU; memory limit 384Mi fits in 512Mi.
+	if len(quota) != 1 {
+		t.Fatalf("expected 1 quota finding, got %v", quota)
+	}
+	if !strings.Contains(quota[0].Message, "CPU limit 1500m") {
+		t.Errorf("unexpected quota message: %q", quota[0].Message)
+	}
+}
+
+func TestCheckCompatibility_ExternalSecretsMissing(t *testing.T) {
+	findings := CheckCompatibility(compatProfile(), CompatInput{SecretsSource: SecretsSourceExternalSecrets})
+	if f := findCheck(findings, "secrets-source"); f == nil || f.Severity != CompatError {
+		t.Fatalf("expected secrets-source error, got %v", findings)
+	}
+
+	p := compatProfile()
+	p.Extensions.ExternalSecrets = true
+	if f := findCheck(CheckCompatibility(p, CompatInput{SecretsSource: SecretsSourceExternalSecrets}), "secrets-source"); f != nil {
+		t.Errorf("expected no finding when ESO installed, got %v", f)
+	}
+}
+
+func TestCheckCompatibility_StaleProfile(t *testing.T) {
+	p := compatProfile()
+	p.GeneratedAt = time.Now().Add(-10 * 24 * time.Hour)
+	findings := CheckCompatibility(p, CompatInput{StaleAfter: 7 * 24 * time.Hour})
+	f := findCheck(findings, "profile-age")
+	if f == nil || f.Severity != CompatWarning {
+		t.Fatalf("expected profile-age warning, got %v", findings)
+	}
+	if findings[0].Check != "profile-age" {
+		t.Errorf("expected staleness reported first, got %v", findings)
+	}
+}
+
+func TestWorkflowResources_EngineOnly(t *testing.T) {
+	total := WorkflowResources(&spec.Workflow{})
+	if total.CPURequest.String() != "100m" || total.MemoryLimit.String() != "256Mi" {
+		t.Errorf("unexpected engine totals: cpu request %s, memory limit %s",
+			total.CPURequest.String(), total.MemoryLimit.String())
+	}
+}
+
+func TestCheckCompatibility_AutoscalingWithoutKEDA(t *testing.T) {
+	wf := &spec.Workflow{Name: "wf", Deployment: spec.DeploymentConfig{Autoscaling: &spec.AutoscalingConfig{MaxReplicas: 2}}}
+	p := compatProfile()
+	f := findCheck(CheckCompatibility(p, CompatInput{Workflow: wf, RuntimeClass: "gvisor"}), "autoscaling")
+	if f == nil || f.Severity != CompatWarning || !strings.Contains(f.Message, "single replica") {
+		t.Errorf("expected an autoscaling warning without KEDA, got %+v", f)
+	}
+	p.Extensions.KEDA = true
+	if f := findCheck(CheckCompatibility(p, CompatInput{Workflow: wf, RuntimeClass: "gvisor"}), "autoscaling"); f != nil {
+		t.Errorf("expected no autoscaling finding with KEDA, got %+v", f)
+	}
+}
@@ -0,0 +1,901 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+const (
+	labelName      = "app.kubernetes.io/name"
+	labelVersion   = "app.kubernetes.io/version"
+	labelManagedBy = "app.kubernetes.io/managed-by"
+	managedByValue = "tentacular"
+
+	// engineContainer is the workflow engine container name in generated Deployments.
+	engineContainer = "engine"
+
+	// deployLockSuffix names the Lease holding a workflow's deploy lock.
+	deployLockSuffix = "-deploy-lock"
+	// annotationLockID records the lock ID on the deploy lock Lease.
+	annotationLockID = "tentacular.io/deploy-lock-id"
+	// labelRevisionsOf marks a workflow's revisions ConfigMap. It is not
+	// labelled with the workflow name, so undeploy and diffs leave it alone.
+	labelRevisionsOf = "tentacular.io/revisions-of"
+	// annotationKEDAPaused stops KEDA scaling a ScaledObject's target.
+	annotationKEDAPaused = "autoscaling.keda.sh/paused"
+)
+
+// scaledObjectGVR is the KEDA ScaledObject generated for deployment.autoscaling.
+var scaledObjectGVR = schema.GroupVersionResource{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"}
+
+// DirectClient applies and inspects workflows with client-go against a
+// kubeconfig context, without the MCP server. It is used to bootstrap clusters
+// before the MCP chart is installed and for disconnected clusters.
+//
+// It implements mcp.WorkflowClient, and supports the resource kinds generated
+// by tntc deploy: ConfigMap, Secret, Service, Deployment and NetworkPolicy.
+type DirectClient struct {
+	clientset kubernetes.Interface
+	dynamic   dynamic.Interface // KEDA ScaledObjects; nil skips them
+	context   string
+}
+
+var (
+	_ mcp.WorkflowClient = (*DirectClient)(nil)
+	_ mcp.DeployLocker   = (*DirectClient)(nil)
+	_ mcp.Suspender      = (*DirectClient)(nil)
+	_ mcp.RevisionKeeper = (*DirectClient)(nil)
+)
+
+// NewDirectClient builds a DirectClient from the default kubeconfig loading
+// rules (KUBECONFIG, then ~/.kube/config). An empty kubeContext uses the
+// kubeconfig's current-context.
+func NewDirectClient(kubeContext string) (*DirectClient, error) {
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	)
+	restCfg, err := loader.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	if kubeContext == "" {
+		if raw, rawErr := loader.RawConfig(); rawErr == nil {
+			kubeContext = raw.CurrentContext
+		}
+	}
+	cs, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating Kubernetes client: %w", err)
+	}
+	dyn, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating dynamic client: %w", err)
+	}
+	return &DirectClient{clientset: cs, dynamic: dyn, context: kubeContext}, nil
+}
+
+// NewDirectClientFromClientset wraps an existing clientset (e.g. a fake in tests).
+func NewDirectClientFromClientset(cs kubernetes.Interface) *DirectClient {
+	return &DirectClient{clientset: cs}
+}
+
+// NewDirectClientFromClients wraps an existing clientset and dynamic client,
+// which reaches KEDA ScaledObjects (e.g. fakes in tests).
+func NewDirectClientFromClients(cs kubernetes.Interface, dyn dynamic.Interface) *DirectClient {
+	return &DirectClient{clientset: cs, dynamic: dyn}
+}
+
+// Context returns the kubeconfig context the client talks to ("" when unknown).
+func (c *DirectClient) Context() string {
+	return c.context
+}
+
+// --- apply ---
+
+// typedResource is the get/create/update subset shared by the typed clients.
+type typedResource[T metav1.Object] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+}
+
+// applyTyped creates obj, or replaces the existing object of the same name.
+// Returns true when an existing object was updated.
+func applyTyped[T metav1.Object](ctx context.Context, rc typedResource[T], obj T) (bool, error) {
+	existing, err := rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = rc.Create(ctx, obj, metav1.CreateOptions{})
+		return false, err
+	}
+	if err != nil {
+		return false, err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = rc.Update(ctx, obj, metav1.UpdateOptions{})
+	return true, err
+}
+
+// fromManifest converts a manifest map into a typed object.
+func fromManifest[T any](m map[string]any) (*T, error) {
+	var obj T
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &obj); err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+// WfApply creates or updates each manifest in namespace. When any existing
+// resource is updated, the workflow Deployment is restarted so pods pick up
+// new code and config, matching the MCP server's wf_apply behaviour.
+func (c *DirectClient) WfApply(ctx context.Context, namespace, name string, manifests []map[string]any) (*mcp.WfApplyResult, error) {
+	result := &mcp.WfApplyResult{Applied: []string{}}
+	for _, m := range manifests {
+		kind, _ := m["kind"].(string)
+		updated, resName, err := c.applyManifest(ctx, namespace, kind, m)
+		if err != nil {
+			return nil, fmt.Errorf("applying %s/%s: %w", kind, resName, err)
+		}
+		result.Applied = append(result.Applied, kind+"/"+resName)
+		if updated {
+			result.Updated++
+		}
+	}
+
+	result.Status = "created"
+	if result.Updated > 0 {
+		result.Status = "updated"
+		if err := c.restartDeployment(ctx, namespace, name); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("restarting deployment %s: %w", name, err)
+		}
+	}
+	return result, nil
+}
+
+func (c *DirectClient) applyManifest(ctx context.Context, namespace, kind string, m map[string]any) (bool, string, error) {
+	meta, _ := m["metadata"].(map[string]any)
+	if meta == nil {
+		return false, "", errors.New("manifest has no metadata")
+	}
+	meta["namespace"] = namespace
+	resName, _ := meta["name"].(string)
+
+	var updated bool
+	var err error
+	switch kind {
+	case "ConfigMap":
+		obj, convErr := fromManifest[corev1.ConfigMap](m)
+		if convErr != nil {
+			return false, resName, convErr
+		}
+		updated, err = applyTyped(ctx, c.clientset.CoreV1().ConfigMaps(namespace), obj)
+	case "Secret":
+		obj, convErr := fromManifest[corev1.Secret](m)
+		if convErr != nil {
+			return false, resName, convErr
+		}
+		updated, err = applyTyped(ctx, c.clientset.CoreV1().Secrets(namespace), obj)
+	case "Service":
+		obj, convErr := fromManifest[corev1.Service](m)
+		if convErr != nil {
+			return false, resName, convErr
+		}
+		// ClusterIP is immutable; carry it over from the live object on update.
+		if existing, getErr := c.clientset.CoreV1().Services(namespace).Get(ctx, resName, metav1.GetOptions{}); getErr == nil {
+			obj.Spec.ClusterIP = existing.Spec.ClusterIP
+			obj.Spec.ClusterIPs = existing.Spec.ClusterIPs
+		}
+		updated, err = applyTyped(ctx, c.clientset.CoreV1().Services(namespace), obj)
+	case "Deployment":
+		obj, convErr := fromManifest[appsv1.Deployment](m)
+		if convErr != nil {
+			return false, resName, convErr
+		}
+		updated, err = applyTyped(ctx, c.clientset.AppsV1().Deployments(namespace), obj)
+	case "NetworkPolicy":
+		obj, convErr := fromManifest[networkingv1.NetworkPolicy](m)
+		if convErr != nil {
+			return false, resName, convErr
+		}
+		updated, err = applyTyped(ctx, c.clientset.NetworkingV1().NetworkPolicies(namespace), obj)
+	case "ScaledObject":
+		return false, resName, errors.New("KEDA ScaledObjects (deployment.autoscaling) are not supported in direct mode; deploy through the MCP server")
+	default:
+		return false, resName, fmt.Errorf("kind %q is not supported in direct mode", kind)
+	}
+	return updated, resName, err
+}
+
+// restartDeployment triggers a rollout by stamping the pod template, like
+// kubectl rollout restart.
+func (c *DirectClient) restartDeployment(ctx context.Context, namespace, name string) error {
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+		time.Now().UTC().Format(time.RFC3339))
+	_, err := c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// --- remove ---
+
+// WfRemove deletes every resource labelled app.kubernetes.io/name=<name> of
+// the supported kinds, plus the <name>-secrets Secret (which is unlabelled)
+// and the <name> KEDA ScaledObject when the cluster has one.
+func (c *DirectClient) WfRemove(ctx context.Context, namespace, name string) (*mcp.WfRemoveResult, error) {
+	selector := metav1.ListOptions{LabelSelector: labelName + "=" + name}
+	result := &mcp.WfRemoveResult{}
+	deleted := func(kind, resName string) {
+		result.Deleted = append(result.Deleted, kind+"/"+resName)
+	}
+
+	// The ScaledObject goes first so KEDA does not scale the Deployment
+	// while it is being removed.
+	if c.dynamic != nil {
+		err := c.dynamic.Resource(scaledObjectGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		switch {
+		case err == nil:
+			deleted("ScaledObject", name)
+		case ignoreNoScaledObject(err) != nil:
+			return nil, fmt.Errorf("deleting ScaledObject/%s: %w", name, err)
+		}
+	}
+
+	deps, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+	for _, d := range deps.Items {
+		if err := c.clientset.AppsV1().Deployments(namespace).Delete(ctx, d.Name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return nil, fmt.Errorf("deleting Deployment/%s: %w", d.Name, err)
+		}
+		deleted("Deployment", d.Name)
+	}
+
+	svcs, err := c.clientset.CoreV1().Services(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing services: %w", err)
+	}
+	for _, s := range svcs.Items {
+		if err := c.clientset.CoreV1().Services(namespace).Delete(ctx, s.Name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return nil, fmt.Errorf("deleting Service/%s: %w", s.Name, err)
+		}
+		deleted("Service", s.Name)
+	}
+
+	cms, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing configmaps: %w", err)
+	}
+	for _, cm := range cms.Items {
+		if err := c.clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return nil, fmt.Errorf("deleting ConfigMap/%s: %w", cm.Name, err)
+		}
+		deleted("ConfigMap", cm.Name)
+	}
+
+	nps, err := c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing network policies: %w", err)
+	}
+	for _, np := range nps.Items {
+		if err := c.clientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, np.Name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return nil, fmt.Errorf("deleting NetworkPolicy/%s: %w", np.Name, err)
+		}
+		deleted("NetworkPolicy", np.Name)
+	}
+
+	secretName := name + "-secrets"
+	err = c.clientset.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	switch {
+	case err == nil:
+		deleted("Secret", secretName)
+	case !apierrors.IsNotFound(err):
+		return nil, fmt.Errorf("deleting Secret/%s: %w", secretName, err)
+	}
+
+	result.DeletedCount = len(result.Deleted)
+	return result, nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// --- live manifests ---
+
+// LiveManifests returns the workflow's live objects of the supported kinds,
+// selected like WfRemove, as manifest maps with apiVersion and kind set. The
+// wf_describe tool returns the same for MCP; it backs tntc deploy --diff.
+func (c *DirectClient) LiveManifests(ctx context.Context, namespace, name string) ([]map[string]any, error) {
+	selector := metav1.ListOptions{LabelSelector: labelName + "=" + name}
+	var manifests []map[string]any
+	add := func(apiVersion, kind string, obj any) error {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("converting %s: %w", kind, err)
+		}
+		m["apiVersion"], m["kind"] = apiVersion, kind
+		manifests = append(manifests, m)
+		return nil
+	}
+
+	cms, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing configmaps: %w", err)
+	}
+	for i := range cms.Items {
+		if err := add("v1", "ConfigMap", &cms.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name+"-secrets", metav1.GetOptions{})
+	if ignoreNotFound(err) != nil {
+		return nil, fmt.Errorf("getting Secret/%s-secrets: %w", name, err)
+	}
+	if err == nil {
+		if err := add("v1", "Secret", secret); err != nil {
+			return nil, err
+		}
+	}
+	svcs, err := c.clientset.CoreV1().Services(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing services: %w", err)
+	}
+	for i := range svcs.Items {
+		if err := add("v1", "Service", &svcs.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	deps, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+	for i := range deps.Items {
+		if err := add("apps/v1", "Deployment", &deps.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	nps, err := c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("listing network policies: %w", err)
+	}
+	for i := range nps.Items {
+		if err := add("networking.k8s.io/v1", "NetworkPolicy", &nps.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
+}
+
+// --- deploy lock ---
+
+// WfLock acquires a workflow's deploy lock, held as the coordination.k8s.io
+// Lease <name>-deploy-lock with the rules of mcp.GrantLock. When another
+// deploy changes the Lease concurrently the attempt is retried, so exactly
+// one of them wins.
+func (c *DirectClient) WfLock(ctx context.Context, p mcp.WfLockParams) (*mcp.WfLockResult, error) {
+	leases := c.clientset.CoordinationV1().Leases(p.Namespace)
+	leaseName := p.Name + deployLockSuffix
+	for attempt := 0; ; attempt++ {
+		existing, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("reading deploy lock: %w", err)
+		}
+		var current *mcp.DeployLock
+		if err == nil {
+			lock := leaseLock(existing)
+			current = &lock
+		}
+		now := time.Now()
+		result := mcp.GrantLock(current, p, now)
+		if !result.Acquired {
+			return &result, nil
+		}
+
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        leaseName,
+				Namespace:   p.Namespace,
+				Labels:      map[string]string{labelName: p.Name, labelManagedBy: managedByValue},
+				Annotations: map[string]string{annotationLockID: p.LockID},
+			},
+			Spec: coordinationv1.LeaseSpec{HolderIdentity: &result.Lock.Holder},
+		}
+		ttl := int32(max(p.TTLSeconds, 0)) //nolint:gosec // TTLs are minutes, far below int32 range
+		if ttl == 0 {
+			ttl = mcp.DefaultLockTTLSeconds
+		}
+		lease.Spec.LeaseDurationSeconds = &ttl
+		acquired := metav1.NewMicroTime(now)
+		if t, parseErr := time.Parse(time.RFC3339, result.Lock.AcquiredAt); parseErr == nil {
+			acquired = metav1.NewMicroTime(t)
+		}
+		renewed := metav1.NewMicroTime(now)
+		lease.Spec.AcquireTime, lease.Spec.RenewTime = &acquired, &renewed
+
+		if current == nil {
+			_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		} else {
+			lease.ResourceVersion = existing.ResourceVersion
+			_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+		}
+		if (apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)) && attempt < 3 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing deploy lock: %w", err)
+		}
+		return &result, nil
+	}
+}
+
+// WfUnlock deletes the workflow's deploy lock Lease when it is held with lockID.
+func (c *DirectClient) WfUnlock(ctx context.Context, namespace, name, lockID string) (*mcp.WfUnlockResult, error) {
+	leases := c.clientset.CoordinationV1().Leases(namespace)
+	existing, err := leases.Get(ctx, name+deployLockSuffix, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &mcp.WfUnlockResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading deploy lock: %w", err)
+	}
+	if existing.Annotations[annotationLockID] != lockID {
+		return &mcp.WfUnlockResult{}, nil
+	}
+	rv := existing.ResourceVersion
+	err = leases.Delete(ctx, existing.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &rv}})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return &mcp.WfUnlockResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("releasing deploy lock: %w", err)
+	}
+	return &mcp.WfUnlockResult{Released: true}, nil
+}
+
+// leaseLock reads the deploy lock held by a Lease.
+func leaseLock(l *coordinationv1.Lease) mcp.DeployLock {
+	lock := mcp.DeployLock{LockID: l.Annotations[annotationLockID]}
+	if l.Spec.HolderIdentity != nil {
+		lock.Holder = *l.Spec.HolderIdentity
+	}
+	if l.Spec.AcquireTime != nil {
+		lock.AcquiredAt = l.Spec.AcquireTime.UTC().Format(time.RFC3339)
+	}
+	if l.Spec.RenewTime != nil && l.Spec.LeaseDurationSeconds != nil {
+		expires := l.Spec.RenewTime.Add(time.Duration(*l.Spec.LeaseDurationSeconds) * time.Second)
+		lock.ExpiresAt = expires.UTC().Format(time.RFC3339)
+	}
+	return lock
+}
+
+// --- revisions ---
+
+// WfRevisions reads the workflow's deploy revisions from its <name>-revisions
+// ConfigMap, newest first.
+func (c *DirectClient) WfRevisions(ctx context.Context, namespace, name string) (*mcp.WfRevisionsResult, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name+mcp.RevisionsConfigMapSuffix, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &mcp.WfRevisionsResult{Revisions: []mcp.StoredRevision{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading revisions: %w", err)
+	}
+	return &mcp.WfRevisionsResult{Revisions: mcp.RevisionsFromData(cm.Data)}, nil
+}
+
+// WfRecordRevision appends a revision to the workflow's <name>-revisions
+// ConfigMap with the rules of mcp.RecordRevision. When another deploy writes
+// the ConfigMap concurrently the attempt is retried, so each gets its own
+// revision number.
+func (c *DirectClient) WfRecordRevision(ctx context.Context, p mcp.WfRecordRevisionParams) (*mcp.WfRecordRevisionResult, error) {
+	cms := c.clientset.CoreV1().ConfigMaps(p.Namespace)
+	cmName := p.Name + mcp.RevisionsConfigMapSuffix
+	for attempt := 0; ; attempt++ {
+		existing, err := cms.Get(ctx, cmName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("reading revisions: %w", err)
+		}
+		found := err == nil
+		var current map[string]string
+		if found {
+			current = existing.Data
+		}
+		data, result, err := mcp.RecordRevision(current, p)
+		if err != nil {
+			return nil, err
+		}
+
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmName,
+				Namespace: p.Namespace,
+				Labels:    map[string]string{labelRevisionsOf: p.Name, labelManagedBy: managedByValue},
+			},
+			Data: data,
+		}
+		if found {
+			cm.ResourceVersion = existing.ResourceVersion
+			_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+		} else {
+			_, err = cms.Create(ctx, cm, metav1.CreateOptions{})
+		}
+		if (apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err)) && attempt < 3 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("writing revisions: %w", err)
+		}
+		return &result, nil
+	}
+}
+
+// --- suspend / resume ---
+
+// WfSuspend scales the workflow Deployment to zero, recording its replica
+// count and who suspended it in annotations. Its cron schedule is moved to
+// the suspended-cron-schedule annotation so the scheduler stops firing runs,
+// and its KEDA ScaledObject, if any, is paused first so queue lag does not
+// scale it back up. Suspending a suspended workflow changes nothing.
+func (c *DirectClient) WfSuspend(ctx context.Context, namespace, name, by string) (*mcp.WfSuspendResult, error) {
+	d, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting deployment %s: %w", name, err)
+	}
+	result := &mcp.WfSuspendResult{Name: name, Namespace: namespace, Suspended: true}
+	if d.Annotations[mcp.AnnotationSuspended] == "true" {
+		return result, nil
+	}
+	annotations := map[string]any{
+		mcp.AnnotationSuspended:         "true",
+		mcp.AnnotationSuspendedAt:       time.Now().UTC().Format(time.RFC3339),
+		mcp.AnnotationSuspendedBy:       by,
+		mcp.AnnotationSuspendedReplicas: strconv.Itoa(int(desiredReplicas(d))),
+	}
+	if schedule, ok := d.Annotations[mcp.AnnotationCronSchedule]; ok {
+		annotations[mcp.AnnotationSuspendedCronSchedule] = schedule
+		annotations[mcp.AnnotationCronSchedule] = nil
+		result.CronSchedule = schedule
+	}
+	if err := c.pauseScaledObject(ctx, namespace, name, true); err != nil {
+		return nil, err
+	}
+	patch, _ := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+		"spec":     map[string]any{"replicas": 0},
+	})
+	if _, err := c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, fmt.Errorf("suspending deployment %s: %w", name, err)
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// WfResume restores the replica count recorded by WfSuspend (at least one)
+// and the cron schedule, removes the suspend annotations and unpauses the
+// KEDA ScaledObject.
+func (c *DirectClient) WfResume(ctx context.Context, namespace, name string) (*mcp.WfSuspendResult, error) {
+	d, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting deployment %s: %w", name, err)
+	}
+	result := &mcp.WfSuspendResult{Name: name, Namespace: namespace, Replicas: desiredReplicas(d)}
+	if d.Annotations[mcp.AnnotationSuspended] != "true" {
+		return result, nil
+	}
+	replicas := int32(1)
+	if n, convErr := strconv.ParseInt(d.Annotations[mcp.AnnotationSuspendedReplicas], 10, 32); convErr == nil && n > 0 {
+		replicas = int32(n)
+	}
+	annotations := map[string]any{
+		mcp.AnnotationSuspended:             nil,
+		mcp.AnnotationSuspendedAt:           nil,
+		mcp.AnnotationSuspendedBy:           nil,
+		mcp.AnnotationSuspendedReplicas:     nil,
+		mcp.AnnotationSuspendedCronSchedule: nil,
+	}
+	if schedule, ok := d.Annotations[mcp.AnnotationSuspendedCronSchedule]; ok {
+		annotations[mcp.AnnotationCronSchedule] = schedule
+		result.CronSchedule = schedule
+	}
+	patch, _ := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": annotations},
+		"spec":     map[string]any{"replicas": replicas},
+	})
+	if _, err := c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return nil, fmt.Errorf("resuming deployment %s: %w", name, err)
+	}
+	if err := c.pauseScaledObject(ctx, namespace, name, false); err != nil {
+		return nil, err
+	}
+	result.Replicas, result.Changed = replicas, true
+	return result, nil
+}
+
+// pauseScaledObject sets or removes the KEDA paused annotation on the
+// workflow's <name> ScaledObject. A workflow without one, or a cluster
+// without KEDA, is left alone.
+func (c *DirectClient) pauseScaledObject(ctx context.Context, namespace, name string, paused bool) error {
+	if c.dynamic == nil {
+		return nil
+	}
+	var value any // null removes the annotation
+	if paused {
+		value = "true"
+	}
+	patch, _ := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{annotationKEDAPaused: value}},
+	})
+	_, err := c.dynamic.Resource(scaledObjectGVR).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if ignoreNoScaledObject(err) != nil {
+		return fmt.Errorf("updating ScaledObject/%s: %w", name, err)
+	}
+	return nil
+}
+
+// ignoreNoScaledObject drops the errors for a missing ScaledObject or a
+// cluster without the KEDA CRDs.
+func ignoreNoScaledObject(err error) error {
+	if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// --- status / list / pods / logs ---
+
+// WfStatus reports the workflow Deployment's readiness. With detail, pods
+// selected by the workflow label and events for the workflow are included.
+func (c *DirectClient) WfStatus(ctx context.Context, namespace, name string, detail bool) (*mcp.WfStatusResult, error) {
+	dep, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting deployment %s: %w", name, err)
+	}
+	result := &mcp.WfStatusResult{
+		Name:      dep.Name,
+		Namespace: dep.Namespace,
+		Version:   dep.Labels[labelVersion],
+		Replicas:  desiredReplicas(dep),
+		Available: dep.Status.AvailableReplicas,
+		Ready:     deploymentReady(dep),
+
+		Updated:            dep.Status.UpdatedReplicas,
+		Generation:         dep.Generation,
+		ObservedGeneration: dep.Status.ObservedGeneration,
+	}
+	if !detail {
+		return result, nil
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelName + "=" + name})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		info := mcp.PodInfo{
+			Name:     p.Name,
+			Phase:    string(p.Status.Phase),
+			NodeName: p.Spec.NodeName,
+			Ready:    podReady(p),
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			info.Restarts += cs.RestartCount
+			if info.Reason != "" {
+				continue
+			}
+			if cs.State.Waiting != nil {
+				info.Reason = cs.State.Waiting.Reason
+			} else if cs.State.Terminated != nil {
+				info.Reason = cs.State.Terminated.Reason
+			}
+		}
+		result.Pods = append(result.Pods, info)
+	}
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing events: %w", err)
+	}
+	for _, e := range events.Items {
+		obj := e.InvolvedObject.Name
+		if obj != name && !strings.HasPrefix(obj, name+"-") {
+			continue
+		}
+		result.Events = append(result.Events, mcp.EventInfo{
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+		})
+	}
+	return result, nil
+}
+
+// WfList lists tentacular-managed Deployments in namespace, sorted by name.
+func (c *DirectClient) WfList(ctx context.Context, namespace string) ([]mcp.WfListItem, error) {
+	deps, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelManagedBy + "=" + managedByValue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+	items := make([]mcp.WfListItem, 0, len(deps.Items))
+	for i := range deps.Items {
+		d := &deps.Items[i]
+		// The module proxy (esm-sh) is tentacular-managed but is not a
+		// workflow; only workflow Deployments carry the version label.
+		if _, ok := d.Labels[labelVersion]; !ok {
+			continue
+		}
+		items = append(items, mcp.WfListItem{
+			Name:        d.Name,
+			Namespace:   d.Namespace,
+			Version:     d.Labels[labelVersion],
+			Description: d.Annotations["tentacular.io/description"],
+			Environment: d.Annotations["tentacular.io/environment"],
+			DeployedBy:  d.Annotations["tentacular.io/deployed-by"],
+			DeployedVia: d.Annotations["tentacular.io/deployed-via"],
+			DeployedAt:  d.Annotations["tentacular.io/deployed-at"],
+			SuspendedBy: d.Annotations[mcp.AnnotationSuspendedBy],
+			SuspendedAt: d.Annotations[mcp.AnnotationSuspendedAt],
+			CreatedAt:   d.CreationTimestamp.UTC().Format(time.RFC3339),
+			Replicas:    desiredReplicas(d),
+			Available:   d.Status.AvailableReplicas,
+			Ready:       deploymentReady(d),
+			Suspended:   d.Annotations[mcp.AnnotationSuspended] == "true",
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+// WfPods lists all pods in namespace.
+func (c *DirectClient) WfPods(ctx context.Context, namespace string) (*mcp.WfPodsResult, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	result := &mcp.WfPodsResult{Pods: make([]mcp.WfPod, 0, len(pods.Items))}
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		pod := mcp.WfPod{
+			Name:  p.Name,
+			Phase: string(p.Status.Phase),
+			Age:   shortAge(time.Since(p.CreationTimestamp.Time)),
+			Ready: podReady(p),
+		}
+		for _, ctr := range p.Spec.Containers {
+			pod.Images = append(pod.Images, ctr.Image)
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			pod.Restarts += int(cs.RestartCount)
+		}
+		result.Pods = append(result.Pods, pod)
+	}
+	return result, nil
+}
+
+// WfLogs returns the last tailLines lines of the pod's engine container log
+// (or its only container, for pods without an engine container).
+func (c *DirectClient) WfLogs(ctx context.Context, namespace, pod string, tailLines int64) (*mcp.WfLogsResult, error) {
+	return c.WfLogsQuery(ctx, mcp.WfLogsParams{Namespace: namespace, Pod: pod, TailLines: tailLines})
+}
+
+// WfLogsQuery returns a pod's log for the requested container (default: the
+// engine container), tail, time window and timestamp options.
+func (c *DirectClient) WfLogsQuery(ctx context.Context, params mcp.WfLogsParams) (*mcp.WfLogsResult, error) {
+	namespace, pod := params.Namespace, params.Pod
+	p, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting pod %s: %w", pod, err)
+	}
+	container := params.Container
+	if container == "" {
+		for _, ctr := range p.Spec.Containers {
+			if ctr.Name == engineContainer {
+				container = engineContainer
+				break
+			}
+		}
+	}
+
+	opts := &corev1.PodLogOptions{Container: container, Timestamps: params.Timestamps}
+	if params.TailLines > 0 {
+		opts.TailLines = &params.TailLines
+	}
+	if params.SinceSeconds > 0 {
+		opts.SinceSeconds = &params.SinceSeconds
+	}
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("streaming logs for %s: %w", pod, err)
+	}
+	defer func() { _ = stream.Close() }()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("reading logs for %s: %w", pod, err)
+	}
+	return &mcp.WfLogsResult{
+		Logs:      strings.TrimRight(string(data), "\n"),
+		Pod:       pod,
+		Container: container,
+	}, nil
+}
+
+// WfHealth fetches the engine telemetry snapshot (GET /health?detail=1)
+// through the API server's service proxy. The snapshot is returned
+// unclassified: green/amber/red is left to the caller.
+func (c *DirectClient) WfHealth(ctx context.Context, namespace, name string) (*mcp.WfHealthResult, error) {
+	data, err := c.clientset.CoreV1().Services(namespace).
+		ProxyGet("http", name, "8080", "/health", map[string]string{"detail": "1"}).
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting health of %s: %w", name, err)
+	}
+	var snapshot mcp.TelemetrySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing health of %s: %w", name, err)
+	}
+	return &mcp.WfHealthResult{Name: name, Namespace: namespace, Detail: &snapshot}, nil
+}
+
+func desiredReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas != nil {
+		return *d.Spec.Replicas
+	}
+	return 1
+}
+
+func deploymentReady(d *appsv1.Deployment) bool {
+	want := desiredReplicas(d)
+	return want > 0 && d.Status.AvailableReplicas >= want
+}
+
+func podReady(p *corev1.Pod) bool {
+	for _, cond := range p.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// shortAge renders a duration the way kubectl's AGE column does (s/m/h/d).
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,453 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/randybias/tentacular/pkg/builder"
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+// directTestManifests renders the Deployment and Service for a workflow and
+// converts them to the map form commands pass to WfApply.
+func directTestManifests(t *testing.T, name string) []map[string]any {
+	t.Helper()
+	wf := &spec.Workflow{
+		Name:     name,
+		Version:  "1.0",
+		Triggers: []spec.Trigger{{Type: "manual"}},
+		Nodes:    map[string]spec.NodeSpec{"fetch": {Path: "./nodes/fetch.ts"}},
+	}
+	var out []map[string]any
+	for _, m := range builder.GenerateK8sManifests(wf, "engine:latest", "ignored", builder.DeployOptions{}) {
+		var obj map[string]any
+		if err := yaml.Unmarshal([]byte(m.Content), &obj); err != nil {
+			t.Fatalf("parsing %s manifest: %v", m.Kind, err)
+		}
+		out = append(out, obj)
+	}
+	return out
+}
+
+func TestDirectClient_ApplyCreatesThenUpdates(t *testing.T) {
+	cs := fake.NewClientset()
+	c := NewDirectClientFromClientset(cs)
+	ctx := context.Background()
+
+	res, err := c.WfApply(ctx, "prod", "hello", directTestManifests(t, "hello"))
+	if err != nil {
+		t.Fatalf("first apply: %v", err)
+	}
+	if res.Status != "created" || res.Updated != 0 {
+		t.Errorf("expected created with 0 updates, got %+v", res)
+	}
+	if strings.Join(res.Applied, ",") != "Deployment/hello,Service/hello" {
+		t.Errorf("unexpected applied list: %v", res.Applied)
+	}
+
+	dep, err := cs.AppsV1().Deployments("prod").Get(ctx, "hello", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("deployment not created in target namespace: %v", err)
+	}
+	if dep.Spec.Template.Spec.Containers[0].Name != "engine" {
+		t.Errorf("expected engine container, got %+v", dep.Spec.Template.Spec.Containers)
+	}
+
+	res, err = c.WfApply(ctx, "prod", "hello", directTestManifests(t, "hello"))
+	if err != nil {
+		t.Fatalf("second apply: %v", err)
+	}
+	if res.Status != "updated" || res.Updated != 2 {
+		t.Errorf("expected updated with 2 updates, got %+v", res)
+	}
+	dep, _ = cs.AppsV1().Deployments("prod").Get(ctx, "hello", metav1.GetOptions{})
+	if dep.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] == "" {
+		t.Error("expected rollout restart annotation after update")
+	}
+}
+
+func TestDirectClient_ApplyUnsupportedKind(t *testing.T) {
+	c := NewDirectClientFromClientset(fake.NewClientset())
+	_, err := c.WfApply(context.Background(), "prod", "hello", []map[string]any{{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata":   map[string]any{"name": "hello-cron"},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "not supported in direct mode") {
+		t.Fatalf("expected unsupported kind error, got %v", err)
+	}
+}
+
+func TestDirectClient_Remove(t *testing.T) {
+	labels := map[string]string{labelName: "hello", labelManagedBy: managedByValue}
+	cs := fake.NewClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "prod", Labels: labels}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "prod", Labels: labels}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "hello-code", Namespace: "prod", Labels: labels}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "hello-secrets", Namespace: "prod"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-code", Namespace: "prod",
+			Labels: map[string]string{labelName: "other"}}},
+	)
+	so := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]any{"name": "hello", "namespace": "prod", "labels": map[string]any{labelName: "hello"}},
+	}}
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), so)
+	c := NewDirectClientFromClients(cs, dyn)
+	ctx := context.Background()
+
+	res, err := c.WfRemove(ctx, "prod", "hello")
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	want := "ScaledObject/hello,Deployment/hello,Service/hello,ConfigMap/hello-code,Secret/hello-secrets"
+	if strings.Join(res.Deleted, ",") != want {
+		t.Errorf("expected deleted %s, got %v", want, res.Deleted)
+	}
+	if res.DeletedCount != 5 {
+		t.Errorf("expected DeletedCount 5, got %d", res.DeletedCount)
+	}
+	if _, err := cs.CoreV1().ConfigMaps("prod").Get(ctx, "other-code", metav1.GetOptions{}); err != nil {
+		t.Errorf("other workflow's ConfigMap should survive: %v", err)
+	}
+	if _, err := dyn.Resource(scaledObjectGVR).Namespace("prod").Get(ctx, "hello", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the ScaledObject to be deleted, got %v", err)
+	}
+
+	res, err = c.WfRemove(ctx, "prod", "hello")
+	if err != nil {
+		t.Fatalf("second remove: %v", err)
+	}
+	if len(res.Deleted) != 0 {
+		t.Errorf("expected nothing deleted the second time, got %v", res.Deleted)
+	}
+}
+
+func TestDirectClient_StatusAndList(t *testing.T) {
+	replicas := int32(1)
+	labels := map[string]string{labelName: "hello", labelVersion: "2.0", labelManagedBy: managedByValue}
+	cs := fake.NewClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "prod", Labels: labels,
+				Annotations: map[string]string{"tentacular.io/description": "says hello"}},
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "esm-sh", Namespace: "prod",
+			Labels: map[string]string{labelName: "esm-sh", labelManagedBy: managedByValue}}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-abc", Namespace: "prod", Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "engine", Image: "engine:latest"}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "hello-abc.1", Namespace: "prod"},
+			InvolvedObject: corev1.ObjectReference{Name: "hello-abc"},
+			Type:           "Normal", Reason: "Started", Count: 1,
+		},
+	)
+	c := NewDirectClientFromClientset(cs)
+	ctx := context.Background()
+
+	st, err := c.WfStatus(ctx, "prod", "hello", true)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !st.Ready || st.Version != "2.0" || st.Available != 1 || st.Replicas != 1 {
+		t.Errorf("unexpected status: %+v", st)
+	}
+	if len(st.Pods) != 1 || !st.Pods[0].Ready || st.Pods[0].NodeName != "node-1" {
+		t.Errorf("unexpected pods: %+v", st.Pods)
+	}
+	if len(st.Events) != 1 || st.Events[0].Reason != "Started" {
+		t.Errorf("unexpected events: %+v", st.Events)
+	}
+
+	items, err := c.WfList(ctx, "prod")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "hello" || items[0].Description != "says hello" {
+		t.Errorf("expected only the hello workflow, got %+v", items)
+	}
+
+	pods, err := c.WfPods(ctx, "prod")
+	if err != nil {
+		t.Fatalf("pods: %v", err)
+	}
+	if len(pods.Pods) != 1 || pods.Pods[0].Images[0] != "engine:latest" {
+		t.Errorf("unexpected pods: %+v", pods.Pods)
+	}
+
+	logs, err := c.WfLogs(ctx, "prod", "hello-abc", 50)
+	if err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	if logs.Container != "engine" || logs.LogText() == "" {
+		t.Errorf("unexpected logs result: %+v", logs)
+	}
+
+	sidecar, err := c.WfLogsQuery(ctx, mcp.WfLogsParams{Namespace: "prod", Pod: "hello-abc", Container: "proxy", SinceSeconds: 60})
+	if err != nil {
+		t.Fatalf("logs query: %v", err)
+	}
+	if sidecar.Container != "proxy" {
+		t.Errorf("expected requested container, got %+v", sidecar)
+	}
+}
+
+func TestDirectClient_StatusNotFound(t *testing.T) {
+	c := NewDirectClientFromClientset(fake.NewClientset())
+	if _, err := c.WfStatus(context.Background(), "prod", "missing", false); err == nil {
+		t.Fatal("expected error for missing deployment")
+	}
+}
+
+func TestDirectClient_StatusPodReason(t *testing.T) {
+	replicas := int32(1)
+	labels := map[string]string{labelName: "hello", labelManagedBy: managedByValue}
+	cs := fake.NewClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "prod", Labels: labels, Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, ObservedGeneration: 2},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-abc", Namespace: "prod", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "engine",
+					RestartCount: 4,
+					State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}},
+			},
+		},
+	)
+	st, err := NewDirectClientFromClientset(cs).WfStatus(context.Background(), "prod", "hello", true)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if st.Updated != 1 || st.Generation != 2 || st.ObservedGeneration != 2 {
+		t.Errorf("unexpected rollout progress: %+v", st)
+	}
+	if len(st.Pods) != 1 || st.Pods[0].Reason != "CrashLoopBackOff" || st.Pods[0].Restarts != 4 {
+		t.Errorf("unexpected pods: %+v", st.Pods)
+	}
+}
+
+func TestDirectClient_LiveManifests(t *testing.T) {
+	cs := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello-secrets", Namespace: "prod"},
+		Data:       map[string][]byte{"token": []byte("s3cret")},
+	})
+	c := NewDirectClientFromClientset(cs)
+	ctx := context.Background()
+	if _, err := c.WfApply(ctx, "prod", "hello", directTestManifests(t, "hello")); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	manifests, err := c.LiveManifests(ctx, "prod", "hello")
+	if err != nil {
+		t.Fatalf("live manifests: %v", err)
+	}
+	var got []string
+	for _, m := range manifests {
+		meta, _ := m["metadata"].(map[string]any)
+		got = append(got, m["apiVersion"].(string)+" "+m["kind"].(string)+"/"+meta["name"].(string))
+	}
+	if strings.Join(got, ",") != "v1 Secret/hello-secrets,v1 Service/hello,apps/v1 Deployment/hello" {
+		t.Errorf("unexpected live manifests: %v", got)
+	}
+
+	if none, err := c.LiveManifests(ctx, "prod", "missing"); err != nil || len(none) != 0 {
+		t.Errorf("expected no live objects for an undeployed workflow, got %d (%v)", len(none), err)
+	}
+}
+
+func TestDirectClient_DeployLock(t *testing.T) {
+	c := NewDirectClientFromClientset(fake.NewClientset())
+	ctx := context.Background()
+	lock := func(holder, id string, steal bool) *mcp.WfLockResult {
+		t.Helper()
+		res, err := c.WfLock(ctx, mcp.WfLockParams{Namespace: "prod", Name: "hello", Holder: holder, LockID: id, TTLSeconds: 60, Steal: steal})
+		if err != nil {
+			t.Fatalf("lock: %v", err)
+		}
+		return res
+	}
+
+	if res := lock("alice@example.com", "a1", false); !res.Acquired || res.Lock.Holder != "alice@example.com" {
+		t.Fatalf("expected alice to acquire a free lock, got %+v", res)
+	}
+	if res := lock("alice@example.com", "a1", false); !res.Acquired {
+		t.Errorf("expected the holder to renew its own lock, got %+v", res)
+	}
+	res := lock("bob@example.com", "b1", false)
+	if res.Acquired || res.Lock.Holder != "alice@example.com" || res.Lock.ExpiresAt == "" {
+		t.Fatalf("expected bob blocked by alice's lock, got %+v", res)
+	}
+	if res := lock("bob@example.com", "b1", true); !res.Acquired || res.StolenFrom == nil || res.StolenFrom.Holder != "alice@example.com" {
+		t.Fatalf("expected bob to steal alice's lock, got %+v", res)
+	}
+
+	if res, err := c.WfUnlock(ctx, "prod", "hello", "a1"); err != nil || res.Released {
+		t.Errorf("expected alice's stale unlock to leave bob's lock, got %+v (%v)", res, err)
+	}
+	if res, err := c.WfUnlock(ctx, "prod", "hello", "b1"); err != nil || !res.Released {
+		t.Errorf("expected bob to release the lock, got %+v (%v)", res, err)
+	}
+	if res := lock("carol@example.com", "c1", false); !res.Acquired {
+		t.Errorf("expected a released lock to be free, got %+v", res)
+	}
+}
+
+func TestDirectClient_Revisions(t *testing.T) {
+	c := NewDirectClientFromClientset(fake.NewClientset())
+	ctx := context.Background()
+	if res, err := c.WfRevisions(ctx, "prod", "hello"); err != nil || len(res.Revisions) != 0 {
+		t.Fatalf("expected no revisions before the first record, got %+v (%v)", res, err)
+	}
+	for i, data := range []string{`{"image":"engine:1"}`, `{"image":"engine:2"}`, `{"image":"engine:3"}`} {
+		res, err := c.WfRecordRevision(ctx, mcp.WfRecordRevisionParams{Namespace: "prod", Name: "hello", Data: []byte(data), Limit: 2})
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		if res.Revision != i+1 {
+			t.Errorf("expected revision %d, got %d", i+1, res.Revision)
+		}
+	}
+
+	res, err := c.WfRevisions(ctx, "prod", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Revisions) != 2 || res.Revisions[0].Revision != 3 || string(res.Revisions[0].Data) != `{"image":"engine:3"}` || res.Revisions[1].Revision != 2 {
+		t.Errorf("expected revisions 3 and 2 kept, got %+v", res.Revisions)
+	}
+
+	// The history survives undeploy and stays out of the workflow's live objects.
+	if _, err := c.WfRemove(ctx, "prod", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := c.WfRevisions(ctx, "prod", "hello"); len(res.Revisions) != 2 {
+		t.Errorf("expected revisions kept after undeploy, got %+v", res.Revisions)
+	}
+	if live, _ := c.LiveManifests(ctx, "prod", "hello"); len(live) != 0 {
+		t.Errorf("expected the revisions ConfigMap excluded from live manifests, got %d", len(live))
+	}
+}
+
+func TestDirectClient_SuspendResume(t *testing.T) {
+	replicas := int32(3)
+	labels := map[string]string{labelName: "hello", labelVersion: "2.0", labelManagedBy: managedByValue}
+	cs := fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: "prod", Labels: labels,
+			Annotations: map[string]string{mcp.AnnotationCronSchedule: "0 * * * *,30 2 * * *"}},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+	c := NewDirectClientFromClientset(cs)
+	ctx := context.Background()
+
+	res, err := c.WfSuspend(ctx, "prod", "hello", "alice@example.com")
+	if err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+	if !res.Changed || !res.Suspended || res.CronSchedule != "0 * * * *,30 2 * * *" {
+		t.Errorf("unexpected suspend result %+v", res)
+	}
+	items, _ := c.WfList(ctx, "prod")
+	if len(items) != 1 || !items[0].Suspended || items[0].Replicas != 0 || items[0].SuspendedBy != "alice@example.com" {
+		t.Errorf("expected a suspended workflow with 0 replicas listed, got %+v", items)
+	}
+	d, _ := cs.AppsV1().Deployments("prod").Get(ctx, "hello", metav1.GetOptions{})
+	if _, ok := d.Annotations[mcp.AnnotationCronSchedule]; ok || d.Annotations[mcp.AnnotationSuspendedCronSchedule] != "0 * * * *,30 2 * * *" {
+		t.Errorf("expected the cron schedule set aside so the scheduler stops firing, got %v", d.Annotations)
+	}
+	if res, err := c.WfSuspend(ctx, "prod", "hello", "bob@example.com"); err != nil || res.Changed {
+		t.Errorf("expected suspending twice to change nothing, got %+v (%v)", res, err)
+	}
+
+	res, err = c.WfResume(ctx, "prod", "hello")
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if !res.Changed || res.Suspended || res.Replicas != 3 || res.CronSchedule == "" {
+		t.Errorf("expected 3 replicas and the cron schedule restored, got %+v", res)
+	}
+	d, _ = cs.AppsV1().Deployments("prod").Get(ctx, "hello", metav1.GetOptions{})
+	if _, ok := d.Annotations[mcp.AnnotationSuspended]; ok || *d.Spec.Replicas != 3 {
+		t.Errorf("expected the suspend annotations removed and replicas restored, got %v / %d", d.Annotations, *d.Spec.Replicas)
+	}
+	if _, ok := d.Annotations[mcp.AnnotationSuspendedCronSchedule]; ok || d.Annotations[mcp.AnnotationCronSchedule] != "0 * * * *,30 2 * * *" {
+		t.Errorf("expected the cron schedule restored, got %v", d.Annotations)
+	}
+	if _, err := c.WfSuspend(ctx, "prod", "missing", ""); err == nil {
+		t.Error("expected an error for an unknown workflow")
+	}
+}
+
+func TestDirectClient_SuspendPausesScaledObject(t *testing.T) {
+	replicas := int32(2)
+	cs := fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "prod",
+			Labels: map[string]string{labelName: "orders", labelVersion: "1.0"}},
+		Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+	so := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]any{"name": "orders", "namespace": "prod", "labels": map[string]any{labelName: "orders"}},
+		"spec":       map[string]any{"scaleTargetRef": map[string]any{"name": "orders"}},
+	}}
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), so)
+	c := NewDirectClientFromClients(cs, dyn)
+	ctx := context.Background()
+	paused := func() string {
+		t.Helper()
+		obj, err := dyn.Resource(scaledObjectGVR).Namespace("prod").Get(ctx, "orders", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj.GetAnnotations()[annotationKEDAPaused]
+	}
+
+	if _, err := c.WfSuspend(ctx, "prod", "orders", "alice"); err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+	if paused() != "true" {
+		t.Error("expected the ScaledObject paused so KEDA does not scale the suspended workflow back up")
+	}
+	if _, err := c.WfResume(ctx, "prod", "orders"); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if paused() != "" {
+		t.Error("expected the ScaledObject unpaused on resume")
+	}
+
+	// A workflow without a ScaledObject suspends as before.
+	if _, err := cs.AppsV1().Deployments("prod").Create(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "prod"},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WfSuspend(ctx, "prod", "plain", "alice"); err != nil {
+		t.Errorf("expected a workflow without a ScaledObject to suspend, got %v", err)
+	}
+}
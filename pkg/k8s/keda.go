@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/randybias/tentacular/pkg/builder"
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+const (
+	defaultJetStreamAccount = "$G"
+	defaultLagThreshold     = 10
+)
+
+// GenerateScaledObject creates a KEDA ScaledObject for a workflow with
+// deployment.autoscaling, with one NATS JetStream scaler per queue trigger.
+// Returns nil if the workflow has no autoscaling or no queue trigger.
+//
+// The scaler reads consumer lag from the NATS server's monitoring endpoint,
+// not from the engine, so the workflow NetworkPolicy needs no ingress rule
+// for the KEDA operator.
+func GenerateScaledObject(wf *spec.Workflow, namespace string) *builder.Manifest {
+	a := wf.Deployment.Autoscaling
+	if a == nil {
+		return nil
+	}
+
+	account := a.JetStream.Account
+	if account == "" {
+		account = defaultJetStreamAccount
+	}
+	lagThreshold := a.LagThreshold
+	if lagThreshold == 0 {
+		lagThreshold = defaultLagThreshold
+	}
+	endpoint := spec.NATSMonitoringEndpoint(wf)
+
+	var triggers strings.Builder
+	for _, t := range wf.Triggers {
+		if t.Type != "queue" || t.Subject == "" {
+			continue
+		}
+		fmt.Fprintf(&triggers, `  # Queue trigger subject: %s
+  - type: nats-jetstream
+    metadata:
+      natsServerMonitoringEndpoint: %q
+      account: %q
+      stream: %q
+      consumer: %q
+      lagThreshold: "%d"
+`, t.Subject, endpoint, account, a.JetStream.Stream, JetStreamConsumerName(wf.Name, t.Subject), lagThreshold)
+	}
+	if triggers.Len() == 0 {
+		return nil
+	}
+
+	var timing strings.Builder
+	if a.PollingInterval > 0 {
+		fmt.Fprintf(&timing, "  pollingInterval: %d\n", a.PollingInterval)
+	}
+	if a.CooldownPeriod > 0 {
+		fmt.Fprintf(&timing, "  cooldownPeriod: %d\n", a.CooldownPeriod)
+	}
+
+	manifest := fmt.Sprintf(`apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    app.kubernetes.io/name: %s
+    app.kubernetes.io/managed-by: tentacular
+spec:
+  scaleTargetRef:
+    name: %s
+  minReplicaCount: %d
+  maxReplicaCount: %d
+%s  triggers:
+%s`,
+		wf.Name,
+		namespace,
+		wf.Name,
+		wf.Name,
+		a.Min(),
+		a.MaxReplicas,
+		timing.String(),
+		triggers.String(),
+	)
+
+	return &builder.Manifest{
+		Kind:    "ScaledObject",
+		Name:    wf.Name,
+		Content: manifest,
+	}
+}
+
+// JetStreamConsumerName returns the durable consumer the scaler reads for a
+// queue trigger subject: <workflow>-<subject>, with "." replaced by "-" and
+// the wildcards "*" and ">" by "any" and "all". The engine creates and
+// consumes the same consumer (consumerName in engine/triggers/nats.ts).
+func JetStreamConsumerName(workflow, subject string) string {
+	token := strings.NewReplacer(".", "-", "*", "any", ">", "all", " ", "-").Replace(subject)
+	return workflow + "-" + strings.ToLower(token)
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/randybias/tentacular/pkg/spec"
+)
+
+func TestGenerateScaledObject(t *testing.T) {
+	zero := 0
+	wf := &spec.Workflow{
+		Name: "orders",
+		Triggers: []spec.Trigger{
+			{Type: "queue", Subject: "orders.created"},
+			{Type: "manual"},
+			{Type: "queue", Subject: "orders.*"},
+		},
+		Deployment: spec.DeploymentConfig{Autoscaling: &spec.AutoscalingConfig{
+			MinReplicas:    &zero,
+			MaxReplicas:    4,
+			CooldownPeriod: 120,
+			JetStream:      spec.JetStreamScaler{Stream: "ORDERS", MonitoringEndpoint: "nats.exo:8222"},
+		}},
+	}
+	m := GenerateScaledObject(wf, "team-a")
+	if m == nil || m.Kind != "ScaledObject" || m.Name != "orders" {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+	var obj struct {
+		Metadata struct {
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+		Spec struct {
+			ScaleTargetRef struct {
+				Name string `yaml:"name"`
+			} `yaml:"scaleTargetRef"`
+			MinReplicaCount int  `yaml:"minReplicaCount"`
+			MaxReplicaCount int  `yaml:"maxReplicaCount"`
+			CooldownPeriod  int  `yaml:"cooldownPeriod"`
+			PollingInterval *int `yaml:"pollingInterval"`
+			Triggers        []struct {
+				Type     string            `yaml:"type"`
+				Metadata map[string]string `yaml:"metadata"`
+			} `yaml:"triggers"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(m.Content), &obj); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, m.Content)
+	}
+	s := obj.Spec
+	if obj.Metadata.Namespace != "team-a" || s.ScaleTargetRef.Name != "orders" || s.MinReplicaCount != 0 || s.MaxReplicaCount != 4 || s.CooldownPeriod != 120 || s.PollingInterval != nil {
+		t.Errorf("unexpected spec %+v", s)
+	}
+	if len(s.Triggers) != 2 {
+		t.Fatalf("expected one scaler per queue trigger, got %d", len(s.Triggers))
+	}
+	want := map[string]string{
+		"natsServerMonitoringEndpoint": "nats.exo:8222",
+		"account":                      "$G",
+		"stream":                       "ORDERS",
+		"consumer":                     "orders-orders-created",
+		"lagThreshold":                 "10",
+	}
+	for k, v := range want {
+		if got := s.Triggers[0].Metadata[k]; s.Triggers[0].Type != "nats-jetstream" || got != v {
+			t.Errorf("trigger metadata %s: expected %q, got %q", k, v, got)
+		}
+	}
+	if got := s.Triggers[1].Metadata["consumer"]; got != "orders-orders-any" {
+		t.Errorf("expected the wildcard subject's consumer orders-orders-any, got %q", got)
+	}
+
+	wf.Deployment.Autoscaling = nil
+	if m := GenerateScaledObject(wf, "team-a"); m != nil {
+		t.Errorf("expected no ScaledObject without autoscaling, got %s", m.Content)
+	}
+}
+
+func TestJetStreamConsumerName(t *testing.T) {
+	// The engine names its consumers the same way (engine/triggers/nats.ts).
+	for subject, want := range map[string]string{
+		"orders.created": "orders-orders-created",
+		"events.*.push":  "orders-events-any-push",
+		"Events.>":       "orders-events-all",
+	} {
+		if got := JetStreamConsumerName("orders", subject); got != want {
+			t.Errorf("JetStreamConsumerName(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
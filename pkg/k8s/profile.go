@@ -86,14 +86,77 @@ type StorageClassInfo struct {
 
 // ExtensionSet records which well-known CRD-based extensions are installed.
 type ExtensionSet struct {
-	OtherCRDGroups  []string `json:"otherCRDGroups"  yaml:"otherCRDGroups"`
-	Istio           bool     `json:"istio"           yaml:"istio"`
-	CertManager     bool     `json:"certManager"     yaml:"certManager"`
-	PrometheusOp    bool     `json:"prometheusOp"    yaml:"prometheusOp"`
-	ExternalSecrets bool     `json:"externalSecrets" yaml:"externalSecrets"`
-	ArgoCD          bool     `json:"argoCD"          yaml:"argoCD"`
-	GatewayAPI      bool     `json:"gatewayAPI"      yaml:"gatewayAPI"`
-	MetricsServer   bool     `json:"metricsServer"   yaml:"metricsServer"`
+	OtherCRDGroups   []string `json:"otherCRDGroups"   yaml:"otherCRDGroups"`
+	Istio            bool     `json:"istio"            yaml:"istio"`
+	CertManager      bool     `json:"certManager"      yaml:"certManager"`
+	PrometheusOp     bool     `json:"prometheusOp"     yaml:"prometheusOp"`
+	ExternalSecrets  bool     `json:"externalSecrets"  yaml:"externalSecrets"`
+	ArgoCD           bool     `json:"argoCD"           yaml:"argoCD"`
+	GatewayAPI       bool     `json:"gatewayAPI"       yaml:"gatewayAPI"`
+	MetricsServer    bool     `json:"metricsServer"    yaml:"metricsServer"`
+	KEDA             bool     `json:"keda"             yaml:"keda"`
+	Kyverno          bool     `json:"kyverno"          yaml:"kyverno"`
+	Gatekeeper       bool     `json:"gatekeeper"       yaml:"gatekeeper"`
+	Knative          bool     `json:"knative"          yaml:"knative"`
+	Cilium           bool     `json:"cilium"           yaml:"cilium"`
+	CiliumFQDNPolicy bool     `json:"ciliumFQDNPolicy" yaml:"ciliumFQDNPolicy"` // CiliumNetworkPolicy CRDs present (toFQDNs egress)
+	Calico           bool     `json:"calico"           yaml:"calico"`
+	Linkerd          bool     `json:"linkerd"          yaml:"linkerd"`
+	SPIRE            bool     `json:"spire"            yaml:"spire"`
+	Velero           bool     `json:"velero"           yaml:"velero"`
+	Flux             bool     `json:"flux"             yaml:"flux"`
+}
+
+// extensionDef describes a well-known extension: its display name, the CRD API
+// groups that identify it, and the ExtensionSet flag it sets.
+type extensionDef struct {
+	flag   func(e *ExtensionSet) *bool
+	name   string
+	groups []string // a CRD group matches if it equals, or is a subdomain of, one of these
+}
+
+// extensionDefs is the detection table for classifyExtensions, in display order.
+// Entries without groups (metrics-server is an APIService, not a CRD) are only
+// rendered, never detected from CRDs.
+var extensionDefs = []extensionDef{
+	{name: "Istio", groups: []string{"istio.io"}, flag: func(e *ExtensionSet) *bool { return &e.Istio }},
+	{name: "cert-manager", groups: []string{"cert-manager.io"}, flag: func(e *ExtensionSet) *bool { return &e.CertManager }},
+	{name: "Prometheus Operator", groups: []string{"monitoring.coreos.com"}, flag: func(e *ExtensionSet) *bool { return &e.PrometheusOp }},
+	{name: "External Secrets", groups: []string{"external-secrets.io"}, flag: func(e *ExtensionSet) *bool { return &e.ExternalSecrets }},
+	{name: "ArgoCD", groups: []string{"argoproj.io"}, flag: func(e *ExtensionSet) *bool { return &e.ArgoCD }},
+	{name: "Gateway API", groups: []string{"gateway.networking.k8s.io"}, flag: func(e *ExtensionSet) *bool { return &e.GatewayAPI }},
+	{name: "Metrics Server", flag: func(e *ExtensionSet) *bool { return &e.MetricsServer }},
+	{name: "KEDA", groups: []string{"keda.sh"}, flag: func(e *ExtensionSet) *bool { return &e.KEDA }},
+	{name: "Kyverno", groups: []string{"kyverno.io"}, flag: func(e *ExtensionSet) *bool { return &e.Kyverno }},
+	{name: "OPA Gatekeeper", groups: []string{"gatekeeper.sh"}, flag: func(e *ExtensionSet) *bool { return &e.Gatekeeper }},
+	{name: "Knative", groups: []string{"knative.dev"}, flag: func(e *ExtensionSet) *bool { return &e.Knative }},
+	{name: "Cilium", groups: []string{"cilium.io"}, flag: func(e *ExtensionSet) *bool { return &e.Cilium }},
+	{name: "Calico", groups: []string{"projectcalico.org"}, flag: func(e *ExtensionSet) *bool { return &e.Calico }},
+	{name: "Linkerd", groups: []string{"linkerd.io"}, flag: func(e *ExtensionSet) *bool { return &e.Linkerd }},
+	{name: "SPIRE", groups: []string{"spire.spiffe.io"}, flag: func(e *ExtensionSet) *bool { return &e.SPIRE }},
+	{name: "Velero", groups: []string{"velero.io"}, flag: func(e *ExtensionSet) *bool { return &e.Velero }},
+	{name: "Flux", groups: []string{"fluxcd.io"}, flag: func(e *ExtensionSet) *bool { return &e.Flux }},
+}
+
+// matches reports whether a CRD API group belongs to this extension.
+func (d extensionDef) matches(group string) bool {
+	for _, g := range d.groups {
+		if group == g || strings.HasSuffix(group, "."+g) {
+			return true
+		}
+	}
+	return false
+}
+
+// Installed returns the display names of installed well-known extensions, in table order.
+func (e ExtensionSet) Installed() []string {
+	var names []string
+	for _, d := range extensionDefs {
+		if *d.flag(&e) {
+			names = append(names, d.name)
+		}
+	}
+	return names
 }
 
 // QuotaSummary contains resource quota limits for the target namespace.
@@ -170,6 +233,12 @@ func isRWXCapable(provisioner string) bool {
 	return false
 }
 
+// ciliumPolicyCRDs are the Cilium policy CRDs whose egress rules support toFQDNs.
+var ciliumPolicyCRDs = map[string]bool{
+	"ciliumnetworkpolicies.cilium.io":            true,
+	"ciliumclusterwidenetworkpolicies.cilium.io": true,
+}
+
 func classifyExtensions(crdList *unstructured.UnstructuredList) ExtensionSet {
 	ext := ExtensionSet{}
 	knownGroups := map[string]bool{}
@@ -182,26 +251,23 @@ func classifyExtensions(crdList *unstructured.UnstructuredList) ExtensionSet {
 		}
 		group := parts[1]
 
-		switch {
-		case strings.HasSuffix(group, ".istio.io") || group == "networking.istio.io" || group == "security.istio.io":
-			ext.Istio = true
-		case group == "cert-manager.io" || strings.HasSuffix(group, ".cert-manager.io"):
-			ext.CertManager = true
-		case group == "monitoring.coreos.com":
-			ext.PrometheusOp = true
-		case group == "external-secrets.io" || strings.HasSuffix(group, ".external-secrets.io"):
-			ext.ExternalSecrets = true
-		case group == "argoproj.io":
-			ext.ArgoCD = true
-		case group == "gateway.networking.k8s.io":
-			ext.GatewayAPI = true
-		default:
-			// Collect unknown CRD groups for the agent
-			if !knownGroups[group] {
-				knownGroups[group] = true
-				ext.OtherCRDGroups = append(ext.OtherCRDGroups, group)
+		if ciliumPolicyCRDs[name] {
+			ext.CiliumFQDNPolicy = true
+		}
+
+		matched := false
+		for _, d := range extensionDefs {
+			if d.matches(group) {
+				*d.flag(&ext) = true
+				matched = true
+				break
 			}
 		}
+		// Collect unknown CRD groups for the agent
+		if !matched && !knownGroups[group] {
+			knownGroups[group] = true
+			ext.OtherCRDGroups = append(ext.OtherCRDGroups, group)
+		}
 	}
 	sort.Strings(ext.OtherCRDGroups)
 	// Cap to 20 entries to avoid bloating agent context on heavily-operatored clusters.
@@ -277,6 +343,41 @@ func deriveGuidance(p *ClusterProfile) []string {
 		g = append(g, "cert-manager available — TLS certificates can be provisioned automatically")
 	}
 
+	if p.Extensions.KEDA {
+		g = append(g, "KEDA detected — queue-triggered tentacles can use event-driven autoscaling via ScaledObjects, including scale-to-zero")
+	}
+	if p.Extensions.Kyverno {
+		g = append(g, "Kyverno detected — admission policies may mutate or reject generated manifests; check PolicyReports if a deploy is rejected")
+	}
+	if p.Extensions.Gatekeeper {
+		g = append(g, "OPA Gatekeeper detected — constraints may reject generated Deployments or NetworkPolicies; review active constraints with 'kubectl get constraints'")
+	}
+	if p.Extensions.Knative {
+		g = append(g, "Knative detected — tentacles still deploy as plain Deployments; Knative eventing sources can deliver events to webhook triggers")
+	}
+	if p.Extensions.Cilium {
+		if p.Extensions.CiliumFQDNPolicy {
+			g = append(g, "Cilium with CiliumNetworkPolicy detected — contract dependency hosts can be enforced with toFQDNs egress rules instead of CIDR allowlists")
+		} else {
+			g = append(g, "Cilium detected — NetworkPolicy egress rules are enforced")
+		}
+	}
+	if p.Extensions.Calico {
+		g = append(g, "Calico detected — NetworkPolicy egress rules are enforced; GlobalNetworkPolicies may add cluster-wide egress restrictions")
+	}
+	if p.Extensions.Linkerd {
+		g = append(g, "Linkerd detected — meshed pods get mTLS; generated NetworkPolicies must allow traffic from the linkerd control-plane namespace")
+	}
+	if p.Extensions.SPIRE {
+		g = append(g, "SPIRE detected — SPIFFE workload identities are available for service-to-service authentication")
+	}
+	if p.Extensions.Velero {
+		g = append(g, "Velero detected — enclave namespaces (tentacle ConfigMaps and Secrets) can be included in Velero backup schedules")
+	}
+	if p.Extensions.Flux {
+		g = append(g, "Flux detected — if Flux reconciles the enclave namespace it may revert tentacles deployed by tntc; exclude tentacle resources from Flux Kustomizations")
+	}
+
 	// Exoskeleton guidance
 	if p.Exoskeleton.Enabled && len(p.Exoskeleton.Services) > 0 {
 		g = append(g, fmt.Sprintf("Exoskeleton services detected: %s — use tentacular-* prefix in contracts to reference these services",
@@ -379,13 +480,12 @@ func (p *ClusterProfile) Markdown() string {
 		}
 		return "✗"
 	}
-	fmt.Fprintf(&sb, "- %s Istio\n", checkmark(ext.Istio))
-	fmt.Fprintf(&sb, "- %s cert-manager\n", checkmark(ext.CertManager))
-	fmt.Fprintf(&sb, "- %s Prometheus Operator\n", checkmark(ext.PrometheusOp))
-	fmt.Fprintf(&sb, "- %s External Secrets\n", checkmark(ext.ExternalSecrets))
-	fmt.Fprintf(&sb, "- %s ArgoCD\n", checkmark(ext.ArgoCD))
-	fmt.Fprintf(&sb, "- %s Gateway API\n", checkmark(ext.GatewayAPI))
-	fmt.Fprintf(&sb, "- %s Metrics Server\n", checkmark(ext.MetricsServer))
+	for _, d := range extensionDefs {
+		fmt.Fprintf(&sb, "- %s %s\n", checkmark(*d.flag(&ext)), d.name)
+	}
+	if ext.CiliumFQDNPolicy {
+		fmt.Fprintf(&sb, "- Cilium FQDN egress policy: supported\n")
+	}
 	if len(ext.OtherCRDGroups) > 0 {
 		fmt.Fprintf(&sb, "- Other CRD groups: %s\n", strings.Join(ext.OtherCRDGroups, ", "))
 	}
@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Profile change kinds.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// ProfileChange is a single difference between two ClusterProfile snapshots.
+type ProfileChange struct {
+	Field  string `json:"field"`  // e.g. "k8sVersion", "runtimeClass", "extension"
+	Change string `json:"change"` // "added" | "removed" | "changed"
+	Name   string `json:"name,omitempty"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// String renders the change as a single diff-style line.
+func (c ProfileChange) String() string {
+	label := c.Field
+	if c.Name != "" {
+		label += " " + c.Name
+	}
+	switch c.Change {
+	case ChangeAdded:
+		if c.New != "" && c.New != c.Name {
+			return fmt.Sprintf("+ %s: %s", label, c.New)
+		}
+		return "+ " + label
+	case ChangeRemoved:
+		if c.Old != "" && c.Old != c.Name {
+			return fmt.Sprintf("- %s: %s", label, c.Old)
+		}
+		return "- " + label
+	default:
+		return fmt.Sprintf("~ %s: %s → %s", label, orNone(c.Old), orNone(c.New))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// DiffProfiles reports capability drift between two profile snapshots: K8s
+// version, CNI, runtime classes, storage classes, extensions, quota and limit
+// ranges. Node topology and guidance text are intentionally not compared.
+func DiffProfiles(prev, next *ClusterProfile) []ProfileChange {
+	var changes []ProfileChange
+	changed := func(field, oldV, newV string) {
+		if oldV != newV {
+			changes = append(changes, ProfileChange{Field: field, Change: ChangeChanged, Old: oldV, New: newV})
+		}
+	}
+
+	changed("k8sVersion", prev.K8sVersion, next.K8sVersion)
+	changed("distribution", prev.Distribution, next.Distribution)
+	changed("gvisor", strconv.FormatBool(prev.GVisor), strconv.FormatBool(next.GVisor))
+
+	changed("cni", prev.CNI.Name, next.CNI.Name)
+	changed("cniVersion", prev.CNI.Version, next.CNI.Version)
+	changed("networkPolicySupported", strconv.FormatBool(prev.CNI.NetworkPolicySupported), strconv.FormatBool(next.CNI.NetworkPolicySupported))
+	changed("egressSupported", strconv.FormatBool(prev.CNI.EgressSupported), strconv.FormatBool(next.CNI.EgressSupported))
+
+	changes = append(changes, diffNamed("runtimeClass", runtimeClassMap(prev.RuntimeClasses), runtimeClassMap(next.RuntimeClasses))...)
+	changes = append(changes, diffNamed("storageClass", storageClassMap(prev.StorageClasses), storageClassMap(next.StorageClasses))...)
+	changed("defaultStorageClass", defaultStorageClass(prev.StorageClasses), defaultStorageClass(next.StorageClasses))
+
+	changes = append(changes, diffNamed("extension", extensionMap(prev.Extensions), extensionMap(next.Extensions))...)
+	changes = append(changes, diffNamed("crdGroup", setMap(prev.Extensions.OtherCRDGroups), setMap(next.Extensions.OtherCRDGroups))...)
+
+	changes = append(changes, diffNamed("quota", quotaMap(prev.Quota), quotaMap(next.Quota))...)
+	changes = append(changes, diffNamed("limitRange", limitRangeMap(prev.LimitRange), limitRangeMap(next.LimitRange))...)
+
+	changed("podSecurity", prev.PodSecurity, next.PodSecurity)
+	return changes
+}
+
+// diffNamed compares two name→value maps and reports added, removed and
+// changed entries in name order.
+func diffNamed(field string, prev, next map[string]string) []ProfileChange {
+	names := make([]string, 0, len(prev)+len(next))
+	seen := map[string]bool{}
+	for n := range prev {
+		names = append(names, n)
+		seen[n] = true
+	}
+	for n := range next {
+		if !seen[n] {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []ProfileChange
+	for _, n := range names {
+		oldV, hadOld := prev[n]
+		newV, hasNew := next[n]
+		switch {
+		case hadOld && !hasNew:
+			changes = append(changes, ProfileChange{Field: field, Change: ChangeRemoved, Name: n, Old: oldV})
+		case !hadOld && hasNew:
+			changes = append(changes, ProfileChange{Field: field, Change: ChangeAdded, Name: n, New: newV})
+		case oldV != newV:
+			changes = append(changes, ProfileChange{Field: field, Change: ChangeChanged, Name: n, Old: oldV, New: newV})
+		}
+	}
+	return changes
+}
+
+func runtimeClassMap(rcs []RuntimeClassInfo) map[string]string {
+	m := make(map[string]string, len(rcs))
+	for _, rc := range rcs {
+		m[rc.Name] = rc.Handler
+	}
+	return m
+}
+
+func storageClassMap(scs []StorageClassInfo) map[string]string {
+	m := make(map[string]string, len(scs))
+	for _, sc := range scs {
+		m[sc.Name] = sc.Provisioner
+	}
+	return m
+}
+
+func defaultStorageClass(scs []StorageClassInfo) string {
+	for _, sc := range scs {
+		if sc.IsDefault {
+			return sc.Name
+		}
+	}
+	return ""
+}
+
+// extensionMap returns the installed well-known extensions keyed by display name.
+func extensionMap(e ExtensionSet) map[string]string {
+	m := map[string]string{}
+	for _, name := range e.Installed() {
+		m[name] = name
+	}
+	if e.CiliumFQDNPolicy {
+		m["Cilium FQDN policy"] = "Cilium FQDN policy"
+	}
+	return m
+}
+
+func setMap(items []string) map[string]string {
+	m := make(map[string]string, len(items))
+	for _, s := range items {
+		m[s] = s
+	}
+	return m
+}
+
+func quotaMap(q *QuotaSummary) map[string]string {
+	m := map[string]string{}
+	if q == nil {
+		return m
+	}
+	putNonEmpty(m, "cpuRequest", q.CPURequest)
+	putNonEmpty(m, "cpuLimit", q.CPULimit)
+	putNonEmpty(m, "memoryRequest", q.MemoryRequest)
+	putNonEmpty(m, "memoryLimit", q.MemoryLimit)
+	if q.MaxPods > 0 {
+		m["maxPods"] = strconv.Itoa(q.MaxPods)
+	}
+	return m
+}
+
+func limitRangeMap(lr *LimitRangeSummary) map[string]string {
+	m := map[string]string{}
+	if lr == nil {
+		return m
+	}
+	putNonEmpty(m, "defaultCPURequest", lr.DefaultCPURequest)
+	putNonEmpty(m, "defaultCPULimit", lr.DefaultCPULimit)
+	putNonEmpty(m, "defaultMemoryRequest", lr.DefaultMemoryRequest)
+	putNonEmpty(m, "defaultMemoryLimit", lr.DefaultMemoryLimit)
+	return m
+}
+
+func putNonEmpty(m map[string]string, k, v string) {
+	if v != "" {
+		m[k] = v
+	}
+}
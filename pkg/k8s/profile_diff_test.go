@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffProfiles_NoChanges(t *testing.T) {
+	p := &ClusterProfile{K8sVersion: "v1.30.0", GVisor: true, CNI: CNIInfo{Name: "calico"}}
+	if changes := DiffProfiles(p, p); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestDiffProfiles_UpgradeRemovedGVisor(t *testing.T) {
+	prev := &ClusterProfile{
+		K8sVersion:     "v1.30.2",
+		GVisor:         true,
+		RuntimeClasses: []RuntimeClassInfo{{Name: "gvisor", Handler: "runsc"}},
+		StorageClasses: []StorageClassInfo{
+			{Name: "standard", Provisioner: "rancher.io/local-path", IsDefault: true},
+		},
+	}
+	next := &ClusterProfile{
+		K8sVersion: "v1.31.0",
+		StorageClasses: []StorageClassInfo{
+			{Name: "standard", Provisioner: "rancher.io/local-path"},
+			{Name: "gp3", Provisioner: "ebs.csi.aws.com", IsDefault: true},
+		},
+	}
+
+	changes := DiffProfiles(prev, next)
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	joined := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"~ k8sVersion: v1.30.2 → v1.31.0",
+		"~ gvisor: true → false",
+		"- runtimeClass gvisor: runsc",
+		"+ storageClass gp3: ebs.csi.aws.com",
+		"~ defaultStorageClass: standard → gp3",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in diff:\n%s", want, joined)
+		}
+	}
+}
+
+func TestDiffProfiles_ExtensionsQuotaLimitRange(t *testing.T) {
+	prev := &ClusterProfile{
+		Extensions: ExtensionSet{Istio: true, OtherCRDGroups: []string{"foo.example.com"}},
+		Quota:      &QuotaSummary{CPULimit: "4"},
+	}
+	next := &ClusterProfile{
+		Extensions: ExtensionSet{CertManager: true},
+		Quota:      &QuotaSummary{CPULimit: "8", MaxPods: 20},
+		LimitRange: &LimitRangeSummary{DefaultMemoryLimit: "512Mi"},
+	}
+	changes := DiffProfiles(prev, next)
+
+	want := map[string]string{
+		"extension/Istio":               ChangeRemoved,
+		"extension/cert-manager":        ChangeAdded,
+		"crdGroup/foo.example.com":      ChangeRemoved,
+		"quota/cpuLimit":                ChangeChanged,
+		"quota/maxPods":                 ChangeAdded,
+		"limitRange/defaultMemoryLimit": ChangeAdded,
+	}
+	got := map[string]string{}
+	for _, c := range changes {
+		got[c.Field+"/"+c.Name] = c.Change
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: expected %s, got %q (all: %v)", k, v, got[k], changes)
+		}
+	}
+}
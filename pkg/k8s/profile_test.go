@@ -255,6 +255,66 @@ func TestClassifyExtensions_Empty(t *testing.T) {
 	}
 }
 
+func TestClassifyExtensions_Table(t *testing.T) {
+	tests := []struct {
+		name  string
+		crds  []string
+		check func(ExtensionSet) bool
+	}{
+		{"KEDA", []string{"scaledobjects.keda.sh", "triggerauthentications.keda.sh"}, func(e ExtensionSet) bool { return e.KEDA }},
+		{"Kyverno", []string{"clusterpolicies.kyverno.io", "policyexceptions.kyverno.io"}, func(e ExtensionSet) bool { return e.Kyverno }},
+		{"Gatekeeper", []string{"constrainttemplates.templates.gatekeeper.sh", "configs.config.gatekeeper.sh"}, func(e ExtensionSet) bool { return e.Gatekeeper }},
+		{"Knative", []string{"services.serving.knative.dev", "brokers.eventing.knative.dev"}, func(e ExtensionSet) bool { return e.Knative }},
+		{"Cilium", []string{"ciliumendpoints.cilium.io"}, func(e ExtensionSet) bool { return e.Cilium && !e.CiliumFQDNPolicy }},
+		{"CiliumFQDN", []string{"ciliumnetworkpolicies.cilium.io"}, func(e ExtensionSet) bool { return e.Cilium && e.CiliumFQDNPolicy }},
+		{"Calico", []string{"felixconfigurations.crd.projectcalico.org"}, func(e ExtensionSet) bool { return e.Calico }},
+		{"Linkerd", []string{"serviceprofiles.linkerd.io", "servers.policy.linkerd.io"}, func(e ExtensionSet) bool { return e.Linkerd }},
+		{"SPIRE", []string{"clusterspiffeids.spire.spiffe.io"}, func(e ExtensionSet) bool { return e.SPIRE }},
+		{"Velero", []string{"backups.velero.io", "schedules.velero.io"}, func(e ExtensionSet) bool { return e.Velero }},
+		{"Flux", []string{"kustomizations.kustomize.toolkit.fluxcd.io", "gitrepositories.source.toolkit.fluxcd.io"}, func(e ExtensionSet) bool { return e.Flux }},
+		{"ExternalSecrets", []string{"externalsecrets.external-secrets.io"}, func(e ExtensionSet) bool { return e.ExternalSecrets }},
+		{"GatewayAPI", []string{"httproutes.gateway.networking.k8s.io"}, func(e ExtensionSet) bool { return e.GatewayAPI }},
+		{"ArgoCD", []string{"applications.argoproj.io"}, func(e ExtensionSet) bool { return e.ArgoCD }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]unstructured.Unstructured, 0, len(tt.crds))
+			for _, n := range tt.crds {
+				items = append(items, makeUnstructured(n))
+			}
+			ext := classifyExtensions(&unstructured.UnstructuredList{Items: items})
+			if !tt.check(ext) {
+				t.Errorf("expected %s detected from %v, got %+v", tt.name, tt.crds, ext)
+			}
+			if len(ext.OtherCRDGroups) != 0 {
+				t.Errorf("expected no other CRD groups, got %v", ext.OtherCRDGroups)
+			}
+		})
+	}
+}
+
+func TestClassifyExtensions_NoFalseSuffixMatch(t *testing.T) {
+	// "notkeda.sh" must not match the keda.sh domain.
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		makeUnstructured("widgets.notkeda.sh"),
+	}}
+	ext := classifyExtensions(list)
+	if ext.KEDA {
+		t.Error("expected KEDA = false for unrelated group")
+	}
+	if len(ext.OtherCRDGroups) != 1 {
+		t.Errorf("expected group collected as other, got %v", ext.OtherCRDGroups)
+	}
+}
+
+func TestExtensionSetInstalled(t *testing.T) {
+	ext := ExtensionSet{Istio: true, KEDA: true, Flux: true}
+	got := strings.Join(ext.Installed(), ",")
+	if got != "Istio,KEDA,Flux" {
+		t.Errorf("expected table order Istio,KEDA,Flux, got %s", got)
+	}
+}
+
 // --- detectPodSecurity ---
 
 func TestDetectPodSecurity_Restricted(t *testing.T) {
@@ -334,6 +394,41 @@ func TestDeriveGuidance_Quota(t *testing.T) {
 	}
 }
 
+func TestDeriveGuidance_NewExtensions(t *testing.T) {
+	tests := []struct {
+		ext  ExtensionSet
+		want string
+	}{
+		{ExtensionSet{KEDA: true}, "KEDA detected"},
+		{ExtensionSet{Kyverno: true}, "Kyverno detected"},
+		{ExtensionSet{Gatekeeper: true}, "OPA Gatekeeper detected"},
+		{ExtensionSet{Knative: true}, "Knative detected"},
+		{ExtensionSet{Cilium: true}, "Cilium detected"},
+		{ExtensionSet{Cilium: true, CiliumFQDNPolicy: true}, "toFQDNs"},
+		{ExtensionSet{Calico: true}, "Calico detected"},
+		{ExtensionSet{Linkerd: true}, "Linkerd detected"},
+		{ExtensionSet{SPIRE: true}, "SPIRE detected"},
+		{ExtensionSet{Velero: true}, "Velero detected"},
+		{ExtensionSet{Flux: true}, "Flux detected"},
+	}
+	for _, tt := range tests {
+		g := deriveGuidance(&ClusterProfile{Extensions: tt.ext})
+		if !containsSubstr(g, tt.want) {
+			t.Errorf("expected guidance containing %q for %+v, got: %v", tt.want, tt.ext, g)
+		}
+	}
+}
+
+func TestMarkdown_ListsNewExtensions(t *testing.T) {
+	p := &ClusterProfile{Extensions: ExtensionSet{KEDA: true, CiliumFQDNPolicy: true}}
+	md := p.Markdown()
+	for _, want := range []string{"✓ KEDA", "✗ Velero", "Cilium FQDN egress policy: supported"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q", want)
+		}
+	}
+}
+
 // --- Markdown output ---
 
 func TestMarkdown_ContainsSections(t *testing.T) {
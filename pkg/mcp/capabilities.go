@@ -0,0 +1,245 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MinServerVersion is the oldest tentacular-mcp release this CLI supports.
+const MinServerVersion = "0.1.0"
+
+// RequiredTools are the tools every tntc workflow command depends on. A server
+// missing any of them is incompatible.
+var RequiredTools = []string{
+	"wf_apply", "wf_remove", "wf_status", "wf_list", "wf_pods", "wf_logs", "wf_run",
+}
+
+// OptionalTools back individual features (enclaves, describe, async runs,
+// run history, health, deploy locks, suspend/resume, deploy revisions, audit,
+// cluster profiles). A server without them still works; only those features
+// fail.
+var OptionalTools = []string{
+	"wf_describe", "wf_run_status", "wf_runs", "wf_health", "wf_lock", "wf_unlock", "wf_suspend", "wf_resume",
+	"wf_revisions", "wf_record_revision",
+	"audit_resources", "cluster_profile",
+	"enclave_preflight", "enclave_provision", "enclave_info", "enclave_list",
+	"enclave_sync", "enclave_deprovision",
+}
+
+// toolFeatures names the tntc feature each tool backs, for errors and reports
+// about tools a server does not provide.
+var toolFeatures = map[string]string{
+	"wf_apply":            "tntc deploy",
+	"wf_remove":           "tntc undeploy",
+	"wf_status":           "tntc status",
+	"wf_list":             "tntc list",
+	"wf_pods":             "tntc status",
+	"wf_logs":             "tntc logs",
+	"wf_run":              "tntc run",
+	"wf_describe":         "tntc describe",
+	"wf_run_status":       "async runs (run --async, run wait)",
+	"wf_runs":             "run history",
+	"wf_health":           "tntc health",
+	"wf_lock":             "deploy locks",
+	"wf_unlock":           "deploy locks",
+	"wf_suspend":          "tntc suspend",
+	"wf_resume":           "tntc resume",
+	"wf_revisions":        "revision history in the cluster",
+	"wf_record_revision":  "revision history in the cluster",
+	"audit_resources":     "tntc audit",
+	"cluster_profile":     "cluster profiles",
+	"enclave_preflight":   "cluster check",
+	"enclave_provision":   "enclaves",
+	"enclave_info":        "enclaves",
+	"enclave_list":        "enclaves",
+	"enclave_sync":        "enclaves",
+	"enclave_deprovision": "enclaves",
+}
+
+// ToolFeature returns the tntc feature that needs tool, or "" if unknown.
+func ToolFeature(tool string) string {
+	return toolFeatures[tool]
+}
+
+// ToolInfo describes a tool advertised by the server.
+type ToolInfo struct {
+	Name   string   `json:"name"`
+	Params []string `json:"params,omitempty"` // top-level input schema properties, sorted
+}
+
+// Capabilities is what the server advertised when the session was established.
+type Capabilities struct {
+	// Tools is keyed by tool name; nil when the server could not list its tools.
+	Tools         map[string]ToolInfo `json:"tools,omitempty"`
+	ServerName    string              `json:"server_name"`
+	ServerVersion string              `json:"server_version"`
+}
+
+// HasTool reports whether the server advertised tool. It returns true when the
+// tool list is unknown, so callers only fail on positive evidence.
+func (c *Capabilities) HasTool(tool string) bool {
+	if c == nil || c.Tools == nil {
+		return true
+	}
+	_, ok := c.Tools[tool]
+	return ok
+}
+
+// Compatibility is the result of comparing server capabilities against what
+// this CLI needs.
+type Compatibility struct {
+	ServerName       string   `json:"server_name"`
+	ServerVersion    string   `json:"server_version"`
+	MinServerVersion string   `json:"min_server_version"`
+	MissingTools     []string `json:"missing_tools,omitempty"`
+	UnavailableTools []string `json:"unavailable_tools,omitempty"`
+	Problems         []string `json:"problems,omitempty"`
+	ToolCount        int      `json:"tool_count"`
+	Compatible       bool     `json:"compatible"`
+}
+
+// CheckCompatibility compares caps with RequiredTools, OptionalTools and
+// MinServerVersion. Non-release server versions (e.g. "dev") skip the version
+// check and are judged on their tool list alone.
+func CheckCompatibility(caps *Capabilities) Compatibility {
+	result := Compatibility{
+		ServerName:       caps.ServerName,
+		ServerVersion:    caps.ServerVersion,
+		MinServerVersion: MinServerVersion,
+		ToolCount:        len(caps.Tools),
+	}
+	if caps.Tools == nil {
+		result.Problems = append(result.Problems, "server did not list its tools; compatibility unknown")
+	}
+	for _, tool := range RequiredTools {
+		if !caps.HasTool(tool) {
+			result.MissingTools = append(result.MissingTools, tool)
+		}
+	}
+	for _, tool := range OptionalTools {
+		if !caps.HasTool(tool) {
+			result.UnavailableTools = append(result.UnavailableTools, tool)
+		}
+	}
+	if len(result.MissingTools) > 0 {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("server too old: missing required tools %s (needs tentacular-mcp >= %s)",
+				strings.Join(result.MissingTools, ", "), MinServerVersion))
+	}
+	cmp, ok := compareVersions(caps.ServerVersion, MinServerVersion)
+	tooOld := ok && cmp < 0
+	if tooOld {
+		result.Problems = append(result.Problems,
+			fmt.Sprintf("server version %s is older than the minimum %s", caps.ServerVersion, MinServerVersion))
+	}
+	result.Compatible = len(result.MissingTools) == 0 && !tooOld
+	return result
+}
+
+// Capabilities returns the capabilities advertised by the server, connecting
+// first if needed.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	if _, err := c.connect(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.caps, nil
+}
+
+// knownCapabilities returns the capabilities recorded by the last connect, or
+// nil before the first connect.
+func (c *Client) knownCapabilities() *Capabilities {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.caps
+}
+
+// discoverCapabilities reads the server identity and tool list from a freshly
+// established session. A failure to list tools is not fatal: the tool list
+// is left unknown and calls proceed as before.
+func discoverCapabilities(ctx context.Context, session *mcpsdk.ClientSession) *Capabilities {
+	caps := &Capabilities{}
+	if init := session.InitializeResult(); init != nil && init.ServerInfo != nil {
+		caps.ServerName = init.ServerInfo.Name
+		caps.ServerVersion = init.ServerInfo.Version
+	}
+	tools := map[string]ToolInfo{}
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return caps
+		}
+		tools[tool.Name] = ToolInfo{Name: tool.Name, Params: schemaParams(tool.InputSchema)}
+	}
+	caps.Tools = tools
+	return caps
+}
+
+// schemaParams returns the sorted top-level property names of a JSON schema.
+func schemaParams(schema any) []string {
+	m, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	props, ok := m["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	params := make([]string, 0, len(props))
+	for name := range props {
+		params = append(params, name)
+	}
+	slices.Sort(params)
+	return params
+}
+
+// compareVersions compares dotted numeric versions, ignoring a leading "v" and
+// any pre-release or build suffix. ok is false when either is not a release
+// version.
+func compareVersions(a, b string) (cmp int, ok bool) {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
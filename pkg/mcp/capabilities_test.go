@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+		ok   bool
+	}{
+		{"0.1.0", "0.1.0", 0, true},
+		{"v0.2.1", "0.10.0", -1, true},
+		{"1.0", "0.9.9", 1, true},
+		{"1.2.0-rc.1", "1.2", 0, true},
+		{"dev", "0.1.0", 0, false},
+		{"", "0.1.0", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := compareVersions(tc.a, tc.b)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("compareVersions(%q, %q) = %d, %v; want %d, %v", tc.a, tc.b, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	all := map[string]ToolInfo{}
+	for _, name := range append(slices.Clone(RequiredTools), OptionalTools...) {
+		all[name] = ToolInfo{Name: name}
+	}
+	if got := CheckCompatibility(&Capabilities{ServerVersion: "dev", Tools: all}); !got.Compatible || len(got.Problems) != 0 {
+		t.Errorf("expected full tool set to be compatible, got %+v", got)
+	}
+
+	delete(all, "wf_logs")
+	delete(all, "enclave_sync")
+	got := CheckCompatibility(&Capabilities{ServerVersion: "0.5.0", Tools: all})
+	if got.Compatible || !slices.Equal(got.MissingTools, []string{"wf_logs"}) {
+		t.Errorf("expected wf_logs to be reported missing, got %+v", got)
+	}
+	if !slices.Equal(got.UnavailableTools, []string{"enclave_sync"}) {
+		t.Errorf("expected enclave_sync to be an unavailable feature, got %+v", got.UnavailableTools)
+	}
+
+	if got := CheckCompatibility(&Capabilities{ServerVersion: "0.0.9", Tools: all}); got.Compatible {
+		t.Errorf("expected version below minimum to be incompatible, got %+v", got)
+	}
+	if got := CheckCompatibility(&Capabilities{}); !got.Compatible || len(got.Problems) != 1 {
+		t.Errorf("unknown tool list should be compatible with a warning, got %+v", got)
+	}
+}
+
+func TestCapabilities_DiscoveredOnConnect(t *testing.T) {
+	srv, client := makeTestServer(t, map[string]func(map[string]any) (string, bool){
+		"wf_status": func(map[string]any) (string, bool) { return `{}`, false },
+	})
+	defer srv.Close()
+	defer func() { _ = client.Close() }()
+
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if caps.ServerName != "test-mcp" || !caps.HasTool("wf_status") || caps.HasTool("wf_apply") {
+		t.Errorf("unexpected capabilities: %+v", caps)
+	}
+}
+
+func TestToolFeature_CoversKnownTools(t *testing.T) {
+	for _, tool := range append(append([]string{}, RequiredTools...), OptionalTools...) {
+		if ToolFeature(tool) == "" {
+			t.Errorf("tool %s has no feature", tool)
+		}
+	}
+}
+
+func TestCallTool_UnadvertisedToolIsServerTooOld(t *testing.T) {
+	srv, client := makeTestServer(t, map[string]func(map[string]any) (string, bool){
+		"wf_status": func(map[string]any) (string, bool) { return `{}`, false },
+	})
+	defer srv.Close()
+	defer func() { _ = client.Close() }()
+
+	_, err := client.CallTool(context.Background(), "wf_describe", nil)
+	if !IsServerTooOld(err) {
+		t.Fatalf("expected ServerTooOldError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "tntc describe needs tool") || strings.Contains(err.Error(), MinServerVersion) {
+		t.Errorf("expected the missing tool and its feature without a minimum version, got %v", err)
+	}
+	if m := client.Metrics(); m.Attempts != 1 {
+		t.Errorf("missing tools must not be retried, got %d attempts", m.Attempts)
+	}
+}
@@ -5,6 +5,7 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -19,15 +20,20 @@ type Config struct {
 	Endpoint string        // e.g. "http://tentacular-mcp.tentacular-system.svc.cluster.local:8080/mcp"
 	Token    string        // OIDC access token for authentication
 	Timeout  time.Duration // Per-request timeout (default: 30s)
+	Retry    RetryPolicy   // Retry and backoff for tool calls (zero value: defaults)
 }
 
 // Client communicates with the tentacular-mcp server via the MCP protocol.
 type Client struct {
 	httpClient *http.Client // used for Ping (healthz) only
 	session    *mcpsdk.ClientSession
+	caps       *Capabilities
+	metrics    CallMetrics
 	baseURL    string
 	token      string
+	retry      RetryPolicy
 	mu         sync.Mutex
+	metricsMu  sync.Mutex
 	timeout    time.Duration
 }
 
@@ -42,6 +48,7 @@ func NewClient(cfg Config) *Client {
 		baseURL: cfg.Endpoint,
 		token:   cfg.Token,
 		timeout: timeout,
+		retry:   cfg.Retry.withDefaults(timeout),
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -76,6 +83,7 @@ func (c *Client) connect(ctx context.Context) (*mcpsdk.ClientSession, error) {
 	}
 
 	c.session = session
+	c.caps = discoverCapabilities(ctx, session)
 	return session, nil
 }
 
@@ -98,36 +106,117 @@ func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 // CallTool invokes a named MCP tool with typed params and returns the raw JSON result.
-// The MCP session is established on first call.
+// The MCP session is established on first call and re-established when the
+// server has expired it.
+//
+// Failed attempts are retried with exponential backoff according to the
+// client's RetryPolicy: read-only tools (see IsReadOnlyTool) on any transient
+// failure, other tools only when the request never reached the server. Tool
+// errors, auth failures and tools the server does not advertise
+// (ServerTooOldError) are never retried. All attempts share the policy's
+// deadline budget.
 func (c *Client) CallTool(ctx context.Context, tool string, params any) (json.RawMessage, error) {
-	session, err := c.connect(ctx)
+	args, err := toArgsMap(params)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("marshaling tool arguments: %w", err)
 	}
 
-	args, err := toArgsMap(params)
+	ctx, cancel := context.WithTimeout(ctx, c.retry.Budget)
+	defer cancel()
+
+	attempts, reconnects := 0, 0
+	for {
+		attempts++
+		raw, sent, reconnect, err := c.callOnce(ctx, tool, args)
+		if reconnect {
+			reconnects++
+		}
+		if err == nil {
+			c.recordMetrics(tool, attempts, reconnects, false)
+			return raw, nil
+		}
+		if attempts >= c.retry.MaxAttempts || !shouldRetry(tool, err, sent) ||
+			!sleepCtx(ctx, c.retry.backoff(attempts)) {
+			c.recordMetrics(tool, attempts, reconnects, true)
+			return nil, err
+		}
+	}
+}
+
+// callOnce makes a single attempt at a tool call. sent reports whether the
+// request may have reached the server; reconnect reports whether the cached
+// session was discarded so the next attempt opens a new one.
+func (c *Client) callOnce(ctx context.Context, tool string, args map[string]any) (raw json.RawMessage, sent, reconnect bool, err error) {
+	session, err := c.connect(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling tool arguments: %w", err)
+		return nil, false, false, err
+	}
+	if caps := c.knownCapabilities(); !caps.HasTool(tool) {
+		return nil, false, false, &ServerTooOldError{Tool: tool, Feature: ToolFeature(tool), ServerVersion: caps.ServerVersion}
 	}
 
-	result, err := session.CallTool(ctx, &mcpsdk.CallToolParams{
+	trace := &sendTrace{}
+	result, err := session.CallTool(trace.context(ctx), &mcpsdk.CallToolParams{
 		Name:      tool,
 		Arguments: args,
 	})
 	if err != nil {
-		return nil, mapCallError(c.baseURL, err)
+		if isSessionMissing(err) {
+			// The server never handled the call on a missing session, so it is
+			// safe to repeat it on a fresh one.
+			c.resetSession(session)
+			return nil, false, true, &ServerUnavailableError{Endpoint: c.baseURL, Cause: err}
+		}
+		sent = trace.wrote.Load()
+		if !sent && ctx.Err() == nil {
+			c.resetSession(session)
+			return nil, false, true, &ServerUnavailableError{Endpoint: c.baseURL, Cause: err}
+		}
+		if errors.Is(err, mcpsdk.ErrConnectionClosed) {
+			// The session is unusable, but the call may already have run, so
+			// only a read-only tool may be repeated on a fresh one.
+			c.resetSession(session)
+			return nil, true, true, mapCallError(c.baseURL, err)
+		}
+		return nil, sent, false, mapCallError(c.baseURL, err)
 	}
 
 	if result.IsError {
 		msg := extractTextContent(result.Content)
-		return nil, &ToolError{Tool: tool, Message: msg}
+		return nil, true, false, &ToolError{Tool: tool, Message: msg}
 	}
 
 	text := extractTextContent(result.Content)
 	if text == "" {
-		return json.RawMessage("{}"), nil
+		return json.RawMessage("{}"), true, false, nil
+	}
+	return json.RawMessage(text), true, false, nil
+}
+
+// resetSession drops session if it is still the cached one, so the next call
+// connects again.
+func (c *Client) resetSession(session *mcpsdk.ClientSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session == session {
+		_ = session.Close()
+		c.session = nil
 	}
-	return json.RawMessage(text), nil
+}
+
+func (c *Client) recordMetrics(tool string, attempts, reconnects int, failed bool) {
+	c.metricsMu.Lock()
+	defer c.metricsMu.Unlock()
+	c.metrics.record(tool, attempts, reconnects, failed)
+}
+
+// Metrics returns a snapshot of the tool call metrics collected so far.
+func (c *Client) Metrics() CallMetrics {
+	c.metricsMu.Lock()
+	defer c.metricsMu.Unlock()
+	var m CallMetrics
+	m.Add(c.metrics)
+	return m
 }
 
 // Ping checks if the MCP server is reachable by calling GET /healthz.
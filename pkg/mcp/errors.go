@@ -39,6 +39,27 @@ func (e *ServerUnavailableError) Unwrap() error {
 	return e.Cause
 }
 
+// ServerTooOldError indicates the server does not provide a tool this CLI
+// needs, typically because it predates the tool.
+type ServerTooOldError struct {
+	Tool          string
+	Feature       string // the tntc feature that needs Tool (see ToolFeature)
+	ServerVersion string
+}
+
+func (e *ServerTooOldError) Error() string {
+	version := e.ServerVersion
+	if version == "" {
+		version = "unknown"
+	}
+	needs := fmt.Sprintf("tool %q", e.Tool)
+	if e.Feature != "" {
+		needs = fmt.Sprintf("%s needs tool %q", e.Feature, e.Tool)
+	}
+	return fmt.Sprintf("MCP server too old: %s, which this server (version %s) does not provide; upgrade tentacular-mcp to a release with %s",
+		needs, version, e.Tool)
+}
+
 // IsUnauthorized returns true if err is an HTTP 401 response from the MCP server.
 func IsUnauthorized(err error) bool {
 	var e *Error
@@ -77,3 +98,9 @@ func IsToolError(err error) bool {
 	var e *ToolError
 	return errors.As(err, &e)
 }
+
+// IsServerTooOld returns true if the MCP server lacks a tool this CLI needs.
+func IsServerTooOld(err error) bool {
+	var e *ServerTooOldError
+	return errors.As(err, &e)
+}
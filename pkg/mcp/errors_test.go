@@ -36,6 +36,14 @@ func TestServerUnavailableErrorString(t *testing.T) {
 	}
 }
 
+func TestServerTooOldErrorString(t *testing.T) {
+	e := &ServerTooOldError{Tool: "wf_suspend", Feature: "tntc suspend", ServerVersion: "0.3.0"}
+	want := `MCP server too old: tntc suspend needs tool "wf_suspend", which this server (version 0.3.0) does not provide; upgrade tentacular-mcp to a release with wf_suspend`
+	if e.Error() != want {
+		t.Errorf("got %q, want %q", e.Error(), want)
+	}
+}
+
 func TestServerUnavailableErrorUnwrap(t *testing.T) {
 	cause := errors.New("dial tcp: connection refused")
 	e := &ServerUnavailableError{Endpoint: "http://mcp:8080", Cause: cause}
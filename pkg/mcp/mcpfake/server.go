@@ -0,0 +1,251 @@
+// Package mcpfake implements an in-memory fake of the tentacular-mcp server
+// for offline development (tntc mcp fake) and, through mcptest, for tests.
+//
+// The fake speaks the real MCP streamable HTTP transport, so an mcp.Client
+// pointed at it exercises the same code paths as against a cluster. Workflow,
+// enclave and profile state lives in memory. Faults (tool errors, HTTP status
+// failures and latency) can be injected per tool.
+//
+// Mount Handler on any HTTP server:
+//
+//	fake := mcpfake.New()
+//	srv := &http.Server{Handler: fake.Handler()}
+package mcpfake
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/randybias/tentacular/pkg/k8s"
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// AnyTool matches every tool when passed to Server.Inject.
+const AnyTool = "*"
+
+// Fault describes an injected failure or delay for a tool.
+type Fault struct {
+	// Message, when non-empty, makes the tool return an error result
+	// (isError=true) with this text.
+	Message string
+	// Latency delays the tool call before it is handled (or fails).
+	Latency time.Duration
+	// Times limits the fault to the next N calls; 0 applies it to every call
+	// until ClearFaults.
+	Times int
+}
+
+// Server is an in-memory fake of the tentacular-mcp server.
+type Server struct {
+	profile     k8s.ClusterProfile
+	workflows   map[string]*Workflow
+	enclaves    map[string]*mcp.EnclaveInfoResult
+	faults      map[string]*Fault
+	calls       map[string]int
+	executions  map[string]*mcp.WfRunResult
+	finishes    map[string]time.Time // when each execution stops running
+	locks       map[string]*mcp.DeployLock
+	revisions   map[string]map[string]string // revisions ConfigMap data per workflow
+	httpFailure *httpFailure
+	mcpServer   *mcpsdk.Server
+	handler     http.Handler
+	mu          sync.Mutex
+
+	nextExecution int
+	ignoreRunWait bool
+}
+
+type httpFailure struct {
+	status int
+	times  int
+}
+
+// New creates a fake server with an empty store. Use Handler to mount it (or
+// mcptest.NewServer to start it on a loopback port for a test).
+func New() *Server {
+	s := &Server{
+		workflows:  map[string]*Workflow{},
+		enclaves:   map[string]*mcp.EnclaveInfoResult{},
+		faults:     map[string]*Fault{},
+		calls:      map[string]int{},
+		executions: map[string]*mcp.WfRunResult{},
+		finishes:   map[string]time.Time{},
+		locks:      map[string]*mcp.DeployLock{},
+		revisions:  map[string]map[string]string{},
+		profile:    DefaultProfile(),
+	}
+
+	mcpServer := mcpsdk.NewServer(&mcpsdk.Implementation{Name: "tentacular-mcp-fake", Version: "test"}, nil)
+	s.mcpServer = mcpServer
+	for name, fn := range s.tools() {
+		mcpServer.AddTool(&mcpsdk.Tool{
+			Name:        name,
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+		}, s.toolHandler(name, fn))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpsdk.NewStreamableHTTPHandler(func(*http.Request) *mcpsdk.Server { return mcpServer }, nil))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+	s.handler = s.httpFaults(mux)
+	return s
+}
+
+// Handler returns the HTTP handler serving /mcp and /healthz.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// RemoveTools stops advertising and serving the named tools, emulating an
+// older server. Clients that connect afterwards will not see them.
+func (s *Server) RemoveTools(names ...string) {
+	s.mcpServer.RemoveTools(names...)
+}
+
+// Inject registers a fault for tool (or AnyTool), replacing any previous one.
+func (s *Server) Inject(tool string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[tool] = &f
+}
+
+// FailRequests makes the next n HTTP requests to /mcp fail with status before
+// reaching the MCP handler (n <= 0 fails every request until ClearFaults).
+func (s *Server) FailRequests(status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.httpFailure = &httpFailure{status: status, times: n}
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string]*Fault{}
+	s.httpFailure = nil
+}
+
+// Calls returns how many times tool has been invoked (including failed calls).
+func (s *Server) Calls(tool string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[tool]
+}
+
+// takeFault returns the fault to apply to this call of tool, consuming one
+// use of a limited fault.
+func (s *Server) takeFault(tool string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[tool]++
+	for _, key := range []string{tool, AnyTool} {
+		f, ok := s.faults[key]
+		if !ok {
+			continue
+		}
+		applied := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				delete(s.faults, key)
+			}
+		}
+		return &applied
+	}
+	return nil
+}
+
+func (s *Server) httpFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/mcp" {
+			s.mu.Lock()
+			hf := s.httpFailure
+			status := 0
+			if hf != nil {
+				status = hf.status
+				if hf.times > 0 {
+					hf.times--
+					if hf.times == 0 {
+						s.httpFailure = nil
+					}
+				}
+			}
+			s.mu.Unlock()
+			if status != 0 {
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// toolFunc handles decoded tool arguments and returns a JSON-serializable result.
+type toolFunc func(args json.RawMessage) (any, error)
+
+func (s *Server) toolHandler(name string, fn toolFunc) mcpsdk.ToolHandler {
+	return func(ctx context.Context, req *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+		if f := s.takeFault(name); f != nil {
+			if f.Latency > 0 {
+				select {
+				case <-time.After(f.Latency):
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			if f.Message != "" {
+				return errorResult(f.Message), nil
+			}
+		}
+
+		args := req.Params.Arguments
+		if len(args) == 0 {
+			args = json.RawMessage(`{}`)
+		}
+		result, err := fn(args)
+		if err != nil {
+			return errorResult(err.Error()), nil
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return errorResult("marshaling result: " + err.Error()), nil
+		}
+		return &mcpsdk.CallToolResult{Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: string(data)}}}, nil
+	}
+}
+
+func errorResult(msg string) *mcpsdk.CallToolResult {
+	return &mcpsdk.CallToolResult{
+		IsError: true,
+		Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: msg}},
+	}
+}
+
+// DefaultProfile is the cluster profile served by a new fake: a gVisor-capable
+// cluster with an egress-enforcing CNI and no quota.
+func DefaultProfile() k8s.ClusterProfile {
+	return k8s.ClusterProfile{
+		K8sVersion:     "v1.31.0",
+		Distribution:   "fake",
+		GVisor:         true,
+		RuntimeClasses: []k8s.RuntimeClassInfo{{Name: "gvisor", Handler: "runsc"}},
+		CNI:            k8s.CNIInfo{Name: "calico", NetworkPolicySupported: true, EgressSupported: true},
+		StorageClasses: []k8s.StorageClassInfo{{Name: "standard", Provisioner: "fake.csi", IsDefault: true}},
+		PodSecurity:    "restricted",
+	}
+}
+
+// SetProfile replaces the profile returned by cluster_profile.
+func (s *Server) SetProfile(p k8s.ClusterProfile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profile = p
+}
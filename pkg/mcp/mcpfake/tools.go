@@ -0,0 +1,1019 @@
+package mcpfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+// Workflow is the fake's record of a deployed workflow.
+type Workflow struct {
+	CreatedAt   time.Time
+	Annotations map[string]string
+	Name        string
+	Namespace   string
+	Version     string
+	Description string
+	Image       string
+	Pod         string
+	Manifests   []map[string]any
+	Logs        []string
+	LogTimes    []time.Time // when each Logs line was written
+	// ContainerLogs holds the logs of containers other than the engine.
+	ContainerLogs map[string][]string
+	RunOutput     json.RawMessage
+	LastInput     json.RawMessage
+	RunNodes      []mcp.NodeTiming
+	Runs          int
+	// RunDuration is how long each execution stays running (0: it finishes at once).
+	RunDuration time.Duration
+	Replicas    int32
+	Available   int32
+	Ready       bool
+	// PodReason is the container waiting reason reported for the pod
+	// (e.g. CrashLoopBackOff); Restarts its restart count.
+	PodReason string
+	Restarts  int32
+	// Telemetry is the engine snapshot returned by wf_health (nil: an idle engine).
+	Telemetry *mcp.TelemetrySnapshot
+}
+
+func workflowKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// Workflow returns a copy of the stored workflow, if deployed.
+func (s *Server) Workflow(namespace, name string) (Workflow, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, ok := s.workflows[workflowKey(namespace, name)]
+	if !ok {
+		return Workflow{}, false
+	}
+	return *wf, true
+}
+
+// SetRunOutput sets the output returned by wf_run for a deployed workflow.
+func (s *Server) SetRunOutput(namespace, name string, output json.RawMessage) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) { wf.RunOutput = output })
+}
+
+// SetRunDuration makes each later execution of a deployed workflow stay
+// running for d before it finishes.
+func (s *Server) SetRunDuration(namespace, name string, d time.Duration) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) { wf.RunDuration = d })
+}
+
+// IgnoreRunWait makes wf_run_status answer at once regardless of
+// wait_seconds, emulating a server without long polling.
+func (s *Server) IgnoreRunWait(ignore bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ignoreRunWait = ignore
+}
+
+// SetRunNodes sets the per-node timings returned with each wf_run result.
+func (s *Server) SetRunNodes(namespace, name string, nodes []mcp.NodeTiming) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) { wf.RunNodes = nodes })
+}
+
+// SetLogs replaces the log lines returned by wf_logs for a deployed workflow.
+func (s *Server) SetLogs(namespace, name string, lines []string) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) {
+		wf.Logs = lines
+		wf.LogTimes = logTimes(len(lines))
+	})
+}
+
+// AppendLogs adds engine log lines, as a running workflow would.
+func (s *Server) AppendLogs(namespace, name string, lines ...string) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) {
+		wf.Logs = append(wf.Logs, lines...)
+		wf.LogTimes = append(wf.LogTimes, logTimes(len(lines))...)
+	})
+}
+
+// SetContainerLogs sets the log lines of a non-engine container (e.g. a sidecar).
+func (s *Server) SetContainerLogs(namespace, name, container string, lines []string) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) {
+		if wf.ContainerLogs == nil {
+			wf.ContainerLogs = map[string][]string{}
+		}
+		wf.ContainerLogs[container] = lines
+	})
+}
+
+// logTimes returns n strictly increasing timestamps ending now, so lines
+// written together keep their order when served with timestamps.
+func logTimes(n int) []time.Time {
+	now := time.Now().UTC()
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = now.Add(time.Duration(i-n+1) * time.Microsecond)
+	}
+	return times
+}
+
+// SetReady sets the readiness reported by wf_status, wf_list and wf_pods.
+func (s *Server) SetReady(namespace, name string, ready bool) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) {
+		wf.Ready = ready
+		wf.Available = 0
+		if ready {
+			wf.Available = wf.Replicas
+		}
+	})
+}
+
+// SetPodReason sets the container waiting reason reported for the workflow's
+// pod by wf_status. A non-empty reason marks the workflow not ready;
+// CrashLoopBackOff also counts a restart.
+func (s *Server) SetPodReason(namespace, name, reason string) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) {
+		wf.PodReason = reason
+		if reason != "" {
+			wf.Ready = false
+			wf.Available = 0
+		}
+		if reason == "CrashLoopBackOff" {
+			wf.Restarts++
+		}
+	})
+}
+
+// SetTelemetry sets the engine telemetry snapshot returned by wf_health.
+func (s *Server) SetTelemetry(namespace, name string, snapshot mcp.TelemetrySnapshot) error {
+	return s.updateWorkflow(namespace, name, func(wf *Workflow) { wf.Telemetry = &snapshot })
+}
+
+func (s *Server) updateWorkflow(namespace, name string, fn func(*Workflow)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, ok := s.workflows[workflowKey(namespace, name)]
+	if !ok {
+		return fmt.Errorf("workflow %s not found in enclave %s", name, namespace)
+	}
+	fn(wf)
+	return nil
+}
+
+// AddEnclave seeds an enclave. Status defaults to "active".
+func (s *Server) AddEnclave(info mcp.EnclaveInfoResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info.Status == "" {
+		info.Status = "active"
+	}
+	if info.CreatedAt == "" {
+		info.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.enclaves[info.Name] = &info
+}
+
+// Lock returns the deploy lock held on a workflow, if any (expired locks
+// included).
+func (s *Server) Lock(namespace, name string) (mcp.DeployLock, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.locks[workflowKey(namespace, name)]
+	if !ok {
+		return mcp.DeployLock{}, false
+	}
+	return *lock, true
+}
+
+// SetLock installs a deploy lock on a workflow, e.g. one held by another user.
+func (s *Server) SetLock(namespace, name string, lock mcp.DeployLock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locks[workflowKey(namespace, name)] = &lock
+}
+
+func (s *Server) tools() map[string]toolFunc {
+	return map[string]toolFunc{
+		"wf_apply":            s.wfApply,
+		"wf_remove":           s.wfRemove,
+		"wf_status":           s.wfStatus,
+		"wf_list":             s.wfList,
+		"wf_pods":             s.wfPods,
+		"wf_logs":             s.wfLogs,
+		"wf_run":              s.wfRun,
+		"wf_run_status":       s.wfRunStatus,
+		"wf_runs":             s.wfRuns,
+		"wf_health":           s.wfHealth,
+		"wf_describe":         s.wfDescribe,
+		"wf_lock":             s.wfLock,
+		"wf_unlock":           s.wfUnlock,
+		"wf_suspend":          s.wfSuspend,
+		"wf_resume":           s.wfResume,
+		"wf_revisions":        s.wfRevisions,
+		"wf_record_revision":  s.wfRecordRevision,
+		"audit_resources":     s.auditResources,
+		"cluster_profile":     s.clusterProfile,
+		"enclave_preflight":   s.enclavePreflight,
+		"enclave_provision":   s.enclaveProvision,
+		"enclave_info":        s.enclaveInfo,
+		"enclave_list":        s.enclaveList,
+		"enclave_sync":        s.enclaveSync,
+		"enclave_deprovision": s.enclaveDeprovision,
+	}
+}
+
+func decode[T any](args json.RawMessage) (T, error) {
+	var p T
+	if err := json.Unmarshal(args, &p); err != nil {
+		return p, fmt.Errorf("invalid arguments: %w", err)
+	}
+	return p, nil
+}
+
+// --- workflow tools ---
+
+func (s *Server) wfApply(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfApplyParams](args)
+	if err != nil {
+		return nil, err
+	}
+	if p.Name == "" || p.Namespace == "" {
+		return nil, fmt.Errorf("name and enclave are required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := workflowKey(p.Namespace, p.Name)
+	prev, existed := s.workflows[key]
+
+	wf := &Workflow{
+		Name:      p.Name,
+		Namespace: p.Namespace,
+		Manifests: p.Manifests,
+		CreatedAt: time.Now().UTC(),
+		Pod:       podName(p.Name, time.Now()),
+		Replicas:  1,
+		Available: 1,
+		Ready:     true,
+		Logs:      []string{fmt.Sprintf("[%s] workflow %s started", time.Now().UTC().Format(time.RFC3339), p.Name)},
+		LogTimes:  logTimes(1),
+	}
+	if existed {
+		wf.CreatedAt = prev.CreatedAt
+		wf.RunOutput = prev.RunOutput
+		wf.Runs = prev.Runs
+	}
+
+	result := &mcp.WfApplyResult{Applied: []string{}}
+	prevNames := map[string]bool{}
+	if existed {
+		for _, m := range prev.Manifests {
+			prevNames[manifestID(m)] = true
+		}
+	}
+	for _, m := range p.Manifests {
+		id := manifestID(m)
+		result.Applied = append(result.Applied, id)
+		if prevNames[id] {
+			result.Updated++
+		}
+		if kind, _ := m["kind"].(string); kind == "Deployment" {
+			meta, _ := m["metadata"].(map[string]any)
+			wf.Version = stringAt(meta, "labels", "app.kubernetes.io/version")
+			wf.Annotations = stringMap(meta["annotations"])
+			wf.Description = wf.Annotations["tentacular.io/description"]
+			wf.Image = deploymentImage(m)
+		}
+	}
+	s.workflows[key] = wf
+
+	result.Status = "created"
+	if result.Updated > 0 {
+		result.Status = "updated"
+	}
+	return result, nil
+}
+
+func (s *Server) wfRemove(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRemoveParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := workflowKey(p.Namespace, p.Name)
+	wf, ok := s.workflows[key]
+	result := &mcp.WfRemoveResult{Deleted: []string{}}
+	if !ok {
+		return result, nil
+	}
+	for _, m := range wf.Manifests {
+		result.Deleted = append(result.Deleted, manifestID(m))
+	}
+	result.DeletedCount = len(result.Deleted)
+	delete(s.workflows, key)
+	return result, nil
+}
+
+func (s *Server) lookup(namespace, name string) (*Workflow, error) {
+	wf, ok := s.workflows[workflowKey(namespace, name)]
+	if !ok {
+		return nil, fmt.Errorf("deployment %s not found in enclave %s", name, namespace)
+	}
+	return wf, nil
+}
+
+func (s *Server) wfStatus(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfStatusParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.WfStatusResult{
+		Name:      wf.Name,
+		Namespace: wf.Namespace,
+		Version:   wf.Version,
+		Replicas:  wf.Replicas,
+		Available: wf.Available,
+		Ready:     wf.Ready,
+	}
+	if p.Detail {
+		result.Pods = []mcp.PodInfo{{
+			Name:     wf.Pod,
+			Phase:    podPhase(wf),
+			NodeName: "fake-node",
+			Reason:   wf.PodReason,
+			Restarts: wf.Restarts,
+			Ready:    wf.Ready,
+		}}
+		result.Events = []mcp.EventInfo{{Type: "Normal", Reason: "Started", Message: "Started container engine", Count: 1}}
+	}
+	return result, nil
+}
+
+func (s *Server) wfList(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfListParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := []mcp.WfListItem{}
+	for _, wf := range s.sortedWorkflows(p.Namespace) {
+		items = append(items, mcp.WfListItem{
+			Name:        wf.Name,
+			Namespace:   wf.Namespace,
+			Version:     wf.Version,
+			Description: wf.Description,
+			Environment: wf.Annotations["tentacular.io/environment"],
+			DeployedBy:  wf.Annotations["tentacular.io/deployed-by"],
+			DeployedVia: wf.Annotations["tentacular.io/deployed-via"],
+			DeployedAt:  wf.Annotations["tentacular.io/deployed-at"],
+			SuspendedBy: wf.Annotations[mcp.AnnotationSuspendedBy],
+			SuspendedAt: wf.Annotations[mcp.AnnotationSuspendedAt],
+			CreatedAt:   wf.CreatedAt.Format(time.RFC3339),
+			Replicas:    wf.Replicas,
+			Available:   wf.Available,
+			Ready:       wf.Ready,
+			Suspended:   wf.Annotations[mcp.AnnotationSuspended] == "true",
+		})
+	}
+	return map[string]any{"workflows": items}, nil
+}
+
+func (s *Server) wfPods(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfPodsParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := &mcp.WfPodsResult{Pods: []mcp.WfPod{}}
+	for _, wf := range s.sortedWorkflows(p.Namespace) {
+		result.Pods = append(result.Pods, mcp.WfPod{
+			Name:   wf.Pod,
+			Phase:  podPhase(wf),
+			Age:    fmt.Sprintf("%ds", int(time.Since(wf.CreatedAt).Seconds())),
+			Images: []string{wf.Image},
+			Ready:  wf.Ready,
+		})
+	}
+	return result, nil
+}
+
+func (s *Server) wfLogs(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfLogsParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, wf := range s.sortedWorkflows(p.Namespace) {
+		if wf.Pod != p.Pod {
+			continue
+		}
+		container := p.Container
+		if container == "" {
+			container = "engine"
+		}
+		if container != "engine" {
+			lines, ok := wf.ContainerLogs[container]
+			if !ok {
+				return nil, fmt.Errorf("container %s is not valid for pod %s", container, wf.Pod)
+			}
+			return &mcp.WfLogsResult{Pod: wf.Pod, Container: container, Lines: append([]string{}, lines...)}, nil
+		}
+
+		var lines []string
+		for i, line := range wf.Logs {
+			at := time.Now().UTC()
+			if i < len(wf.LogTimes) {
+				at = wf.LogTimes[i]
+			}
+			if p.SinceSeconds > 0 && time.Since(at) > time.Duration(p.SinceSeconds)*time.Second {
+				continue
+			}
+			if p.Timestamps {
+				line = at.Format(time.RFC3339Nano) + " " + line
+			}
+			lines = append(lines, line)
+		}
+		if p.TailLines > 0 && int64(len(lines)) > p.TailLines {
+			lines = lines[int64(len(lines))-p.TailLines:]
+		}
+		return &mcp.WfLogsResult{Pod: wf.Pod, Container: container, Lines: append([]string{}, lines...)}, nil
+	}
+	return nil, fmt.Errorf("pod %s not found in enclave %s", p.Pod, p.Namespace)
+}
+
+func (s *Server) wfRun(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRunParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	wf.Runs++
+	wf.LastInput = p.Input
+	output := wf.RunOutput
+	if len(output) == 0 {
+		output = json.RawMessage(`{"success":true}`)
+	}
+
+	// Runs finish after the workflow's RunDuration; async callers see
+	// "running" first and collect the finished result with wf_run_status.
+	s.nextExecution++
+	result := &mcp.WfRunResult{
+		Name:        wf.Name,
+		Namespace:   wf.Namespace,
+		PodName:     wf.Pod,
+		ExecutionID: fmt.Sprintf("exec-%04d", s.nextExecution),
+		Status:      mcp.RunStatusSucceeded,
+		Output:      output,
+		Nodes:       wf.RunNodes,
+		StartedAt:   time.Now().UTC().Format(time.RFC3339),
+		DurationMs:  1,
+	}
+	s.executions[result.ExecutionID] = result
+	s.finishes[result.ExecutionID] = time.Now().Add(wf.RunDuration)
+	if p.Async {
+		return &mcp.WfRunResult{
+			Name:        wf.Name,
+			Namespace:   wf.Namespace,
+			ExecutionID: result.ExecutionID,
+			Status:      mcp.RunStatusRunning,
+		}, nil
+	}
+	return result, nil
+}
+
+func (s *Server) wfRunStatus(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRunStatusParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	result, ok := s.executions[p.ExecutionID]
+	finish, ignoreWait := s.finishes[p.ExecutionID], s.ignoreRunWait
+	s.mu.Unlock()
+	if !ok || result.Namespace != p.Namespace {
+		return nil, fmt.Errorf("execution %q not found in enclave %s", p.ExecutionID, p.Namespace)
+	}
+
+	// Long poll: hold the request until the execution finishes or the wait elapses.
+	if remaining := time.Until(finish); remaining > 0 && p.WaitSeconds > 0 && !ignoreWait {
+		time.Sleep(min(remaining, time.Duration(p.WaitSeconds)*time.Second))
+	}
+	if time.Now().Before(finish) {
+		return &mcp.WfRunResult{
+			Name:        result.Name,
+			Namespace:   result.Namespace,
+			ExecutionID: result.ExecutionID,
+			Status:      mcp.RunStatusRunning,
+		}, nil
+	}
+	return result, nil
+}
+
+func (s *Server) wfRuns(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRunsParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.lookup(p.Namespace, p.Name); err != nil {
+		return nil, err
+	}
+	runs := []mcp.WfRunResult{}
+	for i := s.nextExecution; i > 0 && (p.Limit <= 0 || len(runs) < p.Limit); i-- {
+		result := s.executions[fmt.Sprintf("exec-%04d", i)]
+		if result != nil && result.Namespace == p.Namespace && result.Name == p.Name {
+			runs = append(runs, *result)
+		}
+	}
+	return map[string]any{"runs": runs}, nil
+}
+
+func (s *Server) wfHealth(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfHealthParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.WfHealthResult{Name: wf.Name, Namespace: wf.Namespace, Status: "green"}
+	if !wf.Ready {
+		result.Status, result.Reason = "red", "deployment not ready"
+	}
+	if p.Detail {
+		snapshot := mcp.TelemetrySnapshot{Status: "ok", RecentEvents: []mcp.TelemetryEvent{}}
+		if wf.Telemetry != nil {
+			snapshot = *wf.Telemetry
+		}
+		result.Detail = &snapshot
+	}
+	return result, nil
+}
+
+func (s *Server) wfDescribe(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfDescribeParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.WfDescribeResult{
+		Name:        wf.Name,
+		Namespace:   wf.Namespace,
+		Image:       wf.Image,
+		Annotations: wf.Annotations,
+		Manifests:   wf.Manifests,
+		Replicas:    wf.Replicas,
+		Available:   wf.Available,
+		Ready:       wf.Ready,
+	}
+	// Like the real server, surface the metadata ConfigMap data directly.
+	result.Metadata = result.MetadataData()
+	return result, nil
+}
+
+func (s *Server) wfLock(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfLockParams](args)
+	if err != nil {
+		return nil, err
+	}
+	if p.LockID == "" {
+		return nil, fmt.Errorf("lock_id is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := workflowKey(p.Namespace, p.Name)
+	result := mcp.GrantLock(s.locks[key], p, time.Now())
+	if result.Acquired {
+		lock := result.Lock
+		s.locks[key] = &lock
+	}
+	return result, nil
+}
+
+func (s *Server) wfUnlock(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfUnlockParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := workflowKey(p.Namespace, p.Name)
+	if lock, ok := s.locks[key]; !ok || lock.LockID != p.LockID {
+		return mcp.WfUnlockResult{}, nil
+	}
+	delete(s.locks, key)
+	return mcp.WfUnlockResult{Released: true}, nil
+}
+
+// wfRevisions reads a workflow's revisions ConfigMap. Like the ConfigMap it
+// emulates, the history outlives wf_remove.
+func (s *Server) wfRevisions(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRevisionsParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return mcp.WfRevisionsResult{Revisions: mcp.RevisionsFromData(s.revisions[workflowKey(p.Namespace, p.Name)])}, nil
+}
+
+func (s *Server) wfRecordRevision(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfRecordRevisionParams](args)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(p.Data) {
+		return nil, fmt.Errorf("data must be a JSON revision record")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := workflowKey(p.Namespace, p.Name)
+	data, result, err := mcp.RecordRevision(s.revisions[key], p)
+	if err != nil {
+		return nil, err
+	}
+	s.revisions[key] = data
+	return result, nil
+}
+
+// wfSuspend scales the workflow to zero and records the annotations a real
+// server sets, moving the cron schedule aside.
+func (s *Server) wfSuspend(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfSuspendParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.WfSuspendResult{Name: wf.Name, Namespace: wf.Namespace, Suspended: true}
+	if wf.Annotations[mcp.AnnotationSuspended] == "true" {
+		return result, nil
+	}
+	if wf.Annotations == nil {
+		wf.Annotations = map[string]string{}
+	}
+	wf.Annotations[mcp.AnnotationSuspended] = "true"
+	wf.Annotations[mcp.AnnotationSuspendedAt] = time.Now().UTC().Format(time.RFC3339)
+	wf.Annotations[mcp.AnnotationSuspendedBy] = p.By
+	wf.Annotations[mcp.AnnotationSuspendedReplicas] = strconv.Itoa(int(wf.Replicas))
+	if schedule, ok := wf.Annotations[mcp.AnnotationCronSchedule]; ok {
+		wf.Annotations[mcp.AnnotationSuspendedCronSchedule] = schedule
+		delete(wf.Annotations, mcp.AnnotationCronSchedule)
+		result.CronSchedule = schedule
+	}
+	wf.Replicas, wf.Available, wf.Ready = 0, 0, false
+	result.Changed = true
+	return result, nil
+}
+
+func (s *Server) wfResume(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.WfSuspendParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wf, err := s.lookup(p.Namespace, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	result := &mcp.WfSuspendResult{Name: wf.Name, Namespace: wf.Namespace, Replicas: wf.Replicas}
+	if wf.Annotations[mcp.AnnotationSuspended] != "true" {
+		return result, nil
+	}
+	replicas := int32(1)
+	if n, convErr := strconv.ParseInt(wf.Annotations[mcp.AnnotationSuspendedReplicas], 10, 32); convErr == nil && n > 0 {
+		replicas = int32(n)
+	}
+	if schedule, ok := wf.Annotations[mcp.AnnotationSuspendedCronSchedule]; ok {
+		wf.Annotations[mcp.AnnotationCronSchedule] = schedule
+		result.CronSchedule = schedule
+	}
+	for _, key := range []string{mcp.AnnotationSuspended, mcp.AnnotationSuspendedAt, mcp.AnnotationSuspendedBy, mcp.AnnotationSuspendedReplicas, mcp.AnnotationSuspendedCronSchedule} {
+		delete(wf.Annotations, key)
+	}
+	wf.Replicas, wf.Available, wf.Ready = replicas, replicas, true
+	result.Replicas, result.Changed = replicas, true
+	return result, nil
+}
+
+func (s *Server) auditResources(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.AuditResourcesParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, overall := "match", "pass"
+	if _, ok := s.workflows[workflowKey(p.Namespace, p.WorkflowName)]; !ok {
+		status, overall = "missing", "fail"
+	}
+	return &mcp.AuditResourcesResult{
+		Overall:       overall,
+		NetworkPolicy: mcp.ResourceAudit{Status: status},
+		Secrets:       mcp.ResourceAudit{Status: status},
+		CronJobs:      mcp.ResourceAudit{Status: status},
+	}, nil
+}
+
+func (s *Server) clusterProfile(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.ClusterProfileParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile := s.profile
+	profile.GeneratedAt = time.Now().UTC()
+	profile.Namespace = p.Namespace
+	return profile, nil
+}
+
+// sortedWorkflows returns workflows in namespace ("" for all), sorted by key.
+// Callers must hold s.mu.
+func (s *Server) sortedWorkflows(namespace string) []*Workflow {
+	keys := make([]string, 0, len(s.workflows))
+	for k, wf := range s.workflows {
+		if namespace == "" || wf.Namespace == namespace {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	out := make([]*Workflow, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, s.workflows[k])
+	}
+	return out
+}
+
+// --- enclave tools ---
+
+func (s *Server) enclavePreflight(args json.RawMessage) (any, error) {
+	if _, err := decode[mcp.ClusterPreflightParams](args); err != nil {
+		return nil, err
+	}
+	checks := []mcp.CheckResult{
+		{Name: "namespace", Passed: true},
+		{Name: "runtime-class", Passed: true},
+		{Name: "network-policy", Passed: true},
+	}
+	return &mcp.ClusterPreflightResult{Results: checks, AllPass: true}, nil
+}
+
+func (s *Server) enclaveProvision(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.EnclaveProvisionParams](args)
+	if err != nil {
+		return nil, err
+	}
+	if p.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.enclaves[p.Name]; exists {
+		return nil, fmt.Errorf("enclave %q already exists", p.Name)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	s.enclaves[p.Name] = &mcp.EnclaveInfoResult{
+		Name:        p.Name,
+		Owner:       p.Owner,
+		OwnerSub:    p.OwnerSub,
+		Platform:    p.Platform,
+		ChannelID:   p.ChannelID,
+		ChannelName: p.ChannelName,
+		QuotaPreset: p.Quota,
+		Members:     p.Members,
+		Status:      "active",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	return &mcp.EnclaveProvisionResult{
+		Name:             p.Name,
+		Status:           "active",
+		QuotaPreset:      p.Quota,
+		Owner:            p.Owner,
+		Members:          p.Members,
+		ResourcesCreated: []string{"Namespace/" + p.Name, "NetworkPolicy/default-deny", "ResourceQuota/" + p.Name},
+	}, nil
+}
+
+func (s *Server) enclaveInfo(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.EnclaveInfoParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.enclaves[p.Name]
+	if !ok {
+		return nil, fmt.Errorf("enclave %q not found", p.Name)
+	}
+	out := *info
+	out.TentacleCount = len(s.sortedWorkflows(p.Name))
+	return out, nil
+}
+
+func (s *Server) enclaveList(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.EnclaveListParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.enclaves))
+	for n := range s.enclaves {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	items := []mcp.EnclaveListItem{}
+	for _, n := range names {
+		e := s.enclaves[n]
+		if p.CallerEmail != "" && e.Owner != p.CallerEmail && !contains(e.Members, p.CallerEmail) {
+			continue
+		}
+		items = append(items, mcp.EnclaveListItem{
+			Name:        e.Name,
+			Owner:       e.Owner,
+			Status:      e.Status,
+			Platform:    e.Platform,
+			ChannelName: e.ChannelName,
+			CreatedAt:   e.CreatedAt,
+			Members:     e.Members,
+		})
+	}
+	return map[string]any{"enclaves": items}, nil
+}
+
+func (s *Server) enclaveSync(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.EnclaveSyncParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.enclaves[p.Name]
+	if !ok {
+		return nil, fmt.Errorf("enclave %q not found", p.Name)
+	}
+	var updated []string
+	if p.NewOwner != "" {
+		e.Owner = p.NewOwner
+		updated = append(updated, "owner")
+	}
+	if p.ChannelName != "" {
+		e.ChannelName = p.ChannelName
+		updated = append(updated, "channel_name")
+	}
+	if p.Status != "" {
+		e.Status = p.Status
+		updated = append(updated, "status")
+	}
+	if p.NewQuotaPreset != "" {
+		e.QuotaPreset = p.NewQuotaPreset
+		updated = append(updated, "quota_preset")
+	}
+	for _, m := range p.AddMembers {
+		if !contains(e.Members, m) {
+			e.Members = append(e.Members, m)
+		}
+	}
+	if len(p.RemoveMembers) > 0 {
+		kept := e.Members[:0]
+		for _, m := range e.Members {
+			if !contains(p.RemoveMembers, m) {
+				kept = append(kept, m)
+			}
+		}
+		e.Members = kept
+	}
+	if len(p.AddMembers) > 0 || len(p.RemoveMembers) > 0 {
+		updated = append(updated, "members")
+	}
+	e.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return &mcp.EnclaveSyncResult{Name: e.Name, Updated: updated, Enclave: *e}, nil
+}
+
+func (s *Server) enclaveDeprovision(args json.RawMessage) (any, error) {
+	p, err := decode[mcp.EnclaveDeprovisionParams](args)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.enclaves[p.Name]; !ok {
+		return nil, fmt.Errorf("enclave %q not found", p.Name)
+	}
+	removed := 0
+	for k, wf := range s.workflows {
+		if wf.Namespace == p.Name {
+			delete(s.workflows, k)
+			removed++
+		}
+	}
+	delete(s.enclaves, p.Name)
+	return &mcp.EnclaveDeprovisionResult{Name: p.Name, Deleted: true, TentaclesRemoved: removed}, nil
+}
+
+// --- helpers ---
+
+// manifestID renders a manifest as Kind/name, the form wf_apply reports.
+func manifestID(m map[string]any) string {
+	kind, _ := m["kind"].(string)
+	meta, _ := m["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	return kind + "/" + name
+}
+
+// podName derives a stable, pod-like name for a workflow deployment.
+func podName(name string, at time.Time) string {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%s%d", name, at.UnixNano())
+	return fmt.Sprintf("%s-%08x", name, h.Sum32())
+}
+
+func podPhase(wf *Workflow) string {
+	if wf.Ready {
+		return "Running"
+	}
+	return "Pending"
+}
+
+func stringAt(m map[string]any, keys ...string) string {
+	var cur any = m
+	for _, k := range keys {
+		next, ok := cur.(map[string]any)
+		if !ok {
+			return ""
+		}
+		cur = next[k]
+	}
+	s, _ := cur.(string)
+	return s
+}
+
+func stringMap(v any) map[string]string {
+	in, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, val := range in {
+		out[k] = fmt.Sprint(val)
+	}
+	return out
+}
+
+func deploymentImage(m map[string]any) string {
+	spec, _ := m["spec"].(map[string]any)
+	tmpl, _ := spec["template"].(map[string]any)
+	podSpec, _ := tmpl["spec"].(map[string]any)
+	containers, _ := podSpec["containers"].([]any)
+	for _, c := range containers {
+		ctr, _ := c.(map[string]any)
+		if name, _ := ctr["name"].(string); name == "engine" || len(containers) == 1 {
+			img, _ := ctr["image"].(string)
+			return img
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
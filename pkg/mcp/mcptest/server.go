@@ -0,0 +1,61 @@
+// Package mcptest starts the in-memory fake tentacular-mcp server from
+// pkg/mcp/mcpfake on a loopback port for tests.
+//
+// Typical test usage:
+//
+//	srv := mcptest.NewServer(t)
+//	client := srv.Client()
+//	_, err := client.WfApply(ctx, "dev", "hello", manifests)
+package mcptest
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+	"github.com/randybias/tentacular/pkg/mcp/mcpfake"
+)
+
+// AnyTool matches every tool when passed to Server.Inject.
+const AnyTool = mcpfake.AnyTool
+
+// Fault describes an injected failure or delay for a tool.
+type Fault = mcpfake.Fault
+
+// Workflow is the fake's record of a deployed workflow.
+type Workflow = mcpfake.Workflow
+
+// Server is a fake tentacular-mcp server listening on a loopback port.
+type Server struct {
+	*mcpfake.Server
+	httpServer *httptest.Server
+}
+
+// NewServer starts a fake server on a loopback port and closes it when the
+// test finishes.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+	s := &Server{Server: mcpfake.New()}
+	hs := httptest.NewUnstartedServer(s.Handler())
+	hs.EnableHTTP2 = false
+	hs.Start()
+	s.httpServer = hs
+	tb.Cleanup(func() {
+		// Streaming MCP sessions keep connections open; drop them so Close
+		// does not wait for a graceful shutdown.
+		hs.CloseClientConnections()
+		hs.Close()
+	})
+	return s
+}
+
+// URL returns the MCP endpoint of the server.
+func (s *Server) URL() string {
+	return s.httpServer.URL + "/mcp"
+}
+
+// Client returns an mcp.Client connected to the server.
+func (s *Server) Client() *mcp.Client {
+	return mcp.NewClient(mcp.Config{Endpoint: s.URL(), Token: "mcptest", Timeout: 5 * time.Second})
+}
@@ -0,0 +1,245 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/randybias/tentacular/pkg/mcp"
+)
+
+func testManifests(name string) []map[string]any {
+	return []map[string]any{
+		{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]any{"name": name + "-code"}},
+		{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":        name,
+				"labels":      map[string]any{"app.kubernetes.io/version": "1.2"},
+				"annotations": map[string]any{"tentacular.io/description": "says hello"},
+			},
+			"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "engine", "image": "engine:1.2"}},
+			}}},
+		},
+	}
+}
+
+func TestServer_WorkflowLifecycle(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	applied, err := client.WfApply(ctx, "dev", "hello", testManifests("hello"))
+	if err != nil {
+		t.Fatalf("WfApply: %v", err)
+	}
+	if applied.Status != "created" || len(applied.Applied) != 2 {
+		t.Errorf("unexpected apply result: %+v", applied)
+	}
+	applied, err = client.WfApply(ctx, "dev", "hello", testManifests("hello"))
+	if err != nil {
+		t.Fatalf("second WfApply: %v", err)
+	}
+	if applied.Status != "updated" || applied.Updated != 2 {
+		t.Errorf("expected update on re-apply, got %+v", applied)
+	}
+
+	status, err := client.WfStatus(ctx, "dev", "hello", true)
+	if err != nil {
+		t.Fatalf("WfStatus: %v", err)
+	}
+	if !status.Ready || status.Version != "1.2" || len(status.Pods) != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	list, err := client.WfList(ctx, "dev")
+	if err != nil {
+		t.Fatalf("WfList: %v", err)
+	}
+	if len(list) != 1 || list[0].Description != "says hello" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	pods, err := client.WfPods(ctx, "dev")
+	if err != nil {
+		t.Fatalf("WfPods: %v", err)
+	}
+	if len(pods.Pods) != 1 || pods.Pods[0].Images[0] != "engine:1.2" {
+		t.Fatalf("unexpected pods: %+v", pods.Pods)
+	}
+	if err := srv.SetLogs("dev", "hello", []string{"one", "two", "three"}); err != nil {
+		t.Fatal(err)
+	}
+	logs, err := client.WfLogs(ctx, "dev", pods.Pods[0].Name, 2)
+	if err != nil {
+		t.Fatalf("WfLogs: %v", err)
+	}
+	if logs.LogText() != "two\nthree" {
+		t.Errorf("expected tailed logs, got %q", logs.LogText())
+	}
+
+	if err := srv.SetRunOutput("dev", "hello", json.RawMessage(`{"success":true,"n":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	run, err := client.WfRun(ctx, "dev", "hello", json.RawMessage(`{"x":1}`), 30)
+	if err != nil {
+		t.Fatalf("WfRun: %v", err)
+	}
+	if string(run.Output) != `{"success":true,"n":1}` {
+		t.Errorf("unexpected run output: %s", run.Output)
+	}
+	if wf, _ := srv.Workflow("dev", "hello"); wf.Runs != 1 || string(wf.LastInput) != `{"x":1}` {
+		t.Errorf("expected run recorded, got runs=%d input=%s", wf.Runs, wf.LastInput)
+	}
+
+	started, err := client.WfRunAsync(ctx, "dev", "hello", nil)
+	if err != nil {
+		t.Fatalf("WfRunAsync: %v", err)
+	}
+	if !started.Running() || started.ExecutionID == "" {
+		t.Fatalf("expected running execution, got %+v", started)
+	}
+	finished, err := client.WfRunStatus(ctx, "dev", started.ExecutionID, 1)
+	if err != nil {
+		t.Fatalf("WfRunStatus: %v", err)
+	}
+	if finished.Status != mcp.RunStatusSucceeded || string(finished.Output) != `{"success":true,"n":1}` {
+		t.Errorf("unexpected finished execution: %+v", finished)
+	}
+
+	if err := srv.SetTelemetry("dev", "hello", mcp.TelemetrySnapshot{Status: "ok", InFlight: 1}); err != nil {
+		t.Fatal(err)
+	}
+	health, err := client.WfHealth(ctx, "dev", "hello")
+	if err != nil {
+		t.Fatalf("WfHealth: %v", err)
+	}
+	if health.Status != "green" || health.Detail == nil || health.Detail.InFlight != 1 {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
+	removed, err := client.WfRemove(ctx, "dev", "hello")
+	if err != nil {
+		t.Fatalf("WfRemove: %v", err)
+	}
+	if len(removed.Deleted) != 2 {
+		t.Errorf("expected 2 deleted resources, got %+v", removed)
+	}
+	if _, err := client.WfStatus(ctx, "dev", "hello", false); !mcp.IsToolError(err) {
+		t.Errorf("expected tool error for removed workflow, got %v", err)
+	}
+}
+
+func TestServer_EnclaveLifecycle(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	if _, err := client.EnclaveProvision(ctx, mcp.EnclaveProvisionParams{Name: "team-a", Owner: "a@example.com"}); err != nil {
+		t.Fatalf("EnclaveProvision: %v", err)
+	}
+	if _, err := client.EnclaveSync(ctx, mcp.EnclaveSyncParams{Name: "team-a", AddMembers: []string{"b@example.com"}}); err != nil {
+		t.Fatalf("EnclaveSync: %v", err)
+	}
+	mine, err := client.EnclaveList(ctx, "b@example.com")
+	if err != nil {
+		t.Fatalf("EnclaveList: %v", err)
+	}
+	if len(mine) != 1 || mine[0].Name != "team-a" {
+		t.Errorf("expected member to see team-a, got %+v", mine)
+	}
+
+	if _, err := client.WfApply(ctx, "team-a", "hello", testManifests("hello")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := client.EnclaveInfo(ctx, "team-a")
+	if err != nil {
+		t.Fatalf("EnclaveInfo: %v", err)
+	}
+	if info.TentacleCount != 1 {
+		t.Errorf("expected 1 tentacle, got %d", info.TentacleCount)
+	}
+
+	gone, err := client.EnclaveDeprovision(ctx, "team-a")
+	if err != nil {
+		t.Fatalf("EnclaveDeprovision: %v", err)
+	}
+	if !gone.Deleted || gone.TentaclesRemoved != 1 {
+		t.Errorf("unexpected deprovision result: %+v", gone)
+	}
+	if _, err := client.EnclaveInfo(ctx, "team-a"); err == nil {
+		t.Error("expected enclave_info to fail after deprovision")
+	}
+}
+
+func TestServer_ClusterProfileAndPreflight(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	res, err := client.ClusterProfile(ctx, "dev")
+	if err != nil {
+		t.Fatalf("ClusterProfile: %v", err)
+	}
+	if !strings.Contains(string(res.Raw), `"gvisor":true`) || !strings.Contains(string(res.Raw), `"namespace":"dev"`) {
+		t.Errorf("unexpected profile: %s", res.Raw)
+	}
+
+	pre, err := client.ClusterPreflight(ctx, "dev")
+	if err != nil {
+		t.Fatalf("ClusterPreflight: %v", err)
+	}
+	if !pre.AllPass || len(pre.Results) == 0 {
+		t.Errorf("expected passing preflight, got %+v", pre)
+	}
+}
+
+func TestServer_InjectToolError(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+
+	srv.Inject("wf_list", Fault{Message: "etcd is on fire", Times: 1})
+	_, err := client.WfList(ctx, "dev")
+	if !mcp.IsToolError(err) || !strings.Contains(err.Error(), "etcd is on fire") {
+		t.Fatalf("expected injected tool error, got %v", err)
+	}
+	if _, err := client.WfList(ctx, "dev"); err != nil {
+		t.Fatalf("fault should apply once, got %v", err)
+	}
+	if srv.Calls("wf_list") != 2 {
+		t.Errorf("expected 2 recorded calls, got %d", srv.Calls("wf_list"))
+	}
+}
+
+func TestServer_InjectLatency(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	srv.Inject(AnyTool, Fault{Latency: 2 * time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.WfList(ctx, "dev"); err == nil {
+		t.Fatal("expected deadline error under injected latency")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("call should fail at the caller's deadline, took %s", time.Since(start))
+	}
+}
+
+func TestServer_FailRequests(t *testing.T) {
+	srv := NewServer(t)
+	client := srv.Client()
+
+	srv.FailRequests(http.StatusUnauthorized, 0)
+	_, err := client.WfList(context.Background(), "dev")
+	if !mcp.IsUnauthorized(err) {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+}
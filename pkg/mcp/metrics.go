@@ -0,0 +1,54 @@
+package mcp
+
+// CallMetrics summarizes the tool calls made by a Client.
+type CallMetrics struct {
+	Tools      map[string]ToolMetrics `json:"tools,omitempty"`
+	Calls      int                    `json:"calls"`
+	Attempts   int                    `json:"attempts"`
+	Retries    int                    `json:"retries"`
+	Reconnects int                    `json:"reconnects"`
+	Failures   int                    `json:"failures"`
+}
+
+// ToolMetrics counts calls and attempts for a single tool.
+type ToolMetrics struct {
+	Calls    int `json:"calls"`
+	Attempts int `json:"attempts"`
+	Failures int `json:"failures"`
+}
+
+// Add merges other into m, so metrics from several clients can be reported
+// together.
+func (m *CallMetrics) Add(other CallMetrics) {
+	m.Calls += other.Calls
+	m.Attempts += other.Attempts
+	m.Retries += other.Retries
+	m.Reconnects += other.Reconnects
+	m.Failures += other.Failures
+	for name, tm := range other.Tools {
+		if m.Tools == nil {
+			m.Tools = map[string]ToolMetrics{}
+		}
+		cur := m.Tools[name]
+		cur.Calls += tm.Calls
+		cur.Attempts += tm.Attempts
+		cur.Failures += tm.Failures
+		m.Tools[name] = cur
+	}
+}
+
+// record adds the outcome of one CallTool invocation.
+func (m *CallMetrics) record(tool string, attempts, reconnects int, failed bool) {
+	tm := ToolMetrics{Calls: 1, Attempts: attempts}
+	if failed {
+		tm.Failures = 1
+	}
+	m.Add(CallMetrics{
+		Calls:      1,
+		Attempts:   attempts,
+		Retries:    attempts - 1,
+		Reconnects: reconnects,
+		Failures:   tm.Failures,
+		Tools:      map[string]ToolMetrics{tool: tm},
+	})
+}
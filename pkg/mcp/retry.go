@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 200 * time.Millisecond
+	defaultMaxDelay    = 2 * time.Second
+)
+
+// RetryPolicy controls how CallTool retries failed tool calls.
+// Zero values select the defaults.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts per call including the first (default: 3; 1 disables retries)
+	BaseDelay   time.Duration // Backoff before the second attempt, doubled per retry (default: 200ms)
+	MaxDelay    time.Duration // Upper bound on a single backoff (default: 2s)
+	Budget      time.Duration // Deadline for the whole call across attempts (default: 2x Timeout)
+}
+
+// withDefaults fills unset fields of p. timeout is the per-request timeout
+// used to derive the default budget.
+func (p RetryPolicy) withDefaults(timeout time.Duration) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultMaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	if p.Budget <= 0 {
+		p.Budget = 2 * timeout
+	}
+	return p
+}
+
+// backoff returns the delay before the given retry (1 for the first retry):
+// exponential growth capped at MaxDelay, with jitter in [50%, 100%].
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	half := d / 2
+	return half + rand.N(half+1) //nolint:gosec // jitter does not need a secure source
+}
+
+// readOnlyTools are safe to retry after the request reached the server,
+// because repeating them has no side effects.
+var readOnlyTools = map[string]bool{
+	"wf_status":     true,
+	"wf_list":       true,
+	"wf_pods":       true,
+	"wf_logs":       true,
+	"wf_describe":   true,
+	"wf_run_status": true,
+	"wf_runs":       true,
+	"wf_health":     true,
+	"wf_revisions":  true,
+}
+
+// IsReadOnlyTool reports whether tool is retried on any transient failure.
+// Other tools are retried only when the request never reached the server.
+func IsReadOnlyTool(tool string) bool {
+	return readOnlyTools[tool]
+}
+
+// shouldRetry decides whether a failed attempt may be repeated. sent reports
+// whether the request was written to the server.
+func shouldRetry(tool string, err error, sent bool) bool {
+	if IsUnauthorized(err) || IsForbidden(err) || IsToolError(err) || IsServerTooOld(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if !sent && IsServerUnavailable(err) {
+		return true
+	}
+	return IsReadOnlyTool(tool)
+}
+
+// isSessionMissing reports whether the server rejected the cached MCP session
+// as expired (HTTP 404). It does so before handling the call.
+func isSessionMissing(err error) bool {
+	return errors.Is(err, mcpsdk.ErrSessionMissing) ||
+		strings.Contains(err.Error(), mcpsdk.ErrSessionMissing.Error())
+}
+
+// sendTrace records whether an HTTP request carrying a tool call was written
+// to the wire, which distinguishes "never sent" from "sent, outcome unknown".
+type sendTrace struct {
+	wrote atomic.Bool
+}
+
+func (t *sendTrace) context(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				t.wrote.Store(true)
+			}
+		},
+	})
+}
+
+// sleepCtx waits for d or until ctx is done, returning false in the latter case.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,313 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// flakyServer serves an MCP server whose tools/call requests can be made to
+// fail with an HTTP status before they reach the handler, or to drop the
+// connection after the handler ran, and whose sessions can be expired.
+type flakyServer struct {
+	handler  http.Handler
+	newMCP   func() http.Handler
+	calls    map[string]int
+	failures int
+	status   int
+	drops    int
+	mu       sync.Mutex
+}
+
+func newFlakyServer(t *testing.T) (*flakyServer, *httptest.Server) {
+	t.Helper()
+	fs := &flakyServer{calls: map[string]int{}}
+	fs.newMCP = func() http.Handler {
+		server := mcpsdk.NewServer(&mcpsdk.Implementation{Name: "flaky", Version: "test"}, nil)
+		for _, name := range []string{"wf_status", "wf_apply"} {
+			tool := name
+			server.AddTool(&mcpsdk.Tool{Name: tool, InputSchema: json.RawMessage(`{"type":"object"}`)},
+				func(context.Context, *mcpsdk.CallToolRequest) (*mcpsdk.CallToolResult, error) {
+					fs.mu.Lock()
+					fs.calls[tool]++
+					fs.mu.Unlock()
+					return &mcpsdk.CallToolResult{Content: []mcpsdk.Content{&mcpsdk.TextContent{Text: `{"ok":true}`}}}, nil
+				})
+		}
+		return mcpsdk.NewStreamableHTTPHandler(func(*http.Request) *mcpsdk.Server { return server }, nil)
+	}
+	fs.handler = fs.newMCP()
+
+	srv := httptest.NewServer(fs)
+	t.Cleanup(func() {
+		srv.CloseClientConnections()
+		srv.Close()
+	})
+	return fs, srv
+}
+
+func (fs *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fs.mu.Lock()
+		call := bytes.Contains(body, []byte(`"tools/call"`))
+		fail := fs.failures > 0 && call
+		if fail {
+			fs.failures--
+		}
+		drop := fs.drops > 0 && call && !fail
+		if drop {
+			fs.drops--
+		}
+		status, h := fs.status, fs.handler
+		fs.mu.Unlock()
+		if fail {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		if drop {
+			h.ServeHTTP(discardResponse{header: http.Header{}}, r)
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				_ = conn.Close()
+			}
+			return
+		}
+	}
+	fs.mu.Lock()
+	h := fs.handler
+	fs.mu.Unlock()
+	h.ServeHTTP(w, r)
+}
+
+// failNext fails the next n tools/call requests with status.
+func (fs *flakyServer) failNext(status, n int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.status, fs.failures = status, n
+}
+
+// dropNext runs the next n tools/call requests, then closes the connection
+// without replying.
+func (fs *flakyServer) dropNext(n int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.drops = n
+}
+
+// discardResponse swallows a handler's response.
+type discardResponse struct{ header http.Header }
+
+func (d discardResponse) Header() http.Header         { return d.header }
+func (d discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (d discardResponse) WriteHeader(int)             {}
+func (d discardResponse) Flush()                      {}
+
+// expireSessions replaces the MCP handler so existing session IDs get 404.
+func (fs *flakyServer) expireSessions() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.handler = fs.newMCP()
+}
+
+func (fs *flakyServer) handled(tool string) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.calls[tool]
+}
+
+func fastRetryClient(endpoint string) *Client {
+	return NewClient(Config{
+		Endpoint: endpoint,
+		Timeout:  5 * time.Second,
+		Retry:    RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+	})
+}
+
+func TestCallTool_RetriesReadOnlyToolOnTransientStatus(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	fs.failNext(http.StatusBadGateway, 2)
+	if _, err := client.CallTool(context.Background(), "wf_status", nil); err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+
+	m := client.Metrics()
+	if m.Calls != 1 || m.Attempts != 3 || m.Retries != 2 || m.Failures != 0 {
+		t.Errorf("unexpected metrics: %+v", m)
+	}
+	if m.Tools["wf_status"].Attempts != 3 {
+		t.Errorf("expected per-tool attempts, got %+v", m.Tools)
+	}
+}
+
+func TestCallTool_DoesNotRetryMutatingToolAfterSend(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	fs.failNext(http.StatusBadGateway, 1)
+	if _, err := client.CallTool(context.Background(), "wf_apply", nil); err == nil {
+		t.Fatal("expected wf_apply to fail without retry")
+	}
+	if m := client.Metrics(); m.Attempts != 1 || m.Failures != 1 {
+		t.Errorf("expected a single failed attempt, got %+v", m)
+	}
+	if fs.handled("wf_apply") != 0 {
+		t.Errorf("wf_apply should not have reached the handler")
+	}
+}
+
+func TestCallTool_DoesNotRepeatMutatingToolWhenConnectionClosesAfterSend(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	fs.dropNext(1)
+	if _, err := client.CallTool(context.Background(), "wf_apply", nil); err == nil {
+		t.Fatal("expected wf_apply to fail when the connection closed")
+	}
+	if fs.handled("wf_apply") != 1 {
+		t.Errorf("wf_apply must not be sent twice, handled %d times", fs.handled("wf_apply"))
+	}
+	if m := client.Metrics(); m.Attempts != 1 || m.Failures != 1 {
+		t.Errorf("expected a single failed attempt, got %+v", m)
+	}
+}
+
+func TestCallTool_RetriesReadOnlyToolWhenConnectionClosesAfterSend(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	fs.dropNext(1)
+	if _, err := client.CallTool(context.Background(), "wf_status", nil); err != nil {
+		t.Fatalf("expected wf_status to be retried, got %v", err)
+	}
+	if fs.handled("wf_status") != 2 {
+		t.Errorf("expected wf_status to run twice, got %d", fs.handled("wf_status"))
+	}
+}
+
+func TestCallTool_GivesUpAfterMaxAttempts(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	fs.failNext(http.StatusServiceUnavailable, 10)
+	if _, err := client.CallTool(context.Background(), "wf_status", nil); err == nil {
+		t.Fatal("expected failure after exhausting attempts")
+	}
+	if m := client.Metrics(); m.Attempts != defaultMaxAttempts {
+		t.Errorf("expected %d attempts, got %+v", defaultMaxAttempts, m)
+	}
+}
+
+func TestCallTool_ReconnectsExpiredSession(t *testing.T) {
+	fs, srv := newFlakyServer(t)
+	client := fastRetryClient(srv.URL)
+	defer func() { _ = client.Close() }()
+
+	ctx := context.Background()
+	if _, err := client.CallTool(ctx, "wf_apply", nil); err != nil {
+		t.Fatal(err)
+	}
+	fs.expireSessions()
+	if _, err := client.CallTool(ctx, "wf_apply", nil); err != nil {
+		t.Fatalf("expected transparent reconnect, got %v", err)
+	}
+	if fs.handled("wf_apply") != 2 {
+		t.Errorf("expected each call to be handled once, got %d", fs.handled("wf_apply"))
+	}
+	if m := client.Metrics(); m.Reconnects != 1 || m.Attempts != 3 {
+		t.Errorf("unexpected metrics after reconnect: %+v", m)
+	}
+}
+
+func TestCallTool_RetriesConnectFailureForMutatingTool(t *testing.T) {
+	var up atomic.Bool
+	fs, _ := newFlakyServer(t)
+	gate := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Swap(true) {
+			// The first request (initialize) fails before any tool is sent.
+			http.Error(w, "starting", http.StatusServiceUnavailable)
+			return
+		}
+		fs.ServeHTTP(w, r)
+	}))
+	defer gate.Close()
+
+	client := fastRetryClient(gate.URL)
+	defer func() { _ = client.Close() }()
+	if _, err := client.CallTool(context.Background(), "wf_apply", nil); err != nil {
+		t.Fatalf("expected connect failure to be retried, got %v", err)
+	}
+	if fs.handled("wf_apply") != 1 {
+		t.Errorf("expected exactly one apply, got %d", fs.handled("wf_apply"))
+	}
+}
+
+func TestCallTool_BudgetBoundsRetries(t *testing.T) {
+	client := NewClient(Config{
+		Endpoint: "http://127.0.0.1:1",
+		Timeout:  time.Second,
+		Retry:    RetryPolicy{MaxAttempts: 100, BaseDelay: 50 * time.Millisecond, Budget: 300 * time.Millisecond},
+	})
+	start := time.Now()
+	if _, err := client.CallTool(context.Background(), "wf_status", nil); !IsServerUnavailable(err) {
+		t.Fatalf("expected server unavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("budget should bound the call, took %s", elapsed)
+	}
+	if m := client.Metrics(); m.Attempts >= 100 || m.Attempts < 2 {
+		t.Errorf("expected a few attempts within the budget, got %d", m.Attempts)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{}.withDefaults(30 * time.Second)
+	if p.MaxAttempts != 3 || p.Budget != time.Minute {
+		t.Errorf("unexpected defaults: %+v", p)
+	}
+	for retry, upper := range map[int]time.Duration{1: 200 * time.Millisecond, 2: 400 * time.Millisecond, 10: 2 * time.Second} {
+		d := p.backoff(retry)
+		if d < upper/2 || d > upper {
+			t.Errorf("backoff(%d) = %s, want within [%s, %s]", retry, d, upper/2, upper)
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	unavailable := &ServerUnavailableError{Endpoint: "x", Cause: io.EOF}
+	cases := []struct {
+		err  error
+		name string
+		tool string
+		sent bool
+		want bool
+	}{
+		{name: "read-only transient", tool: "wf_status", err: &Error{Code: -1}, sent: true, want: true},
+		{name: "mutating after send", tool: "wf_apply", err: &Error{Code: -1}, sent: true, want: false},
+		{name: "mutating before send", tool: "wf_apply", err: unavailable, want: true},
+		{name: "tool error", tool: "wf_status", err: &ToolError{Tool: "wf_status"}, sent: true, want: false},
+		{name: "unauthorized", tool: "wf_status", err: &Error{Code: 401}, want: false},
+		{name: "deadline", tool: "wf_status", err: context.DeadlineExceeded, sent: true, want: false},
+	}
+	for _, tc := range cases {
+		if got := shouldRetry(tc.tool, tc.err, tc.sent); got != tc.want {
+			t.Errorf("%s: shouldRetry = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
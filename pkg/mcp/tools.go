@@ -3,8 +3,12 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // --- wf_apply ---
@@ -114,6 +118,25 @@ type WfStatusResult struct {
 	Replicas  int32       `json:"replicas"`
 	Available int32       `json:"available"`
 	Ready     bool        `json:"ready"`
+
+	// Rollout progress (omitted by older servers): replicas running the
+	// current pod template and the Deployment generation the controller has
+	// acted on.
+	Updated            int32 `json:"updated,omitempty"`
+	Generation         int64 `json:"generation,omitempty"`
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// RolloutComplete reports whether the Deployment is ready and, when the
+// server reports rollout progress, every replica runs the latest template.
+func (r *WfStatusResult) RolloutComplete() bool {
+	if !r.Ready {
+		return false
+	}
+	if r.Generation == 0 {
+		return true
+	}
+	return r.ObservedGeneration >= r.Generation && r.Updated >= r.Replicas
 }
 
 // PodInfo represents a pod in the workflow deployment.
@@ -121,6 +144,8 @@ type PodInfo struct {
 	Name     string `json:"name"`
 	Phase    string `json:"phase"`
 	NodeName string `json:"nodeName,omitempty"`
+	Reason   string `json:"reason,omitempty"` // container waiting/terminated reason, e.g. CrashLoopBackOff
+	Restarts int32  `json:"restarts,omitempty"`
 	Ready    bool   `json:"ready"`
 }
 
@@ -167,11 +192,15 @@ type WfListItem struct {
 	Environment string `json:"environment,omitempty"`
 	DeployedBy  string `json:"deployed_by,omitempty"`
 	DeployedVia string `json:"deployed_via,omitempty"`
+	DeployedAt  string `json:"deployed_at,omitempty"`
+	SuspendedBy string `json:"suspended_by,omitempty"`
+	SuspendedAt string `json:"suspended_at,omitempty"`
 	Age         string `json:"age,omitempty"`
 	CreatedAt   string `json:"createdAt,omitempty"`
 	Replicas    int32  `json:"replicas,omitempty"`
 	Available   int32  `json:"available,omitempty"`
 	Ready       bool   `json:"ready"`
+	Suspended   bool   `json:"suspended,omitempty"`
 }
 
 // wfListResult is the envelope returned by the MCP server for wf_list.
@@ -239,10 +268,12 @@ func (c *Client) WfPods(ctx context.Context, namespace string) (*WfPodsResult, e
 
 // WfLogsParams are the arguments for the wf_logs MCP tool.
 type WfLogsParams struct {
-	Namespace string `json:"enclave"`
-	Pod       string `json:"pod"`
-	Container string `json:"container,omitempty"`
-	TailLines int64  `json:"tail_lines,omitempty"`
+	Namespace    string `json:"enclave"`
+	Pod          string `json:"pod"`
+	Container    string `json:"container,omitempty"`     // default: the engine container
+	TailLines    int64  `json:"tail_lines,omitempty"`    // 0 returns the server's default tail
+	SinceSeconds int64  `json:"since_seconds,omitempty"` // only lines newer than this
+	Timestamps   bool   `json:"timestamps,omitempty"`    // prefix each line with its RFC3339Nano timestamp
 }
 
 // WfLogsResult is the response from wf_logs.
@@ -261,13 +292,19 @@ func (r *WfLogsResult) LogText() string {
 	return r.Logs
 }
 
-// WfLogs calls the wf_logs MCP tool to retrieve pod logs.
+// WfLogs calls the wf_logs MCP tool to retrieve the engine container's logs.
 func (c *Client) WfLogs(ctx context.Context, namespace, pod string, tailLines int64) (*WfLogsResult, error) {
-	raw, err := c.CallTool(ctx, "wf_logs", WfLogsParams{
+	return c.WfLogsQuery(ctx, WfLogsParams{
 		Namespace: namespace,
 		Pod:       pod,
 		TailLines: tailLines,
 	})
+}
+
+// WfLogsQuery calls the wf_logs MCP tool with a choice of container, time
+// window and timestamps.
+func (c *Client) WfLogsQuery(ctx context.Context, params WfLogsParams) (*WfLogsResult, error) {
+	raw, err := c.CallTool(ctx, "wf_logs", params)
 	if err != nil {
 		return nil, err
 	}
@@ -280,31 +317,88 @@ func (c *Client) WfLogs(ctx context.Context, namespace, pod string, tailLines in
 
 // --- wf_run ---
 
+// Execution states reported in WfRunResult.Status.
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
 // WfRunParams are the arguments for the wf_run MCP tool.
 type WfRunParams struct {
 	Namespace      string          `json:"enclave"`
 	Name           string          `json:"name"`
 	Input          json.RawMessage `json:"input,omitempty"`
 	TimeoutSeconds int             `json:"timeout_seconds,omitempty"`
+	Async          bool            `json:"async,omitempty"`
+}
+
+// NodeTiming is the per-node execution record returned by the engine.
+type NodeTiming struct {
+	Name       string `json:"name"`
+	Status     string `json:"status,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
 }
 
-// WfRunResult is the response from wf_run.
+// WfRunResult is the response from wf_run and wf_run_status.
 type WfRunResult struct {
-	Name       string          `json:"name"`
-	Namespace  string          `json:"enclave"`
-	PodName    string          `json:"pod_name,omitempty"`
-	Output     json.RawMessage `json:"output"`
-	DurationMs int64           `json:"duration_ms"`
+	Name        string          `json:"name"`
+	Namespace   string          `json:"enclave"`
+	PodName     string          `json:"pod_name,omitempty"`
+	ExecutionID string          `json:"execution_id,omitempty"`
+	Status      string          `json:"status,omitempty"` // running, succeeded, failed (empty from older servers)
+	Error       string          `json:"error,omitempty"`
+	Output      json.RawMessage `json:"output"`
+	Nodes       []NodeTiming    `json:"nodes,omitempty"`
+	StartedAt   string          `json:"started_at,omitempty"` // RFC3339, when the server records it
+	DurationMs  int64           `json:"duration_ms"`
+}
+
+// Running reports whether the execution has not finished yet.
+func (r *WfRunResult) Running() bool {
+	return r.Status == RunStatusRunning
 }
 
 // WfRun calls the wf_run MCP tool to trigger a workflow execution.
 func (c *Client) WfRun(ctx context.Context, namespace, name string, input json.RawMessage, timeoutSeconds int) (*WfRunResult, error) {
-	raw, err := c.CallTool(ctx, "wf_run", WfRunParams{
+	return c.wfRun(ctx, WfRunParams{
 		Namespace:      namespace,
 		Name:           name,
 		Input:          input,
 		TimeoutSeconds: timeoutSeconds,
 	})
+}
+
+// WfRunAsync starts a workflow execution without waiting for it to finish.
+// The result carries the ExecutionID to pass to WfRunStatus.
+// It fails before starting anything when the server lacks wf_run_status, since
+// such a server would ignore async and run the workflow synchronously.
+func (c *Client) WfRunAsync(ctx context.Context, namespace, name string, input json.RawMessage) (*WfRunResult, error) {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !caps.HasTool("wf_run_status") {
+		return nil, &ServerTooOldError{Tool: "wf_run_status", Feature: ToolFeature("wf_run_status"), ServerVersion: caps.ServerVersion}
+	}
+	result, err := c.wfRun(ctx, WfRunParams{
+		Namespace: namespace,
+		Name:      name,
+		Input:     input,
+		Async:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.ExecutionID == "" {
+		return nil, errors.New("wf_run returned no execution_id: the MCP server ignored async and ran the workflow synchronously")
+	}
+	return result, nil
+}
+
+func (c *Client) wfRun(ctx context.Context, params WfRunParams) (*WfRunResult, error) {
+	raw, err := c.CallTool(ctx, "wf_run", params)
 	if err != nil {
 		return nil, err
 	}
@@ -315,6 +409,124 @@ func (c *Client) WfRun(ctx context.Context, namespace, name string, input json.R
 	return &result, nil
 }
 
+// --- wf_run_status ---
+
+// WfRunStatusParams are the arguments for the wf_run_status MCP tool.
+type WfRunStatusParams struct {
+	Namespace   string `json:"enclave"`
+	ExecutionID string `json:"execution_id"`
+	WaitSeconds int    `json:"wait_seconds,omitempty"`
+}
+
+// runStatusWaitMargin is how far below the per-request timeout a
+// wf_run_status long poll is held, leaving room for the response to arrive.
+const runStatusWaitMargin = 5 * time.Second
+
+// WfRunStatus calls the wf_run_status MCP tool. When waitSeconds > 0 the
+// server holds the request until the execution finishes or the wait elapses;
+// the wait is capped below the client's request timeout.
+func (c *Client) WfRunStatus(ctx context.Context, namespace, executionID string, waitSeconds int) (*WfRunResult, error) {
+	if waitSeconds > 0 {
+		waitSeconds = min(waitSeconds, max(int((c.timeout-runStatusWaitMargin).Seconds()), 1))
+	}
+	raw, err := c.CallTool(ctx, "wf_run_status", WfRunStatusParams{
+		Namespace:   namespace,
+		ExecutionID: executionID,
+		WaitSeconds: waitSeconds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result WfRunResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_run_status result: %w", err)
+	}
+	return &result, nil
+}
+
+// --- wf_runs ---
+
+// WfRunsParams are the arguments for the wf_runs MCP tool.
+type WfRunsParams struct {
+	Namespace string `json:"enclave"`
+	Name      string `json:"name"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// wfRunsResult is the envelope returned by wf_runs.
+type wfRunsResult struct {
+	Runs []WfRunResult `json:"runs"`
+}
+
+// WfRuns calls the wf_runs MCP tool to list recent executions of a workflow
+// recorded by the server, newest first. limit <= 0 uses the server default.
+func (c *Client) WfRuns(ctx context.Context, namespace, name string, limit int) ([]WfRunResult, error) {
+	raw, err := c.CallTool(ctx, "wf_runs", WfRunsParams{Namespace: namespace, Name: name, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	var result wfRunsResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_runs result: %w", err)
+	}
+	return result.Runs, nil
+}
+
+// --- wf_health ---
+
+// WfHealthParams are the arguments for the wf_health MCP tool.
+type WfHealthParams struct {
+	Namespace string `json:"enclave"`
+	Name      string `json:"name"`
+	Detail    bool   `json:"detail,omitempty"`
+}
+
+// WfHealthResult is the response from wf_health. Status is the server's own
+// green/amber/red classification; Detail is the engine telemetry snapshot
+// (GET /health?detail=1) when requested.
+type WfHealthResult struct {
+	Detail    *TelemetrySnapshot `json:"detail,omitempty"`
+	Name      string             `json:"name"`
+	Namespace string             `json:"enclave"`
+	Status    string             `json:"status,omitempty"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+// TelemetrySnapshot mirrors the engine's in-memory telemetry snapshot.
+type TelemetrySnapshot struct {
+	LastError     *string          `json:"lastError"`
+	LastErrorAt   *int64           `json:"lastErrorAt"` // epoch ms
+	Status        string           `json:"status"`
+	RecentEvents  []TelemetryEvent `json:"recentEvents"`
+	TotalEvents   int64            `json:"totalEvents"`
+	ErrorCount    int64            `json:"errorCount"`
+	ErrorRate     float64          `json:"errorRate"` // node errors / total events
+	UptimeMs      int64            `json:"uptimeMs"`
+	InFlight      int              `json:"inFlight"`
+	LastRunFailed bool             `json:"lastRunFailed"`
+}
+
+// TelemetryEvent is a single engine telemetry event (engine-start,
+// node-start, node-complete, node-error, request-in, request-out, ...).
+type TelemetryEvent struct {
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Type      string         `json:"type"`
+	Timestamp int64          `json:"timestamp"` // epoch ms
+}
+
+// WfHealth calls the wf_health MCP tool for a workflow's engine telemetry snapshot.
+func (c *Client) WfHealth(ctx context.Context, namespace, name string) (*WfHealthResult, error) {
+	raw, err := c.CallTool(ctx, "wf_health", WfHealthParams{Namespace: namespace, Name: name, Detail: true})
+	if err != nil {
+		return nil, err
+	}
+	var result WfHealthResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_health result: %w", err)
+	}
+	return &result, nil
+}
+
 // --- cluster_preflight ---
 
 // ClusterPreflightParams are the arguments for the cluster_preflight MCP tool.
@@ -453,15 +665,45 @@ type WfDescribeParams struct {
 // WfDescribeResult is the response from wf_describe.
 type WfDescribeResult struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
-	Name        string            `json:"name"`
-	Namespace   string            `json:"enclave"`
-	Image       string            `json:"image,omitempty"`
-	Nodes       []PodInfo         `json:"nodes,omitempty"`
-	Triggers    []string          `json:"triggers,omitempty"`
-	Manifests   []map[string]any  `json:"manifests,omitempty"`
-	Replicas    int32             `json:"replicas"`
-	Available   int32             `json:"available"`
-	Ready       bool              `json:"ready"`
+	// Metadata is the data of the tentacle's <name>-metadata ConfigMap
+	// (readme, contract, params_schema, prompts, git_provenance, node_descriptions).
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"enclave"`
+	Image     string            `json:"image,omitempty"`
+	Nodes     []PodInfo         `json:"nodes,omitempty"`
+	Triggers  []string          `json:"triggers,omitempty"`
+	Manifests []map[string]any  `json:"manifests,omitempty"`
+	Replicas  int32             `json:"replicas"`
+	Available int32             `json:"available"`
+	Ready     bool              `json:"ready"`
+}
+
+// MetadataData returns the metadata ConfigMap data, falling back to the
+// <name>-metadata ConfigMap in Manifests for servers that do not return it
+// separately. Returns nil when the tentacle has no metadata.
+func (r *WfDescribeResult) MetadataData() map[string]string {
+	if len(r.Metadata) > 0 {
+		return r.Metadata
+	}
+	for _, m := range r.Manifests {
+		if kind, _ := m["kind"].(string); kind != "ConfigMap" {
+			continue
+		}
+		meta, _ := m["metadata"].(map[string]any)
+		if name, _ := meta["name"].(string); name != r.Name+"-metadata" {
+			continue
+		}
+		raw, _ := m["data"].(map[string]any)
+		data := make(map[string]string, len(raw))
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				data[k] = s
+			}
+		}
+		return data
+	}
+	return nil
 }
 
 // WfDescribe calls the wf_describe MCP tool to get detailed deployment info.
@@ -479,3 +721,289 @@ func (c *Client) WfDescribe(ctx context.Context, namespace, name string) (*WfDes
 	}
 	return &result, nil
 }
+
+// --- wf_lock / wf_unlock ---
+
+// WfLockParams are the arguments for the wf_lock MCP tool.
+type WfLockParams struct {
+	Namespace  string `json:"enclave"`
+	Name       string `json:"name"`
+	Holder     string `json:"holder"`  // who is deploying, shown to anyone blocked by the lock
+	LockID     string `json:"lock_id"` // unique per deploy; re-locking with the same ID renews
+	TTLSeconds int    `json:"ttl_seconds"`
+	Steal      bool   `json:"steal,omitempty"` // break an unexpired lock held by someone else
+}
+
+// DeployLock describes a held deploy lock.
+type DeployLock struct {
+	Holder     string `json:"holder"`
+	LockID     string `json:"lock_id"`
+	AcquiredAt string `json:"acquired_at"` // RFC3339
+	ExpiresAt  string `json:"expires_at"`  // RFC3339
+}
+
+// Expired reports whether the lock's TTL has passed at now. A lock without a
+// parseable expiry is treated as expired.
+func (l DeployLock) Expired(now time.Time) bool {
+	exp, err := time.Parse(time.RFC3339, l.ExpiresAt)
+	return err != nil || !now.Before(exp)
+}
+
+// DefaultLockTTLSeconds is used when wf_lock is called without a TTL.
+const DefaultLockTTLSeconds = 600
+
+// GrantLock applies the wf_lock rules to the current lock (nil when free): a
+// free, expired or own (same LockID) lock is granted; an unexpired lock held
+// by someone else only with Steal. Transports that implement locking
+// themselves share these rules.
+func GrantLock(current *DeployLock, p WfLockParams, now time.Time) WfLockResult {
+	held := current != nil && current.LockID != p.LockID && !current.Expired(now)
+	if held && !p.Steal {
+		return WfLockResult{Lock: *current}
+	}
+	ttl := p.TTLSeconds
+	if ttl <= 0 {
+		ttl = DefaultLockTTLSeconds
+	}
+	result := WfLockResult{Acquired: true, Lock: DeployLock{
+		Holder:     p.Holder,
+		LockID:     p.LockID,
+		AcquiredAt: now.UTC().Format(time.RFC3339),
+		ExpiresAt:  now.Add(time.Duration(ttl) * time.Second).UTC().Format(time.RFC3339),
+	}}
+	if current != nil && current.LockID == p.LockID {
+		result.Lock.AcquiredAt = current.AcquiredAt // a renewal
+	}
+	if held {
+		stolen := *current
+		result.StolenFrom = &stolen
+	}
+	return result
+}
+
+// WfLockResult is the response from wf_lock.
+type WfLockResult struct {
+	// Lock is the lock in force: ours when Acquired, the current holder's otherwise.
+	Lock DeployLock `json:"lock"`
+	// StolenFrom is the previous holder when an unexpired lock was broken with Steal.
+	StolenFrom *DeployLock `json:"stolen_from,omitempty"`
+	Acquired   bool        `json:"acquired"`
+}
+
+// WfUnlockParams are the arguments for the wf_unlock MCP tool.
+type WfUnlockParams struct {
+	Namespace string `json:"enclave"`
+	Name      string `json:"name"`
+	LockID    string `json:"lock_id"`
+}
+
+// WfUnlockResult is the response from wf_unlock. Released is false when the
+// lock was already gone or is now held by someone else.
+type WfUnlockResult struct {
+	Released bool `json:"released"`
+}
+
+// WfLock calls the wf_lock MCP tool to acquire (or renew) a workflow's
+// deploy lock. A lock held by someone else is not an error: the result has
+// Acquired=false and describes the holder.
+func (c *Client) WfLock(ctx context.Context, params WfLockParams) (*WfLockResult, error) {
+	raw, err := c.CallTool(ctx, "wf_lock", params)
+	if err != nil {
+		return nil, err
+	}
+	var result WfLockResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_lock result: %w", err)
+	}
+	return &result, nil
+}
+
+// WfUnlock calls the wf_unlock MCP tool to release a deploy lock held with lockID.
+func (c *Client) WfUnlock(ctx context.Context, namespace, name, lockID string) (*WfUnlockResult, error) {
+	raw, err := c.CallTool(ctx, "wf_unlock", WfUnlockParams{Namespace: namespace, Name: name, LockID: lockID})
+	if err != nil {
+		return nil, err
+	}
+	var result WfUnlockResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_unlock result: %w", err)
+	}
+	return &result, nil
+}
+
+// --- wf_suspend / wf_resume ---
+
+// AnnotationCronSchedule holds a workflow's cron trigger schedules on its
+// Deployment; the server's in-process scheduler fires runs from it.
+const AnnotationCronSchedule = "tentacular.io/cron-schedule"
+
+// Annotations on the workflow Deployment while it is suspended.
+const (
+	AnnotationSuspended             = "tentacular.io/suspended" // "true"
+	AnnotationSuspendedAt           = "tentacular.io/suspended-at"
+	AnnotationSuspendedBy           = "tentacular.io/suspended-by"
+	AnnotationSuspendedReplicas     = "tentacular.io/suspended-replicas"      // replica count restored on resume
+	AnnotationSuspendedCronSchedule = "tentacular.io/suspended-cron-schedule" // cron-schedule restored on resume
+)
+
+// WfSuspendParams are the arguments for the wf_suspend and wf_resume MCP tools.
+type WfSuspendParams struct {
+	Namespace string `json:"enclave"`
+	Name      string `json:"name"`
+	By        string `json:"by,omitempty"` // who is suspending, recorded on the workflow
+}
+
+// WfSuspendResult is the response from wf_suspend and wf_resume.
+type WfSuspendResult struct {
+	Name      string `json:"name"`
+	Namespace string `json:"enclave"`
+	// CronSchedule is the cron schedule paused by suspend or restored by
+	// resume; empty when the workflow has no cron triggers.
+	CronSchedule string `json:"cron_schedule,omitempty"`
+	Replicas     int32  `json:"replicas"` // desired replicas: 0 when suspended, the restored count when resumed
+	Suspended    bool   `json:"suspended"`
+	Changed      bool   `json:"changed"` // false when the workflow was already in that state
+}
+
+// WfSuspend calls the wf_suspend MCP tool to scale a workflow to zero and
+// pause its cron schedule without removing it.
+func (c *Client) WfSuspend(ctx context.Context, namespace, name, by string) (*WfSuspendResult, error) {
+	return c.wfSuspend(ctx, "wf_suspend", WfSuspendParams{Namespace: namespace, Name: name, By: by})
+}
+
+// WfResume calls the wf_resume MCP tool to restore a suspended workflow's
+// replicas and cron schedule.
+func (c *Client) WfResume(ctx context.Context, namespace, name string) (*WfSuspendResult, error) {
+	return c.wfSuspend(ctx, "wf_resume", WfSuspendParams{Namespace: namespace, Name: name})
+}
+
+func (c *Client) wfSuspend(ctx context.Context, tool string, params WfSuspendParams) (*WfSuspendResult, error) {
+	raw, err := c.CallTool(ctx, tool, params)
+	if err != nil {
+		return nil, err
+	}
+	var result WfSuspendResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing %s result: %w", tool, err)
+	}
+	return &result, nil
+}
+
+// --- wf_revisions / wf_record_revision ---
+
+// RevisionsConfigMapSuffix names the ConfigMap, in the workflow's namespace,
+// that holds its deploy revisions (<name>-revisions).
+const RevisionsConfigMapSuffix = "-revisions"
+
+// MaxRevisionsBytes keeps the revisions ConfigMap below the 1MiB object size
+// limit; the oldest revisions are dropped to stay under it.
+const MaxRevisionsBytes = 900 * 1024
+
+// StoredRevision is one deploy revision as kept in the cluster. Data is the
+// revision record written by tntc and is opaque to the server.
+type StoredRevision struct {
+	Data     json.RawMessage `json:"data"`
+	Revision int             `json:"revision"`
+}
+
+// WfRevisionsParams are the arguments for the wf_revisions MCP tool.
+type WfRevisionsParams struct {
+	Namespace string `json:"enclave"`
+	Name      string `json:"name"`
+}
+
+// WfRevisionsResult is the response from wf_revisions, newest first.
+type WfRevisionsResult struct {
+	Revisions []StoredRevision `json:"revisions"`
+}
+
+// WfRecordRevisionParams are the arguments for the wf_record_revision MCP tool.
+type WfRecordRevisionParams struct {
+	Namespace string          `json:"enclave"`
+	Name      string          `json:"name"`
+	Data      json.RawMessage `json:"data"`
+	Limit     int             `json:"limit,omitempty"` // revisions kept; 0 keeps all that fit
+}
+
+// WfRecordRevisionResult is the response from wf_record_revision.
+type WfRecordRevisionResult struct {
+	Revision int `json:"revision"` // the number assigned to the new revision
+	Pruned   int `json:"pruned"`   // older revisions dropped
+}
+
+// revisionKey is the ConfigMap data key of revision n.
+func revisionKey(n int) string {
+	return fmt.Sprintf("%04d.json", n)
+}
+
+// RevisionsFromData reads the revisions held in a revisions ConfigMap's
+// data, newest first. Keys that are not revision records are ignored.
+func RevisionsFromData(data map[string]string) []StoredRevision {
+	revs := make([]StoredRevision, 0, len(data))
+	for key, value := range data {
+		n, err := strconv.Atoi(strings.TrimSuffix(key, ".json"))
+		if err != nil || key != revisionKey(n) || !json.Valid([]byte(value)) {
+			continue
+		}
+		revs = append(revs, StoredRevision{Revision: n, Data: json.RawMessage(value)})
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Revision > revs[j].Revision })
+	return revs
+}
+
+// RecordRevision adds p.Data to a revisions ConfigMap's data as the revision
+// after the newest one held, then drops the oldest revisions until at most
+// p.Limit remain and the data fits in MaxRevisionsBytes. A revision that
+// cannot fit on its own is refused, leaving data unchanged. Transports that
+// store revisions themselves share these rules.
+func RecordRevision(data map[string]string, p WfRecordRevisionParams) (map[string]string, WfRecordRevisionResult, error) {
+	if len(p.Data) > MaxRevisionsBytes {
+		return data, WfRecordRevisionResult{}, fmt.Errorf("revision is %d bytes, over the %d byte limit of the revisions ConfigMap", len(p.Data), MaxRevisionsBytes)
+	}
+	revs := RevisionsFromData(data)
+	result := WfRecordRevisionResult{Revision: 1}
+	if len(revs) > 0 {
+		result.Revision = revs[0].Revision + 1
+	}
+	revs = append([]StoredRevision{{Revision: result.Revision, Data: p.Data}}, revs...)
+
+	size := 0
+	kept := make(map[string]string, len(revs))
+	for i, rev := range revs {
+		size += len(rev.Data)
+		if i > 0 && ((p.Limit > 0 && i >= p.Limit) || size > MaxRevisionsBytes) {
+			result.Pruned = len(revs) - i
+			break
+		}
+		kept[revisionKey(rev.Revision)] = string(rev.Data)
+	}
+	return kept, result, nil
+}
+
+// WfRevisions calls the wf_revisions MCP tool to read a workflow's deploy
+// revisions from its revisions ConfigMap.
+func (c *Client) WfRevisions(ctx context.Context, namespace, name string) (*WfRevisionsResult, error) {
+	raw, err := c.CallTool(ctx, "wf_revisions", WfRevisionsParams{Namespace: namespace, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	var result WfRevisionsResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_revisions result: %w", err)
+	}
+	return &result, nil
+}
+
+// WfRecordRevision calls the wf_record_revision MCP tool to append a deploy
+// revision to the workflow's revisions ConfigMap.
+func (c *Client) WfRecordRevision(ctx context.Context, params WfRecordRevisionParams) (*WfRecordRevisionResult, error) {
+	raw, err := c.CallTool(ctx, "wf_record_revision", params)
+	if err != nil {
+		return nil, err
+	}
+	var result WfRecordRevisionResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing wf_record_revision result: %w", err)
+	}
+	return &result, nil
+}
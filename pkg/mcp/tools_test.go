@@ -3,7 +3,11 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 // makeToolServer creates a test server with a single named tool that returns
@@ -178,6 +182,28 @@ func TestWfRun(t *testing.T) {
 	}
 }
 
+func TestWfRunStatus_CapsWaitBelowTimeout(t *testing.T) {
+	var waits []any
+	srv, _ := makeTestServer(t, map[string]func(map[string]any) (string, bool){
+		"wf_run_status": func(args map[string]any) (string, bool) {
+			waits = append(waits, args["wait_seconds"])
+			return `{"name":"my-wf","status":"running"}`, false
+		},
+	})
+	defer srv.Close()
+	client := NewClient(Config{Endpoint: srv.URL + "/mcp", Timeout: 20 * time.Second})
+	defer func() { _ = client.Close() }()
+
+	for _, wait := range []int{30, 10, 0} {
+		if _, err := client.WfRunStatus(context.Background(), "default", "exec-1", wait); err != nil {
+			t.Fatalf("WfRunStatus: %v", err)
+		}
+	}
+	if want := []any{float64(15), float64(10), nil}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("expected wait_seconds %v, got %v", want, waits)
+	}
+}
+
 func TestClusterPreflight(t *testing.T) {
 	h := makeToolServer(t, "enclave_preflight", ClusterPreflightResult{
 		Results: []CheckResult{
@@ -503,3 +529,41 @@ func TestClusterProfile_ToolError(t *testing.T) {
 		t.Errorf("expected IsToolError=true, got false for: %v", err)
 	}
 }
+
+func TestRecordRevision_NumbersAndPrunes(t *testing.T) {
+	var data map[string]string
+	var result WfRecordRevisionResult
+	for i := range 4 {
+		data, result, _ = RecordRevision(data, WfRecordRevisionParams{Data: json.RawMessage(fmt.Sprintf(`{"n":%d}`, i+1)), Limit: 3})
+	}
+	if result.Revision != 4 || result.Pruned != 1 {
+		t.Errorf("expected revision 4 with one pruned, got %+v", result)
+	}
+	revs := RevisionsFromData(data)
+	if len(revs) != 3 || revs[0].Revision != 4 || revs[2].Revision != 2 || string(revs[0].Data) != `{"n":4}` {
+		t.Errorf("expected revisions 4..2 newest first, got %+v", revs)
+	}
+
+	// The oldest revisions are dropped to keep the ConfigMap under its size limit.
+	big := json.RawMessage(`"` + strings.Repeat("x", MaxRevisionsBytes/2) + `"`)
+	data, _, _ = RecordRevision(data, WfRecordRevisionParams{Data: big})
+	data, result, _ = RecordRevision(data, WfRecordRevisionParams{Data: big})
+	if revs := RevisionsFromData(data); len(revs) != 1 || revs[0].Revision != 6 || result.Pruned != 4 {
+		t.Errorf("expected only the newest revision to fit, got %d revisions (%+v)", len(revs), result)
+	}
+}
+
+func TestRecordRevision_RefusesRevisionOverLimit(t *testing.T) {
+	data, _, err := RecordRevision(nil, WfRecordRevisionParams{Data: json.RawMessage(`{"n":1}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	huge := json.RawMessage(`"` + strings.Repeat("x", MaxRevisionsBytes) + `"`)
+	kept, _, err := RecordRevision(data, WfRecordRevisionParams{Data: huge})
+	if err == nil || !strings.Contains(err.Error(), "over the") {
+		t.Fatalf("expected a revision over the limit to be refused, got %v", err)
+	}
+	if revs := RevisionsFromData(kept); len(revs) != 1 || revs[0].Revision != 1 {
+		t.Errorf("expected the existing history to be kept, got %+v", revs)
+	}
+}
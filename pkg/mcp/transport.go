@@ -0,0 +1,49 @@
+package mcp
+
+import "context"
+
+// WorkflowClient is the set of workflow lifecycle operations shared by every
+// transport. *Client implements it over MCP; k8s.DirectClient implements it
+// with client-go against a kubeconfig context (tntc --direct). Commands that
+// only apply, inspect or remove workflows should depend on this interface
+// rather than on *Client.
+type WorkflowClient interface {
+	WfApply(ctx context.Context, namespace, name string, manifests []map[string]any) (*WfApplyResult, error)
+	WfRemove(ctx context.Context, namespace, name string) (*WfRemoveResult, error)
+	WfStatus(ctx context.Context, namespace, name string, detail bool) (*WfStatusResult, error)
+	WfList(ctx context.Context, namespace string) ([]WfListItem, error)
+	WfPods(ctx context.Context, namespace string) (*WfPodsResult, error)
+	WfLogs(ctx context.Context, namespace, pod string, tailLines int64) (*WfLogsResult, error)
+	WfLogsQuery(ctx context.Context, params WfLogsParams) (*WfLogsResult, error)
+	WfHealth(ctx context.Context, namespace, name string) (*WfHealthResult, error)
+}
+
+var _ WorkflowClient = (*Client)(nil)
+
+// DeployLocker is implemented by transports that can hold a per-workflow
+// deploy lock, so concurrent deploys of the same workflow are serialized.
+type DeployLocker interface {
+	WfLock(ctx context.Context, params WfLockParams) (*WfLockResult, error)
+	WfUnlock(ctx context.Context, namespace, name, lockID string) (*WfUnlockResult, error)
+}
+
+var _ DeployLocker = (*Client)(nil)
+
+// Suspender is implemented by transports that can suspend a workflow (scale
+// it to zero and suspend its triggers) and resume it.
+type Suspender interface {
+	WfSuspend(ctx context.Context, namespace, name, by string) (*WfSuspendResult, error)
+	WfResume(ctx context.Context, namespace, name string) (*WfSuspendResult, error)
+}
+
+var _ Suspender = (*Client)(nil)
+
+// RevisionKeeper is implemented by transports that keep a workflow's deploy
+// revisions in the cluster, in the <name>-revisions ConfigMap of its
+// namespace, so every user sees the same history.
+type RevisionKeeper interface {
+	WfRevisions(ctx context.Context, namespace, name string) (*WfRevisionsResult, error)
+	WfRecordRevision(ctx context.Context, params WfRecordRevisionParams) (*WfRecordRevisionResult, error)
+}
+
+var _ RevisionKeeper = (*Client)(nil)
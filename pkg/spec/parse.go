@@ -5,6 +5,7 @@ import (
 	"log"
 	"net"
 	"regexp"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -63,6 +64,10 @@ func Parse(data []byte) (*Workflow, []string) {
 		errs = append(errs, fmt.Sprintf("version must be semver (e.g., 1.0), got: %q", wf.Version))
 	}
 
+	if wf.Input != nil && len(wf.Input.Schema) == 0 {
+		errs = append(errs, "input.schema is required when input is declared")
+	}
+
 	// Triggers
 	if len(wf.Triggers) == 0 {
 		errs = append(errs, "at least one trigger is required")
@@ -140,6 +145,11 @@ func Parse(data []byte) (*Workflow, []string) {
 		}
 	}
 
+	// Autoscaling validation (optional section)
+	if wf.Deployment.Autoscaling != nil {
+		errs = append(errs, validateAutoscaling(&wf)...)
+	}
+
 	if len(errs) > 0 {
 		return nil, errs
 	}
@@ -451,3 +461,63 @@ func validateSidecars(sidecars []SidecarSpec) []string {
 
 	return errs
 }
+
+// validateAutoscaling checks deployment.autoscaling. Scaling is driven by the
+// queue triggers, so at least one is required, and the scaler needs a NATS
+// monitoring endpoint, given explicitly or derived from a nats dependency.
+func validateAutoscaling(wf *Workflow) []string {
+	a := wf.Deployment.Autoscaling
+	var errs []string
+	hasQueue := false
+	for _, t := range wf.Triggers {
+		if t.Type == "queue" {
+			hasQueue = true
+			break
+		}
+	}
+	if !hasQueue {
+		errs = append(errs, "deployment.autoscaling: requires a queue trigger")
+	}
+	if a.MaxReplicas < 1 {
+		errs = append(errs, "deployment.autoscaling.maxReplicas must be at least 1")
+	}
+	if a.Min() < 0 || a.Min() > a.MaxReplicas {
+		errs = append(errs, fmt.Sprintf("deployment.autoscaling.minReplicas must be between 0 and maxReplicas, got %d", a.Min()))
+	}
+	if a.CooldownPeriod < 0 || a.PollingInterval < 0 || a.LagThreshold < 0 {
+		errs = append(errs, "deployment.autoscaling: cooldownPeriod, pollingInterval and lagThreshold must not be negative")
+	}
+	if a.JetStream.Stream == "" {
+		errs = append(errs, "deployment.autoscaling.jetstream.stream is required")
+	}
+	if a.JetStream.MonitoringEndpoint == "" && NATSMonitoringEndpoint(wf) == "" {
+		errs = append(errs, "deployment.autoscaling.jetstream.monitoringEndpoint is required without a nats contract dependency")
+	}
+	if hasNewline(a.JetStream.MonitoringEndpoint) || hasNewline(a.JetStream.Stream) || hasNewline(a.JetStream.Account) {
+		errs = append(errs, "deployment.autoscaling.jetstream: values must not contain newlines")
+	}
+	return errs
+}
+
+// NATSMonitoringEndpoint returns the monitoring endpoint of the workflow's
+// NATS server: deployment.autoscaling.jetstream.monitoringEndpoint, else the
+// host of the first nats contract dependency (by name) on port 8222.
+func NATSMonitoringEndpoint(wf *Workflow) string {
+	if a := wf.Deployment.Autoscaling; a != nil && a.JetStream.MonitoringEndpoint != "" {
+		return a.JetStream.MonitoringEndpoint
+	}
+	if wf.Contract == nil {
+		return ""
+	}
+	names := make([]string, 0, len(wf.Contract.Dependencies))
+	for name, dep := range wf.Contract.Dependencies {
+		if dep.Protocol == "nats" && dep.Host != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return net.JoinHostPort(wf.Contract.Dependencies[names[0]].Host, "8222")
+}
@@ -91,6 +91,56 @@ edges: []
 	}
 }
 
+func TestParseInputWithoutSchema(t *testing.T) {
+	yaml := `
+name: with-input
+version: "1.0"
+triggers:
+  - type: manual
+nodes:
+  a:
+    path: ./a.ts
+    description: "Test node"
+edges: []
+input: {}
+`
+	_, errs := Parse([]byte(yaml))
+	found := false
+	for _, e := range errs {
+		if strings.Contains(e, "input.schema is required") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected 'input.schema is required' error, got: %v", errs)
+	}
+}
+
+func TestParseInputSchema(t *testing.T) {
+	yaml := `
+name: with-input
+version: "1.0"
+triggers:
+  - type: manual
+nodes:
+  a:
+    path: ./a.ts
+    description: "Test node"
+edges: []
+input:
+  schema:
+    type: object
+    required: [url]
+`
+	wf, errs := Parse([]byte(yaml))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if wf.Input == nil || wf.Input.Schema["type"] != "object" {
+		t.Errorf("expected input schema parsed, got %+v", wf.Input)
+	}
+}
+
 func TestParseInvalidName(t *testing.T) {
 	yaml := `
 name: NotKebab
@@ -1471,3 +1521,50 @@ func TestSidecarEnvValueWithNewline(t *testing.T) {
 		t.Errorf("expected 'value must not contain newlines' error, got: %v", sidecarErrs)
 	}
 }
+
+func TestParseAutoscaling(t *testing.T) {
+	base := `
+name: queue-wf
+version: "1.0"
+triggers:
+  - type: queue
+    subject: orders.created
+nodes:
+  handle:
+    path: ./nodes/handle.ts
+    description: "Test node"
+contract:
+  version: "1"
+  dependencies:
+    nats:
+      protocol: nats
+      host: nats.exo.svc.cluster.local
+      subject: orders.created
+deployment:
+  autoscaling:
+`
+	wf, errs := Parse([]byte(base + "    minReplicas: 0\n    maxReplicas: 5\n    jetstream:\n      stream: ORDERS\n"))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if a := wf.Deployment.Autoscaling; a.Min() != 0 || a.MaxReplicas != 5 {
+		t.Errorf("expected 0-5 replicas, got %d-%d", a.Min(), a.MaxReplicas)
+	}
+	if got := NATSMonitoringEndpoint(wf); got != "nats.exo.svc.cluster.local:8222" {
+		t.Errorf("expected the monitoring endpoint derived from the nats dependency, got %q", got)
+	}
+
+	_, errs = Parse([]byte(base + "    minReplicas: 3\n    maxReplicas: 2\n    jetstream: {}\n"))
+	joined := strings.Join(errs, "\n")
+	for _, want := range []string{"minReplicas must be between 0 and maxReplicas", "jetstream.stream is required"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected error %q, got %v", want, errs)
+		}
+	}
+
+	manual := strings.Replace(base, "  - type: queue\n    subject: orders.created\n", "  - type: manual\n", 1)
+	_, errs = Parse([]byte(manual + "    maxReplicas: 2\n    jetstream:\n      stream: ORDERS\n"))
+	if !strings.Contains(strings.Join(errs, "\n"), "deployment.autoscaling: requires a queue trigger") {
+		t.Errorf("expected autoscaling without a queue trigger rejected, got %v", errs)
+	}
+}
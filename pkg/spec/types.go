@@ -4,6 +4,7 @@ package spec
 type Workflow struct {
 	Metadata    *WorkflowMetadata   `yaml:"metadata,omitempty"`
 	Contract    *Contract           `yaml:"contract,omitempty"`
+	Input       *InputSpec          `yaml:"input,omitempty"`
 	Nodes       map[string]NodeSpec `yaml:"nodes"`
 	Name        string              `yaml:"name"`
 	Version     string              `yaml:"version"`
@@ -22,9 +23,49 @@ type WorkflowMetadata struct {
 	Tags        []string `yaml:"tags,omitempty"`
 }
 
+// InputSpec declares the payload a workflow accepts when triggered manually.
+// Schema is a JSON Schema (draft 2020-12 or draft-07) written in YAML;
+// tntc run, test --live and deploy validate --input payloads against it.
+type InputSpec struct {
+	Schema map[string]any `yaml:"schema"`
+}
+
 // DeploymentConfig holds deployment-specific settings embedded in workflow.yaml.
 type DeploymentConfig struct {
-	Namespace string `yaml:"namespace,omitempty"`
+	Autoscaling *AutoscalingConfig `yaml:"autoscaling,omitempty"`
+	Namespace   string             `yaml:"namespace,omitempty"`
+}
+
+// AutoscalingConfig scales a queue-triggered workflow with a KEDA ScaledObject
+// on the NATS JetStream consumer lag of its trigger subjects. It only takes
+// effect when KEDA is detected in the target cluster's profile; otherwise the
+// workflow runs a single replica.
+type AutoscalingConfig struct {
+	MinReplicas     *int            `yaml:"minReplicas,omitempty"`     // default 1; 0 allows scale-to-zero
+	JetStream       JetStreamScaler `yaml:"jetstream"`                 // scaler source
+	MaxReplicas     int             `yaml:"maxReplicas"`               // required, at least 1
+	CooldownPeriod  int             `yaml:"cooldownPeriod,omitempty"`  // seconds after the last activity before scaling to zero (KEDA default 300)
+	PollingInterval int             `yaml:"pollingInterval,omitempty"` // seconds between lag checks (KEDA default 30)
+	LagThreshold    int             `yaml:"lagThreshold,omitempty"`    // pending messages per replica (default 10)
+}
+
+// Min returns the minimum replica count, defaulting to 1.
+func (a *AutoscalingConfig) Min() int {
+	if a.MinReplicas == nil {
+		return 1
+	}
+	return *a.MinReplicas
+}
+
+// JetStreamScaler locates the JetStream stream whose consumer lag drives
+// scaling. The stream must capture the queue trigger subjects: with
+// autoscaling the engine consumes each subject through a durable consumer
+// named <workflow>-<subject>, with dots and wildcards replaced, shared by the
+// replicas as a queue group.
+type JetStreamScaler struct {
+	Stream             string `yaml:"stream"`                       // required
+	Account            string `yaml:"account,omitempty"`            // default "$G"
+	MonitoringEndpoint string `yaml:"monitoringEndpoint,omitempty"` // NATS HTTP monitoring host:port; default: the nats dependency host on 8222
 }
 
 type Trigger struct {